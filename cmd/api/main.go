@@ -21,7 +21,12 @@ func main() {
 		port = "8080"
 	}
 
-	dbStore, err := store.NewStore()
+	opts, err := store.OptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Error reading store options: %v", err)
+	}
+
+	dbStore, err := store.Open(os.Getenv("STORE"), opts)
 	if err != nil {
 		log.Fatalf("Error creating store: %v", err)
 	}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"future-app/models"
+	"future-app/store"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+func main() {
+	trainerID := flag.Int("trainer", 0, "only export the trainer's appointments")
+	userID := flag.Int("user", 0, "only export the user's appointments")
+	from := flag.String("from", "", "only export appointments ending at or after this RFC-3339 time")
+	to := flag.String("to", "", "only export appointments starting at or before this RFC-3339 time")
+	format := flag.String("format", formatJSON, "output format, json or csv")
+	output := flag.String("o", "", "file to write to instead of stdout")
+	flag.Parse()
+
+	if *format != formatJSON && *format != formatCSV {
+		log.Fatalf("Unknown format: %s", *format)
+	}
+
+	if *trainerID < 0 || *userID < 0 {
+		log.Fatalf("Trainer and user IDs must be greater than 0")
+	}
+
+	startsAt := parseTime("from", *from)
+	endsAt := parseTime("to", *to)
+	if !startsAt.IsZero() && !endsAt.IsZero() && endsAt.Before(startsAt) {
+		log.Fatalf("from must be before to")
+	}
+
+	// INFO: Unlike the server, the scripts also run without a .env file
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	opts, err := store.OptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Error reading store options: %v", err)
+	}
+
+	dbStore, err := store.NewStore(opts)
+	if err != nil {
+		log.Fatalf("Error creating store: %v", err)
+	}
+	defer dbStore.Close()
+	if err := dbStore.Init(); err != nil {
+		log.Fatalf("Error initializing store: %v", err)
+	}
+
+	appointments, err := dbStore.ExportAppointments(*trainerID, *userID, startsAt, endsAt)
+	if err != nil {
+		log.Fatalf("Error exporting appointments: %v", err)
+	}
+
+	// INFO: Series and resources are not part of the seed files, so an export
+	// keeps to the fields the seed imports
+	for _, appointment := range appointments {
+		appointment.SeriesID = nil
+		appointment.ResourceIDs = nil
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Error creating file: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := writeAppointments(w, *format, appointments); err != nil {
+		log.Fatalf("Error writing appointments: %v", err)
+	}
+
+	if *output != "" {
+		log.Printf("Exported %d appointments to %s", len(appointments), *output)
+	}
+}
+
+// parseTime reads an optional RFC-3339 flag value. An empty value is the zero
+// time, which leaves that end of the range open.
+func parseTime(name, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %s must be in RFC-3339 format", name, value)
+	}
+
+	return t
+}
+
+func writeAppointments(w io.Writer, format string, appointments []*models.Appointment) error {
+	if format == formatCSV {
+		return models.WriteAppointmentsCSV(w, appointments)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(appointments); err != nil {
+		return fmt.Errorf("Error marshalling JSON: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"future-app/store"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	command := "status"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	// INFO: Unlike the server, the scripts also run without a .env file
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	opts, err := store.OptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Error reading store options: %v", err)
+	}
+
+	dbStore, err := store.NewStore(opts)
+	if err != nil {
+		log.Fatalf("Error creating store: %v", err)
+	}
+	defer dbStore.Close()
+
+	switch command {
+	case "status":
+		printStatus(dbStore)
+	case "up":
+		applied, err := dbStore.MigrateUp(parseSteps(0))
+		for _, status := range applied {
+			fmt.Printf("Applied %d %s\n", status.Version, status.Name)
+		}
+		if err != nil {
+			log.Fatalf("Error applying migrations: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Schema is up to date")
+		}
+	case "down":
+		reverted, err := dbStore.MigrateDown(parseSteps(1))
+		for _, status := range reverted {
+			fmt.Printf("Reverted %d %s\n", status.Version, status.Name)
+		}
+		if err != nil {
+			log.Fatalf("Error reverting migrations: %v", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No migrations to revert")
+		}
+	default:
+		log.Fatalf("Unknown command: %s", command)
+	}
+}
+
+// parseSteps reads the optional step count after the command. 0 means all.
+func parseSteps(fallback int) int {
+	if len(os.Args) < 3 {
+		return fallback
+	}
+
+	steps, err := strconv.Atoi(os.Args[2])
+	if err != nil || steps < 0 {
+		log.Fatalf("Usage: migrate %s [steps]", os.Args[1])
+	}
+
+	return steps
+}
+
+func printStatus(dbStore *store.Store) {
+	statuses, err := dbStore.GetMigrationStatus()
+	if err != nil {
+		log.Fatalf("Error reading migrations: %v", err)
+	}
+
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%4d  %-28s %s\n", status.Version, status.Name, applied)
+	}
+}
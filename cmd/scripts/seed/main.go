@@ -1,26 +1,38 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"future-app/models"
 	"future-app/store"
 	"log"
 	"os"
-	"time"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
 )
 
+const holidaysFile = "holidays.json"
+
 func main() {
-	byteValue, err := os.ReadFile("appointments.json")
-	if err != nil {
-		log.Fatalf("Error reading file: %v", err)
+	command := "seed"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
 	}
 
-	var appointments []models.Appointment
-	if err = json.Unmarshal(byteValue, &appointments); err != nil {
-		log.Fatalf("Error unmarshalling JSON: %v", err)
+	// INFO: Unlike the server, the scripts also run without a .env file
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
+
+	opts, err := store.OptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Error reading store options: %v", err)
 	}
 
-	dbStore, err := store.NewStore()
+	dbStore, err := store.NewStore(opts)
 	if err != nil {
 		log.Fatalf("Error creating store: %v", err)
 	}
@@ -29,23 +41,124 @@ func main() {
 		log.Fatalf("Error initializing store: %v", err)
 	}
 
-	for _, appointment := range appointments {
-		query := `
-        INSERT INTO appointments (id, user_id, trainer_id, starts_at, ends_at)
-        VALUES ($1, $2, $3, $4, $5)
-        `
+	switch command {
+	case "seed":
+		// INFO: IDs are assigned in file order, so the files reference users and trainers by position
+		seedUsers(dbStore)
+		seedTrainers(dbStore)
+		seedAppointments(dbStore, "appointments.json")
+
+		if _, err := os.Stat(holidaysFile); err == nil {
+			importHolidays(dbStore, holidaysFile)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.Fatalf("Error reading file: %v", err)
+		}
+	case "holidays":
+		if len(os.Args) < 3 {
+			log.Fatalf("Usage: seed holidays <file>")
+		}
+		importHolidays(dbStore, os.Args[2])
+	case "appointments":
+		if len(os.Args) < 3 {
+			log.Fatalf("Usage: seed appointments <file>")
+		}
+		seedAppointments(dbStore, os.Args[2])
+	default:
+		log.Fatalf("Unknown command: %s", command)
+	}
+}
+
+func seedUsers(dbStore store.Repository) {
+	byteValue, err := os.ReadFile("users.json")
+	if err != nil {
+		log.Fatalf("Error reading file: %v", err)
+	}
+
+	var users []models.User
+	if err = json.Unmarshal(byteValue, &users); err != nil {
+		log.Fatalf("Error unmarshalling JSON: %v", err)
+	}
 
-		_, err := dbStore.DB.Exec(
-			query,
-			appointment.ID,
-			appointment.UserID,
-			appointment.TrainerID,
-			(appointment.StartsAt).Format(time.RFC3339),
-			(appointment.EndsAt).Format(time.RFC3339),
-		)
+	for _, user := range users {
+		if _, err := dbStore.CreateUser(&user); err != nil {
+			log.Fatalf("Error creating user: %v", err)
+		}
+	}
+}
 
+func seedTrainers(dbStore store.Repository) {
+	byteValue, err := os.ReadFile("trainers.json")
+	if err != nil {
+		log.Fatalf("Error reading file: %v", err)
+	}
+
+	var trainers []models.Trainer
+	if err = json.Unmarshal(byteValue, &trainers); err != nil {
+		log.Fatalf("Error unmarshalling JSON: %v", err)
+	}
+
+	for _, trainer := range trainers {
+		if _, err := dbStore.CreateTrainer(&trainer); err != nil {
+			log.Fatalf("Error creating trainer: %v", err)
+		}
+	}
+}
+
+// seedAppointments imports the appointments in a JSON file, or a CSV file
+// written by the export script. They are imported all at once without the
+// booking rules, so a failed seed leaves no appointments behind and an export
+// seeds back whatever the trainers' settings. Appointments keep their IDs and
+// status, and seeding an ID that is already taken fails.
+func seedAppointments(dbStore store.Repository, path string) {
+	byteValue, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Error reading file: %v", err)
+	}
+
+	var entries []models.Appointment
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		if entries, err = models.ReadAppointmentsCSV(bytes.NewReader(byteValue)); err != nil {
+			log.Fatalf("Error reading CSV: %v", err)
+		}
+	} else if err = json.Unmarshal(byteValue, &entries); err != nil {
+		log.Fatalf("Error unmarshalling JSON: %v", err)
+	}
+
+	appointments := make([]*models.Appointment, 0, len(entries))
+	for i := range entries {
+		appointments = append(appointments, &entries[i])
+	}
+
+	if _, err := dbStore.ImportAppointments(appointments); err != nil {
+		log.Fatalf("Error importing appointments: %v", err)
+	}
+
+	log.Printf("Seeded %d appointments from %s", len(appointments), path)
+}
+
+func importHolidays(dbStore store.Repository, path string) {
+	byteValue, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Error reading file: %v", err)
+	}
+
+	var entries []models.Holiday
+	if err = json.Unmarshal(byteValue, &entries); err != nil {
+		log.Fatalf("Error unmarshalling JSON: %v", err)
+	}
+
+	holidays := make([]*models.Holiday, 0, len(entries))
+	for _, entry := range entries {
+		holiday, err := models.NewHoliday(entry.Date, entry.Name, entry.StartTime, entry.EndTime)
 		if err != nil {
-			log.Fatalf("Error creating appointment: %v", err)
+			log.Fatalf("Error validating holiday %s: %v", entry.Date, err)
 		}
+		holidays = append(holidays, holiday)
 	}
+
+	if _, err := dbStore.ImportHolidays(holidays); err != nil {
+		log.Fatalf("Error importing holidays: %v", err)
+	}
+
+	log.Printf("Imported %d holidays from %s", len(holidays), path)
 }
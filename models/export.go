@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// AppointmentCSVHeader is the first row of an appointment export. The columns
+// are named after the appointment's JSON fields.
+var AppointmentCSVHeader = []string{"id", "user_id", "trainer_id", "starts_at", "ends_at", "status"}
+
+// WriteAppointmentsCSV writes the appointments as CSV with a header row. Times
+// are written in RFC-3339 format in the offset they carry.
+func WriteAppointmentsCSV(w io.Writer, appointments []*Appointment) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(AppointmentCSVHeader); err != nil {
+		return err
+	}
+
+	for _, appointment := range appointments {
+		if err := writer.Write([]string{
+			strconv.Itoa(appointment.ID),
+			strconv.Itoa(appointment.UserID),
+			strconv.Itoa(appointment.TrainerID),
+			appointment.StartsAt.Format(time.RFC3339),
+			appointment.EndsAt.Format(time.RFC3339),
+			appointment.Status,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadAppointmentsCSV reads appointments written by WriteAppointmentsCSV. The
+// header row must match AppointmentCSVHeader.
+func ReadAppointmentsCSV(r io.Reader) ([]Appointment, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Equal(header, AppointmentCSVHeader) {
+		return nil, fmt.Errorf("CSV header must be %v", AppointmentCSVHeader)
+	}
+
+	appointments := make([]Appointment, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		appointment, err := parseAppointmentRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", line, err)
+		}
+
+		appointments = append(appointments, appointment)
+	}
+
+	return appointments, nil
+}
+
+func parseAppointmentRecord(record []string) (Appointment, error) {
+	var appointment Appointment
+	var err error
+
+	if appointment.ID, err = strconv.Atoi(record[0]); err != nil {
+		return appointment, fmt.Errorf("Invalid id: %s", record[0])
+	}
+
+	if appointment.UserID, err = strconv.Atoi(record[1]); err != nil {
+		return appointment, fmt.Errorf("Invalid user_id: %s", record[1])
+	}
+
+	if appointment.TrainerID, err = strconv.Atoi(record[2]); err != nil {
+		return appointment, fmt.Errorf("Invalid trainer_id: %s", record[2])
+	}
+
+	if appointment.StartsAt, err = time.Parse(time.RFC3339, record[3]); err != nil {
+		return appointment, fmt.Errorf("Invalid starts_at: %s", record[3])
+	}
+
+	if appointment.EndsAt, err = time.Parse(time.RFC3339, record[4]); err != nil {
+		return appointment, fmt.Errorf("Invalid ends_at: %s", record[4])
+	}
+
+	switch record[5] {
+	case AppointmentStatusScheduled, AppointmentStatusCancelled:
+		appointment.Status = record[5]
+	default:
+		return appointment, fmt.Errorf("Invalid status: %s", record[5])
+	}
+
+	return appointment, nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppointmentsCSV(t *testing.T) {
+	tz := DefaultLocation()
+	startsAt := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+	appointments := []*Appointment{
+		{ID: 1, UserID: 1, TrainerID: 2, StartsAt: startsAt, EndsAt: startsAt.Add(time.Minute * 30), Status: AppointmentStatusScheduled},
+		{ID: 4, UserID: 3, TrainerID: 2, StartsAt: startsAt.Add(time.Hour), EndsAt: startsAt.Add(time.Hour * 2), Status: AppointmentStatusCancelled},
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, WriteAppointmentsCSV(&buf, appointments))
+		assert.Equal(t, `id,user_id,trainer_id,starts_at,ends_at,status
+1,1,2,2030-07-08T09:00:00-07:00,2030-07-08T09:30:00-07:00,scheduled
+4,3,2,2030-07-08T10:00:00-07:00,2030-07-08T11:00:00-07:00,cancelled
+`, buf.String())
+
+		read, err := ReadAppointmentsCSV(&buf)
+		if assert.NoError(t, err) && assert.Len(t, read, 2) {
+			for i, appointment := range read {
+				assert.Equal(t, appointments[i].ID, appointment.ID)
+				assert.Equal(t, appointments[i].UserID, appointment.UserID)
+				assert.Equal(t, appointments[i].TrainerID, appointment.TrainerID)
+				assert.True(t, appointments[i].StartsAt.Equal(appointment.StartsAt))
+				assert.True(t, appointments[i].EndsAt.Equal(appointment.EndsAt))
+				assert.Equal(t, appointments[i].Status, appointment.Status)
+			}
+		}
+	})
+
+	t.Run("Header only", func(t *testing.T) {
+		read, err := ReadAppointmentsCSV(strings.NewReader("id,user_id,trainer_id,starts_at,ends_at,status\n"))
+		assert.NoError(t, err)
+		assert.Empty(t, read)
+	})
+
+	testCases := []struct {
+		name   string
+		input  string
+		errMsg string
+	}{
+		{name: "empty file", input: "", errMsg: "CSV file is empty"},
+		{name: "wrong header", input: "id,user,trainer\n", errMsg: "CSV header must be [id user_id trainer_id starts_at ends_at status]"},
+		{name: "invalid user", input: "id,user_id,trainer_id,starts_at,ends_at,status\n1,one,2,2030-07-08T09:00:00Z,2030-07-08T09:30:00Z,scheduled\n", errMsg: "Line 2: Invalid user_id: one"},
+		{name: "invalid time", input: "id,user_id,trainer_id,starts_at,ends_at,status\n1,1,2,2030-07-08 09:00,2030-07-08T09:30:00Z,scheduled\n", errMsg: "Line 2: Invalid starts_at: 2030-07-08 09:00"},
+		{name: "invalid status", input: "id,user_id,trainer_id,starts_at,ends_at,status\n1,1,2,2030-07-08T09:00:00Z,2030-07-08T09:30:00Z,done\n", errMsg: "Line 2: Invalid status: done"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadAppointmentsCSV(strings.NewReader(tc.input))
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
@@ -1,18 +1,40 @@
 package models
 
-import "time"
+import (
+	"sync"
+	"time"
+	_ "time/tzdata"
+)
 
-const GLOBAL_TZ = "PST"
-const GLOBAL_TZ_OFFSET = -8 * 60 * 60
+const DEFAULT_TZ = "America/Los_Angeles"
 
-func ConvertToFixedTZ(t time.Time) time.Time {
-	return t.In(time.FixedZone(GLOBAL_TZ, GLOBAL_TZ_OFFSET))
-}
+var locations sync.Map
 
-func ParseDateStr(dateStr string) (time.Time, error) {
-	parsedDate, err := time.Parse(time.RFC3339, dateStr)
+// LoadLocation caches loaded locations so times in the same zone share a
+// *time.Location.
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
 	if err != nil {
-		return time.Time{}, err
+		return nil, err
 	}
-	return ConvertToFixedTZ(parsedDate), nil
+
+	actual, _ := locations.LoadOrStore(name, loc)
+	return actual.(*time.Location), nil
+}
+
+func DefaultLocation() *time.Location {
+	loc, _ := LoadLocation(DEFAULT_TZ)
+	return loc
+}
+
+func ConvertToTZ(t time.Time, loc *time.Location) time.Time {
+	return t.In(loc)
+}
+
+func ParseDateStr(dateStr string) (time.Time, error) {
+	return time.Parse(time.RFC3339, dateStr)
 }
@@ -7,32 +7,64 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestConvertToFixedTZ(t *testing.T) {
+func TestLoadLocation(t *testing.T) {
+	t.Run("Valid location", func(t *testing.T) {
+		loc, err := LoadLocation("America/New_York")
+		assert.NoError(t, err)
+		assert.Equal(t, "America/New_York", loc.String())
+
+		// INFO: Same location is reused
+		cached, err := LoadLocation("America/New_York")
+		assert.NoError(t, err)
+		assert.Same(t, loc, cached)
+	})
+
+	t.Run("Invalid location", func(t *testing.T) {
+		_, err := LoadLocation("Mars/Olympus_Mons")
+		assert.Error(t, err)
+	})
+}
+
+func TestConvertToTZ(t *testing.T) {
+	la := DefaultLocation()
+
 	testCases := []struct {
 		name     string
 		input    time.Time
-		expected time.Time
+		expected string
 	}{
 		{
 			name:     "UTC to PST",
 			input:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: time.Date(2019, 12, 31, 16, 0, 0, 0, time.FixedZone("PST", -8*60*60)),
+			expected: "2019-12-31T16:00:00-08:00",
 		},
 		{
-			name:     "PST to PST",
-			input:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("PST", -8*60*60)),
-			expected: time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("PST", -8*60*60)),
+			name:     "UTC to PDT",
+			input:    time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC),
+			expected: "2020-06-30T17:00:00-07:00",
 		},
 		{
-			name:     "PDT to PST",
+			name:     "Fixed offset to PST",
 			input:    time.Date(2020, 1, 1, 1, 0, 0, 0, time.FixedZone("PDT", -7*60*60)),
-			expected: time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("PST", -8*60*60)),
+			expected: "2020-01-01T00:00:00-08:00",
+		},
+		{
+			name:     "Before DST starts",
+			input:    time.Date(2020, 3, 8, 9, 59, 0, 0, time.UTC),
+			expected: "2020-03-08T01:59:00-08:00",
+		},
+		{
+			name:     "After DST starts",
+			input:    time.Date(2020, 3, 8, 10, 0, 0, 0, time.UTC),
+			expected: "2020-03-08T03:00:00-07:00",
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			assert.Equal(t, tc.expected, ConvertToFixedTZ(tc.input))
+			converted := ConvertToTZ(tc.input, la)
+			assert.Equal(t, tc.expected, converted.Format(time.RFC3339))
+			assert.True(t, tc.input.Equal(converted))
 		})
 	}
 }
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+const (
+	AppointmentActionCreated     = "created"
+	AppointmentActionUpdated     = "updated"
+	AppointmentActionRescheduled = "rescheduled"
+	AppointmentActionCancelled   = "cancelled"
+)
+
+// ActorSystem is recorded for changes made without an actor, such as seeding
+// or waitlist offers lapsing outside of a request.
+const ActorSystem = "system"
+
+// Audit identifies who made a change and the request it was made in.
+type Audit struct {
+	Actor     string
+	RequestID string
+}
+
+// AppointmentChange is one entry in an appointment's history. Before is empty
+// for the change that created the appointment.
+type AppointmentChange struct {
+	ID            int          `json:"id"`
+	AppointmentID int          `json:"appointment_id"`
+	Action        string       `json:"action"`
+	Actor         string       `json:"actor"`
+	RequestID     string       `json:"request_id"`
+	ChangedAt     time.Time    `json:"changed_at"`
+	Before        *Appointment `json:"before"`
+	After         *Appointment `json:"after"`
+}
+
+// AppointmentAction names the change between two states of an appointment.
+func AppointmentAction(before, after *Appointment) string {
+	switch {
+	case before == nil:
+		return AppointmentActionCreated
+	case before.Status != AppointmentStatusCancelled && after.Status == AppointmentStatusCancelled:
+		return AppointmentActionCancelled
+	case !before.StartsAt.Equal(after.StartsAt) || !before.EndsAt.Equal(after.EndsAt):
+		return AppointmentActionRescheduled
+	}
+	return AppointmentActionUpdated
+}
+
+func (c *AppointmentChange) In(loc *time.Location) *AppointmentChange {
+	change := *c
+	change.ChangedAt = ConvertToTZ(c.ChangedAt, loc)
+	if c.Before != nil {
+		change.Before = c.Before.In(loc)
+	}
+	if c.After != nil {
+		change.After = c.After.In(loc)
+	}
+	return &change
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppointmentAction(t *testing.T) {
+	startsAt := time.Date(2030, 7, 8, 9, 0, 0, 0, time.UTC)
+	scheduled := &Appointment{ID: 1, UserID: 1, TrainerID: 1, StartsAt: startsAt, EndsAt: startsAt.Add(time.Minute * 30), Status: AppointmentStatusScheduled}
+
+	cancelled := *scheduled
+	cancelled.Status = AppointmentStatusCancelled
+
+	moved := *scheduled
+	moved.StartsAt = startsAt.Add(time.Hour)
+	moved.EndsAt = moved.StartsAt.Add(time.Minute * 30)
+
+	sameInstant := *scheduled
+	sameInstant.StartsAt = scheduled.StartsAt.In(time.FixedZone("UTC+9", 9*60*60))
+
+	withResources := *scheduled
+	withResources.ResourceIDs = []int{1}
+
+	testCases := []struct {
+		name     string
+		before   *Appointment
+		after    *Appointment
+		expected string
+	}{
+		{name: "new appointment", before: nil, after: scheduled, expected: AppointmentActionCreated},
+		{name: "cancelled", before: scheduled, after: &cancelled, expected: AppointmentActionCancelled},
+		{name: "moved", before: scheduled, after: &moved, expected: AppointmentActionRescheduled},
+		{name: "same instant in another offset", before: scheduled, after: &sameInstant, expected: AppointmentActionUpdated},
+		{name: "other fields", before: scheduled, after: &withResources, expected: AppointmentActionUpdated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, AppointmentAction(tc.before, tc.after))
+		})
+	}
+}
+
+func TestAppointmentChangeIn(t *testing.T) {
+	tz := DefaultLocation()
+	changedAt := time.Date(2030, 7, 1, 12, 0, 0, 0, time.UTC)
+	startsAt := time.Date(2030, 7, 8, 9, 0, 0, 0, time.UTC)
+	after := &Appointment{ID: 1, StartsAt: startsAt, EndsAt: startsAt.Add(time.Minute * 30)}
+
+	change := &AppointmentChange{ID: 1, ChangedAt: changedAt, After: after}
+	converted := change.In(tz)
+
+	assert.Equal(t, tz, converted.ChangedAt.Location())
+	assert.Nil(t, converted.Before)
+	assert.Equal(t, tz, converted.After.StartsAt.Location())
+	assert.True(t, converted.After.StartsAt.Equal(startsAt))
+
+	// INFO: The original change is left untouched
+	assert.Equal(t, time.UTC, change.After.StartsAt.Location())
+}
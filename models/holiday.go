@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const HolidayDateFormat = "2006-01-02"
+
+// Holiday is an organization-wide closure. It applies to the calendar date in
+// each trainer's time zone. Holidays without start and end times close the
+// whole day.
+type Holiday struct {
+	ID        int    `json:"id"`
+	Date      string `json:"date"`
+	Name      string `json:"name"`
+	StartTime string `json:"start_time,omitempty"`
+	EndTime   string `json:"end_time,omitempty"`
+}
+
+func NewHoliday(date, name, startTime, endTime string) (*Holiday, error) {
+	parsedDate, err := time.Parse(HolidayDateFormat, date)
+	if err != nil {
+		return nil, errors.New("Date must be in YYYY-MM-DD format")
+	}
+
+	if name == "" {
+		return nil, errors.New("Name is required")
+	}
+
+	if (startTime == "") != (endTime == "") {
+		return nil, errors.New("Partial day holidays must have both a start time and an end time")
+	}
+
+	holiday := &Holiday{
+		Date: parsedDate.Format(HolidayDateFormat),
+		Name: name,
+	}
+
+	if startTime != "" {
+		// INFO: Partial closures follow the same rules as working hours
+		closure, err := NewWorkingHours(int(parsedDate.Weekday()), startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+
+		holiday.StartTime = closure.StartTime
+		holiday.EndTime = closure.EndTime
+	}
+
+	return holiday, nil
+}
+
+func (h *Holiday) AllDay() bool {
+	return h.StartTime == "" && h.EndTime == ""
+}
+
+// On returns the closure on the holiday's date in the given location.
+func (h *Holiday) On(loc *time.Location) Timeslot {
+	date, _ := time.ParseInLocation(HolidayDateFormat, h.Date, loc)
+
+	if h.AllDay() {
+		return NewTimeslot(date, date.AddDate(0, 0, 1))
+	}
+
+	startsAt, endsAt := WorkingHours{StartTime: h.StartTime, EndTime: h.EndTime}.On(date)
+	return NewTimeslot(startsAt, endsAt)
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHoliday(t *testing.T) {
+	testCases := []struct {
+		name      string
+		date      string
+		label     string
+		startTime string
+		endTime   string
+		hasErr    bool
+		errMsg    string
+		expected  *Holiday
+	}{
+		{
+			name:     "full day",
+			date:     "2030-11-28",
+			label:    "Thanksgiving",
+			expected: &Holiday{Date: "2030-11-28", Name: "Thanksgiving"},
+		},
+		{
+			name:      "partial day",
+			date:      "2030-12-24",
+			label:     "Christmas Eve",
+			startTime: "14:00",
+			endTime:   "00:00",
+			expected:  &Holiday{Date: "2030-12-24", Name: "Christmas Eve", StartTime: "14:00", EndTime: "00:00"},
+		},
+		{
+			name:   "invalid date",
+			date:   "11/28/2030",
+			label:  "Thanksgiving",
+			hasErr: true,
+			errMsg: "Date must be in YYYY-MM-DD format",
+		},
+		{
+			name:   "missing name",
+			date:   "2030-11-28",
+			hasErr: true,
+			errMsg: "Name is required",
+		},
+		{
+			name:      "missing end time",
+			date:      "2030-12-24",
+			label:     "Christmas Eve",
+			startTime: "14:00",
+			hasErr:    true,
+			errMsg:    "Partial day holidays must have both a start time and an end time",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			holiday, err := NewHoliday(tc.date, tc.label, tc.startTime, tc.endTime)
+			if tc.hasErr {
+				assert.Error(t, err)
+				assert.Nil(t, holiday)
+				assert.Equal(t, tc.errMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, holiday)
+			}
+		})
+	}
+}
+
+func TestHolidayOn(t *testing.T) {
+	la := DefaultLocation()
+	london, _ := LoadLocation("Europe/London")
+
+	t.Run("Full day in trainer's time zone", func(t *testing.T) {
+		holiday := &Holiday{Date: "2030-11-28", Name: "Thanksgiving"}
+
+		closure := holiday.On(la)
+		assert.Equal(t, time.Date(2030, 11, 28, 0, 0, 0, 0, la), closure.StartsAt)
+		assert.Equal(t, time.Date(2030, 11, 29, 0, 0, 0, 0, la), closure.EndsAt)
+
+		closure = holiday.On(london)
+		assert.Equal(t, time.Date(2030, 11, 28, 0, 0, 0, 0, london), closure.StartsAt)
+	})
+
+	t.Run("Partial day", func(t *testing.T) {
+		holiday := &Holiday{Date: "2030-12-24", Name: "Christmas Eve", StartTime: "14:00", EndTime: "00:00"}
+
+		closure := holiday.On(la)
+		assert.Equal(t, time.Date(2030, 12, 24, 14, 0, 0, 0, la), closure.StartsAt)
+		assert.Equal(t, time.Date(2030, 12, 25, 0, 0, 0, 0, la), closure.EndsAt)
+	})
+}
@@ -2,18 +2,30 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
+// SlotInterval is the granularity at which appointments can start.
+const SlotInterval = 30 * time.Minute
+
+const (
+	AppointmentStatusScheduled = "scheduled"
+	AppointmentStatusCancelled = "cancelled"
+)
+
 type Appointment struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	TrainerID int       `json:"trainer_id"`
-	StartsAt  time.Time `json:"starts_at"`
-	EndsAt    time.Time `json:"ends_at"`
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	TrainerID   int       `json:"trainer_id"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	Status      string    `json:"status"`
+	SeriesID    *int      `json:"series_id,omitempty"`
+	ResourceIDs []int     `json:"resource_ids,omitempty"`
 }
 
-func NewAppointment(userID, trainerID int, startsAt, endsAt time.Time) (*Appointment, error) {
+func NewAppointment(userID, trainerID int, startsAt, endsAt time.Time, settings *TrainerSettings, schedule WeeklySchedule) (*Appointment, error) {
 	if userID < 1 {
 		return nil, errors.New("UserID must be greater than 0")
 	}
@@ -22,8 +34,8 @@ func NewAppointment(userID, trainerID int, startsAt, endsAt time.Time) (*Appoint
 		return nil, errors.New("TrainerID must be greater than 0")
 	}
 
-	startsAt = ConvertToFixedTZ(startsAt)
-	endsAt = ConvertToFixedTZ(endsAt)
+	startsAt = ConvertToTZ(startsAt, settings.Location())
+	endsAt = ConvertToTZ(endsAt, settings.Location())
 
 	if startsAt.Local().Before(time.Now().Add(time.Hour)) {
 		return nil, errors.New("Appointments must be scheduled at least 1 hour in advance")
@@ -33,32 +45,16 @@ func NewAppointment(userID, trainerID int, startsAt, endsAt time.Time) (*Appoint
 		return nil, errors.New("Appointment start time must be before end time")
 	}
 
-	if startsAt.Hour() < 8 || startsAt.Hour() >= 17 {
-		return nil, errors.New("Appointment must be scheduled between 8am and 5pm PST")
-	}
-
-	if endsAt.Hour() < 8 || endsAt.Hour() > 17 {
-		return nil, errors.New("Appointment must be scheduled between 8am and 5pm PST")
-	}
-
-	if int(startsAt.Weekday()) < 1 || int(startsAt.Weekday()) > 5 {
-		return nil, errors.New("Appointment must be scheduled between Monday and Friday PST")
-	}
-
-	if int(endsAt.Weekday()) < 1 || int(endsAt.Weekday()) > 5 {
-		return nil, errors.New("Appointment must be scheduled between Monday and Friday PST")
+	if !schedule.Contains(startsAt, endsAt) {
+		return nil, errors.New("Appointment must be scheduled within the trainer's working hours")
 	}
 
-	if startsAt.Minute() != 0 && startsAt.Minute() != 30 {
-		return nil, errors.New("Appointment must be scheduled on the hour or half hour PST")
+	if startsAt.Minute()%30 != 0 || startsAt.Second() != 0 {
+		return nil, errors.New("Appointment must be scheduled on the hour or half hour")
 	}
 
-	if endsAt.Minute() != 0 && endsAt.Minute() != 30 {
-		return nil, errors.New("Appointment must be scheduled on the hour or half hour PST")
-	}
-
-	if !startsAt.Add(time.Minute * 30).Equal(endsAt) {
-		return nil, errors.New("Appointment must be scheduled in 30-minute increments")
+	if !settings.AllowsDuration(endsAt.Sub(startsAt)) {
+		return nil, fmt.Errorf("Appointment must be one of %s minutes long", settings.DurationsString())
 	}
 
 	return &Appointment{
@@ -66,9 +62,41 @@ func NewAppointment(userID, trainerID int, startsAt, endsAt time.Time) (*Appoint
 		TrainerID: trainerID,
 		StartsAt:  startsAt,
 		EndsAt:    endsAt,
+		Status:    AppointmentStatusScheduled,
 	}, nil
 }
 
+func (a *Appointment) Timeslot() Timeslot {
+	return NewTimeslot(a.StartsAt, a.EndsAt)
+}
+
+func (a *Appointment) In(loc *time.Location) *Appointment {
+	appointment := *a
+	appointment.StartsAt = ConvertToTZ(a.StartsAt, loc)
+	appointment.EndsAt = ConvertToTZ(a.EndsAt, loc)
+	return &appointment
+}
+
+// ProfileSummary is the part of a user or trainer embedded in other responses.
+type ProfileSummary struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+// AppointmentDetails is an appointment with its user and trainer.
+type AppointmentDetails struct {
+	Appointment
+	User    ProfileSummary `json:"user"`
+	Trainer ProfileSummary `json:"trainer"`
+}
+
+func (d *AppointmentDetails) In(loc *time.Location) *AppointmentDetails {
+	details := *d
+	details.Appointment = *d.Appointment.In(loc)
+	return &details
+}
+
 type Timeslot struct {
 	StartsAt time.Time `json:"starts_at"`
 	EndsAt   time.Time `json:"ends_at"`
@@ -76,7 +104,39 @@ type Timeslot struct {
 
 func NewTimeslot(startsAt, endsAt time.Time) Timeslot {
 	return Timeslot{
-		StartsAt: ConvertToFixedTZ(startsAt),
-		EndsAt:   ConvertToFixedTZ(endsAt),
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
 	}
 }
+
+func (t Timeslot) Overlaps(startsAt, endsAt time.Time) bool {
+	return t.StartsAt.Before(endsAt) && t.EndsAt.After(startsAt)
+}
+
+func (t Timeslot) In(loc *time.Location) Timeslot {
+	return NewTimeslot(ConvertToTZ(t.StartsAt, loc), ConvertToTZ(t.EndsAt, loc))
+}
+
+// OpenTimeslot is a trainer's timeslot with the seats still open in it.
+type OpenTimeslot struct {
+	Timeslot
+	Seats int `json:"seats"`
+}
+
+func (o OpenTimeslot) In(loc *time.Location) OpenTimeslot {
+	return OpenTimeslot{Timeslot: o.Timeslot.In(loc), Seats: o.Seats}
+}
+
+// AvailableTimeslot is a timeslot with the trainers who are free for all of it
+// and the seats open across them.
+type AvailableTimeslot struct {
+	Timeslot
+	TrainerIDs []int `json:"trainer_ids"`
+	Seats      int   `json:"seats"`
+}
+
+func (a *AvailableTimeslot) In(loc *time.Location) *AvailableTimeslot {
+	timeslot := *a
+	timeslot.Timeslot = a.Timeslot.In(loc)
+	return &timeslot
+}
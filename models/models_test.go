@@ -8,7 +8,7 @@ import (
 )
 
 func TestNewAppointment(t *testing.T) {
-	tz := time.FixedZone(GLOBAL_TZ, GLOBAL_TZ_OFFSET)
+	tz := DefaultLocation()
 	now := time.Now().In(tz)
 	past := time.Date(2020, 7, 13, 0, 0, 0, 0, tz)  // Monday midnight
 	future := time.Date(2030, 7, 8, 0, 0, 0, 0, tz) // Monday midnight
@@ -35,6 +35,7 @@ func TestNewAppointment(t *testing.T) {
 				TrainerID: 1,
 				StartsAt:  future.Add(time.Hour * 8),
 				EndsAt:    future.Add(time.Hour * 8).Add(time.Minute * 30),
+				Status:    AppointmentStatusScheduled,
 			},
 		},
 		{
@@ -49,6 +50,7 @@ func TestNewAppointment(t *testing.T) {
 				TrainerID: 1,
 				StartsAt:  future.Add(time.Hour * 24 * 4).Add(time.Hour * 16).Add(time.Minute * 30),
 				EndsAt:    future.Add(time.Hour * 24 * 4).Add(time.Hour * 17),
+				Status:    AppointmentStatusScheduled,
 			},
 		},
 		{
@@ -112,7 +114,7 @@ func TestNewAppointment(t *testing.T) {
 			startsAt:  future.Add(time.Hour * 7).Add(time.Minute * 30),
 			endsAt:    future.Add(time.Hour * 8),
 			hasErr:    true,
-			errMsg:    "Appointment must be scheduled between 8am and 5pm PST",
+			errMsg:    "Appointment must be scheduled within the trainer's working hours",
 		},
 		{
 			name:      "end time outside business hours",
@@ -121,7 +123,7 @@ func TestNewAppointment(t *testing.T) {
 			startsAt:  future.Add(time.Hour * 17),
 			endsAt:    future.Add(time.Hour * 17).Add(time.Minute * 30),
 			hasErr:    true,
-			errMsg:    "Appointment must be scheduled between 8am and 5pm PST",
+			errMsg:    "Appointment must be scheduled within the trainer's working hours",
 		},
 		{
 			name:      "appointment on weekend",
@@ -130,7 +132,7 @@ func TestNewAppointment(t *testing.T) {
 			startsAt:  future.Add(-time.Hour * 24).Add(time.Hour * 8),
 			endsAt:    future.Add(-time.Hour * 24).Add(time.Hour * 8).Add(time.Minute * 30),
 			hasErr:    true,
-			errMsg:    "Appointment must be scheduled between Monday and Friday PST",
+			errMsg:    "Appointment must be scheduled within the trainer's working hours",
 		},
 		{
 			name:      "appointment not on the hour or half hour",
@@ -139,22 +141,61 @@ func TestNewAppointment(t *testing.T) {
 			startsAt:  future.Add(time.Hour * 8).Add(time.Minute * 15),
 			endsAt:    future.Add(time.Hour * 8).Add(time.Minute * 45),
 			hasErr:    true,
-			errMsg:    "Appointment must be scheduled on the hour or half hour PST",
+			errMsg:    "Appointment must be scheduled on the hour or half hour",
 		},
 		{
-			name:      "appointment not in 30-minute increments",
+			name:      "valid 45-minute appointment",
+			userID:    1,
+			trainerID: 1,
+			startsAt:  future.Add(time.Hour * 8).Add(time.Minute * 30),
+			endsAt:    future.Add(time.Hour * 9).Add(time.Minute * 15),
+			hasErr:    false,
+			expected: &Appointment{
+				UserID:    1,
+				TrainerID: 1,
+				StartsAt:  future.Add(time.Hour * 8).Add(time.Minute * 30),
+				EndsAt:    future.Add(time.Hour * 9).Add(time.Minute * 15),
+				Status:    AppointmentStatusScheduled,
+			},
+		},
+		{
+			name:      "valid 90-minute appointment",
+			userID:    1,
+			trainerID: 1,
+			startsAt:  future.Add(time.Hour * 15).Add(time.Minute * 30),
+			endsAt:    future.Add(time.Hour * 17),
+			hasErr:    false,
+			expected: &Appointment{
+				UserID:    1,
+				TrainerID: 1,
+				StartsAt:  future.Add(time.Hour * 15).Add(time.Minute * 30),
+				EndsAt:    future.Add(time.Hour * 17),
+				Status:    AppointmentStatusScheduled,
+			},
+		},
+		{
+			name:      "appointment with unsupported duration",
 			userID:    1,
 			trainerID: 1,
 			startsAt:  future.Add(time.Hour * 8),
-			endsAt:    future.Add(time.Hour * 9),
+			endsAt:    future.Add(time.Hour * 10),
 			hasErr:    true,
-			errMsg:    "Appointment must be scheduled in 30-minute increments",
+			errMsg:    "Appointment must be one of 30, 45, 60, 90 minutes long",
+		},
+		{
+			name:      "90-minute appointment past working hours",
+			userID:    1,
+			trainerID: 1,
+			startsAt:  future.Add(time.Hour * 16),
+			endsAt:    future.Add(time.Hour * 17).Add(time.Minute * 30),
+			hasErr:    true,
+			errMsg:    "Appointment must be scheduled within the trainer's working hours",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			appointment, err := NewAppointment(tc.userID, tc.trainerID, tc.startsAt, tc.endsAt)
+			appointment, err := NewAppointment(tc.userID, tc.trainerID, tc.startsAt, tc.endsAt, DefaultTrainerSettings(tc.trainerID), DefaultWeeklySchedule())
 			if tc.hasErr {
 				assert.Error(t, err)
 				assert.Nil(t, appointment)
@@ -166,3 +207,36 @@ func TestNewAppointment(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAppointmentTimeZones(t *testing.T) {
+	london, _ := LoadLocation("Europe/London")
+	settings := &TrainerSettings{TrainerID: 1, TimeZone: "Europe/London", Durations: DefaultDurations}
+
+	t.Run("Working hours apply in trainer's time zone", func(t *testing.T) {
+		// INFO: 08:00 London is 00:00 in Los Angeles
+		startsAt := time.Date(2030, 7, 8, 8, 0, 0, 0, london).In(DefaultLocation())
+		appointment, err := NewAppointment(1, 1, startsAt, startsAt.Add(time.Minute*30), settings, DefaultWeeklySchedule())
+		assert.NoError(t, err)
+		assert.Equal(t, london, appointment.StartsAt.Location())
+		assert.Equal(t, 8, appointment.StartsAt.Hour())
+	})
+
+	t.Run("Outside working hours in trainer's time zone", func(t *testing.T) {
+		// INFO: 10:00 in Los Angeles is 18:00 London
+		startsAt := time.Date(2030, 7, 8, 10, 0, 0, 0, DefaultLocation())
+		_, err := NewAppointment(1, 1, startsAt, startsAt.Add(time.Minute*30), settings, DefaultWeeklySchedule())
+		assert.Error(t, err)
+		assert.Equal(t, "Appointment must be scheduled within the trainer's working hours", err.Error())
+	})
+
+	t.Run("Working hours follow daylight saving time", func(t *testing.T) {
+		winter := time.Date(2030, 1, 7, 16, 0, 0, 0, time.UTC) // 08:00 PST
+		summer := time.Date(2030, 7, 8, 15, 0, 0, 0, time.UTC) // 08:00 PDT
+
+		for _, startsAt := range []time.Time{winter, summer} {
+			appointment, err := NewAppointment(1, 1, startsAt, startsAt.Add(time.Minute*30), DefaultTrainerSettings(1), DefaultWeeklySchedule())
+			assert.NoError(t, err)
+			assert.Equal(t, 8, appointment.StartsAt.Hour())
+		}
+	})
+}
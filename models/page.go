@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+var ErrInvalidCursor = errors.New("Cursor is invalid")
+
+// Cursor marks the last appointment of a page. Listings are ordered by
+// (starts_at, id), so the next page starts right after it.
+type Cursor struct {
+	StartsAt time.Time
+	ID       int
+}
+
+func CursorFor(appointment *Appointment) *Cursor {
+	return &Cursor{StartsAt: appointment.StartsAt, ID: appointment.ID}
+}
+
+// String encodes the cursor as an opaque URL-safe token.
+func (c *Cursor) String() string {
+	raw := c.StartsAt.UTC().Format(time.RFC3339) + "," + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func ParseCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	startsAtStr, idStr, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	startsAt, err := ParseDateStr(startsAtStr)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id < 1 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{StartsAt: startsAt, ID: id}, nil
+}
+
+// Page selects a slice of a listing. A zero Limit returns every row after
+// the cursor.
+type Page struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+// NewPage parses the limit and cursor query parameters, defaulting the limit
+// to DefaultPageLimit.
+func NewPage(limit int, cursor string) (Page, error) {
+	page := Page{Limit: limit}
+	if page.Limit == 0 {
+		page.Limit = DefaultPageLimit
+	}
+
+	if page.Limit < 0 || page.Limit > MaxPageLimit {
+		return Page{}, errors.New("Limit must be between 1 and " + strconv.Itoa(MaxPageLimit))
+	}
+
+	if cursor != "" {
+		parsed, err := ParseCursor(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		page.Cursor = parsed
+	}
+
+	return page, nil
+}
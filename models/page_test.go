@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		cursor := &Cursor{StartsAt: time.Date(2030, 7, 8, 9, 0, 0, 0, DefaultLocation()), ID: 42}
+
+		parsed, err := ParseCursor(cursor.String())
+		assert.NoError(t, err)
+		assert.True(t, cursor.StartsAt.Equal(parsed.StartsAt))
+		assert.Equal(t, 42, parsed.ID)
+	})
+
+	for _, token := range []string{"not base64!", "bm8tY29tbWE", "MjAzMC0wNy0wOCwx", "MjAzMC0wNy0wOFQxNjowMDowMFosYQ"} {
+		_, err := ParseCursor(token)
+		assert.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	t.Run("Default limit", func(t *testing.T) {
+		page, err := NewPage(0, "")
+		assert.NoError(t, err)
+		assert.Equal(t, Page{Limit: DefaultPageLimit}, page)
+	})
+
+	t.Run("Limit too large", func(t *testing.T) {
+		_, err := NewPage(MaxPageLimit+1, "")
+		assert.EqualError(t, err, "Limit must be between 1 and 500")
+	})
+
+	t.Run("With cursor", func(t *testing.T) {
+		cursor := &Cursor{StartsAt: time.Date(2030, 7, 8, 16, 0, 0, 0, time.UTC), ID: 3}
+
+		page, err := NewPage(10, cursor.String())
+		assert.NoError(t, err)
+		assert.Equal(t, 10, page.Limit)
+		assert.Equal(t, 3, page.Cursor.ID)
+	})
+
+	t.Run("Invalid cursor", func(t *testing.T) {
+		_, err := NewPage(10, "garbage")
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
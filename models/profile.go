@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+// normalizeProfile validates the name and email shared by users and trainers.
+// Emails are compared case-insensitively, so they are stored lowercased.
+func normalizeProfile(name, email string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", errors.New("Name is required")
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", "", errors.New("Email must be a valid email address")
+	}
+
+	return name, email, nil
+}
+
+type User struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Active bool   `json:"active"`
+}
+
+func NewUser(name, email string, active bool) (*User, error) {
+	name, email, err := normalizeProfile(name, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &User{
+		Name:   name,
+		Email:  email,
+		Active: active,
+	}, nil
+}
+
+type Trainer struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Active bool   `json:"active"`
+}
+
+func NewTrainer(name, email string, active bool) (*Trainer, error) {
+	name, email, err := normalizeProfile(name, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Trainer{
+		Name:   name,
+		Email:  email,
+		Active: active,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUser(t *testing.T) {
+	testCases := []struct {
+		name     string
+		userName string
+		email    string
+		hasErr   bool
+		errMsg   string
+		expected *User
+	}{
+		{
+			name:     "valid user",
+			userName: " Jane Doe ",
+			email:    "Jane@Example.com",
+			expected: &User{Name: "Jane Doe", Email: "jane@example.com", Active: true},
+		},
+		{
+			name:     "missing name",
+			userName: " ",
+			email:    "jane@example.com",
+			hasErr:   true,
+			errMsg:   "Name is required",
+		},
+		{
+			name:     "invalid email",
+			userName: "Jane Doe",
+			email:    "jane",
+			hasErr:   true,
+			errMsg:   "Email must be a valid email address",
+		},
+		{
+			name:     "email with display name",
+			userName: "Jane Doe",
+			email:    "Jane <jane@example.com>",
+			hasErr:   true,
+			errMsg:   "Email must be a valid email address",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			user, err := NewUser(tc.userName, tc.email, true)
+			if tc.hasErr {
+				assert.Error(t, err)
+				assert.Nil(t, user)
+				assert.Equal(t, tc.errMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, user)
+			}
+		})
+	}
+}
+
+func TestNewTrainer(t *testing.T) {
+	trainer, err := NewTrainer("John Smith", "john@example.com", false)
+	assert.NoError(t, err)
+	assert.Equal(t, &Trainer{Name: "John Smith", Email: "john@example.com", Active: false}, trainer)
+
+	_, err = NewTrainer("", "john@example.com", true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	ResourceKindRoom      = "room"
+	ResourceKindEquipment = "equipment"
+)
+
+// Resource is a room or piece of equipment that appointments can require.
+// Each resource can only be used by one session at a time.
+type Resource struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Active bool   `json:"active"`
+}
+
+func NewResource(name, kind string, active bool) (*Resource, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("Name is required")
+	}
+
+	if kind != ResourceKindRoom && kind != ResourceKindEquipment {
+		return nil, errors.New("Kind must be one of room, equipment")
+	}
+
+	return &Resource{
+		Name:   name,
+		Kind:   kind,
+		Active: active,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResource(t *testing.T) {
+	testCases := []struct {
+		name         string
+		resourceName string
+		kind         string
+		hasErr       bool
+		errMsg       string
+		expected     *Resource
+	}{
+		{
+			name:         "valid room",
+			resourceName: " Studio A ",
+			kind:         ResourceKindRoom,
+			expected:     &Resource{Name: "Studio A", Kind: ResourceKindRoom, Active: true},
+		},
+		{
+			name:         "valid equipment",
+			resourceName: "Rowing machine",
+			kind:         ResourceKindEquipment,
+			expected:     &Resource{Name: "Rowing machine", Kind: ResourceKindEquipment, Active: true},
+		},
+		{
+			name:         "missing name",
+			resourceName: " ",
+			kind:         ResourceKindRoom,
+			hasErr:       true,
+			errMsg:       "Name is required",
+		},
+		{
+			name:         "invalid kind",
+			resourceName: "Studio A",
+			kind:         "vehicle",
+			hasErr:       true,
+			errMsg:       "Kind must be one of room, equipment",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resource, err := NewResource(tc.resourceName, tc.kind, true)
+			if tc.hasErr {
+				assert.Error(t, err)
+				assert.Nil(t, resource)
+				assert.Equal(t, tc.errMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, resource)
+			}
+		})
+	}
+}
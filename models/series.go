@@ -0,0 +1,215 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSeriesOccurrences caps how many appointments a single series can book.
+const MaxSeriesOccurrences = 52
+
+const (
+	SeriesFrequencyWeekly   = "weekly"
+	SeriesFrequencyBiweekly = "biweekly"
+)
+
+const rruleUntilFormat = "20060102"
+
+// Recurrence is a weekly repeat rule. Exactly one of Count and Until is set;
+// Until is an inclusive date in the trainer's time zone.
+type Recurrence struct {
+	Interval int
+	Count    int
+	Until    string
+}
+
+func NewRecurrence(frequency string, count int, until string) (*Recurrence, error) {
+	recurrence := &Recurrence{Count: count}
+
+	switch frequency {
+	case SeriesFrequencyWeekly:
+		recurrence.Interval = 1
+	case SeriesFrequencyBiweekly:
+		recurrence.Interval = 2
+	default:
+		return nil, errors.New("Frequency must be weekly or biweekly")
+	}
+
+	if until != "" {
+		parsedUntil, err := time.Parse(HolidayDateFormat, until)
+		if err != nil {
+			return nil, errors.New("Until must be in YYYY-MM-DD format")
+		}
+		recurrence.Until = parsedUntil.Format(HolidayDateFormat)
+	}
+
+	if err := recurrence.validate(); err != nil {
+		return nil, err
+	}
+
+	return recurrence, nil
+}
+
+// ParseRecurrence reads the subset of RFC 5545 RRULE supported by series, e.g.
+// "FREQ=WEEKLY;INTERVAL=2;COUNT=10" or "FREQ=WEEKLY;UNTIL=20301231".
+func ParseRecurrence(rrule string) (*Recurrence, error) {
+	recurrence := &Recurrence{Interval: 1}
+	hasFreq := false
+
+	for _, part := range strings.Split(strings.TrimPrefix(rrule, "RRULE:"), ";") {
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return nil, errors.New("RRULE must be a list of KEY=VALUE pairs")
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			if strings.ToUpper(value) != "WEEKLY" {
+				return nil, errors.New("RRULE frequency must be WEEKLY")
+			}
+			hasFreq = true
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > 2 {
+				return nil, errors.New("RRULE interval must be 1 or 2")
+			}
+			recurrence.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.New("RRULE count must be a number")
+			}
+			recurrence.Count = count
+		case "UNTIL":
+			// INFO: Only the date part is used, so both 20301231 and 20301231T235959Z are accepted
+			if len(value) < len(rruleUntilFormat) {
+				return nil, errors.New("RRULE until must be in YYYYMMDD format")
+			}
+			parsedUntil, err := time.Parse(rruleUntilFormat, value[:len(rruleUntilFormat)])
+			if err != nil {
+				return nil, errors.New("RRULE until must be in YYYYMMDD format")
+			}
+			recurrence.Until = parsedUntil.Format(HolidayDateFormat)
+		default:
+			return nil, fmt.Errorf("RRULE part %s is not supported", key)
+		}
+	}
+
+	if !hasFreq {
+		return nil, errors.New("RRULE frequency is required")
+	}
+
+	if err := recurrence.validate(); err != nil {
+		return nil, err
+	}
+
+	return recurrence, nil
+}
+
+func (r *Recurrence) validate() error {
+	if (r.Count == 0) == (r.Until == "") {
+		return errors.New("Series must have either a count or an end date")
+	}
+
+	if r.Until == "" && (r.Count < 1 || r.Count > MaxSeriesOccurrences) {
+		return fmt.Errorf("Series count must be between 1 and %d", MaxSeriesOccurrences)
+	}
+
+	return nil
+}
+
+func (r *Recurrence) String() string {
+	rrule := fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d", r.Interval)
+
+	if r.Count > 0 {
+		return fmt.Sprintf("%s;COUNT=%d", rrule, r.Count)
+	}
+
+	until, _ := time.Parse(HolidayDateFormat, r.Until)
+	return fmt.Sprintf("%s;UNTIL=%s", rrule, until.Format(rruleUntilFormat))
+}
+
+// Occurrences expands the recurrence starting from the first timeslot. Dates
+// are stepped in the timeslot's location so every occurrence keeps the same
+// wall-clock time across DST transitions.
+func (r *Recurrence) Occurrences(startsAt, endsAt time.Time) ([]Timeslot, error) {
+	occurrences := make([]Timeslot, 0)
+
+	for i := 0; r.Count == 0 || i < r.Count; i++ {
+		occurrenceStartsAt := startsAt.AddDate(0, 0, 7*r.Interval*i)
+
+		if r.Until != "" && occurrenceStartsAt.Format(HolidayDateFormat) > r.Until {
+			break
+		}
+
+		if i == MaxSeriesOccurrences {
+			return nil, fmt.Errorf("Series cannot have more than %d occurrences", MaxSeriesOccurrences)
+		}
+
+		occurrences = append(occurrences, NewTimeslot(occurrenceStartsAt, endsAt.AddDate(0, 0, 7*r.Interval*i)))
+	}
+
+	if len(occurrences) == 0 {
+		return nil, errors.New("Series must have at least one occurrence")
+	}
+
+	return occurrences, nil
+}
+
+type AppointmentSeries struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	TrainerID int       `json:"trainer_id"`
+	RRule     string    `json:"rrule"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+func (s *AppointmentSeries) In(loc *time.Location) *AppointmentSeries {
+	series := *s
+	series.StartsAt = ConvertToTZ(s.StartsAt, loc)
+	series.EndsAt = ConvertToTZ(s.EndsAt, loc)
+	return &series
+}
+
+// SeriesConflict is an occurrence that could not be booked or moved.
+type SeriesConflict struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+// SeriesResult reports the outcome of booking or editing a series.
+type SeriesResult struct {
+	Series    *AppointmentSeries `json:"series,omitempty"`
+	Booked    []*Appointment     `json:"booked"`
+	Conflicts []SeriesConflict   `json:"conflicts"`
+}
+
+func (r *SeriesResult) In(loc *time.Location) *SeriesResult {
+	result := &SeriesResult{
+		Booked:    make([]*Appointment, len(r.Booked)),
+		Conflicts: make([]SeriesConflict, len(r.Conflicts)),
+	}
+
+	if r.Series != nil {
+		result.Series = r.Series.In(loc)
+	}
+
+	for i, appointment := range r.Booked {
+		result.Booked[i] = appointment.In(loc)
+	}
+
+	for i, conflict := range r.Conflicts {
+		result.Conflicts[i] = SeriesConflict{
+			StartsAt: ConvertToTZ(conflict.StartsAt, loc),
+			EndsAt:   ConvertToTZ(conflict.EndsAt, loc),
+			Reason:   conflict.Reason,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRecurrence(t *testing.T) {
+	testCases := []struct {
+		name      string
+		frequency string
+		count     int
+		until     string
+		hasErr    bool
+		errMsg    string
+		expected  string
+	}{
+		{
+			name:      "weekly with count",
+			frequency: "weekly",
+			count:     4,
+			expected:  "FREQ=WEEKLY;INTERVAL=1;COUNT=4",
+		},
+		{
+			name:      "biweekly until date",
+			frequency: "biweekly",
+			until:     "2030-12-31",
+			expected:  "FREQ=WEEKLY;INTERVAL=2;UNTIL=20301231",
+		},
+		{
+			name:      "invalid frequency",
+			frequency: "daily",
+			count:     4,
+			hasErr:    true,
+			errMsg:    "Frequency must be weekly or biweekly",
+		},
+		{
+			name:      "count and until",
+			frequency: "weekly",
+			count:     4,
+			until:     "2030-12-31",
+			hasErr:    true,
+			errMsg:    "Series must have either a count or an end date",
+		},
+		{
+			name:      "count too large",
+			frequency: "weekly",
+			count:     53,
+			hasErr:    true,
+			errMsg:    "Series count must be between 1 and 52",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recurrence, err := NewRecurrence(tc.frequency, tc.count, tc.until)
+			if tc.hasErr {
+				assert.Error(t, err)
+				assert.Nil(t, recurrence)
+				assert.Equal(t, tc.errMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, recurrence.String())
+			}
+		})
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	t.Run("Round trips", func(t *testing.T) {
+		for _, rrule := range []string{
+			"FREQ=WEEKLY;INTERVAL=1;COUNT=4",
+			"FREQ=WEEKLY;INTERVAL=2;UNTIL=20301231",
+		} {
+			recurrence, err := ParseRecurrence(rrule)
+			assert.NoError(t, err)
+			assert.Equal(t, rrule, recurrence.String())
+		}
+	})
+
+	t.Run("Defaults and prefix", func(t *testing.T) {
+		recurrence, err := ParseRecurrence("RRULE:FREQ=WEEKLY;UNTIL=20301231T235959Z")
+		assert.NoError(t, err)
+		assert.Equal(t, &Recurrence{Interval: 1, Until: "2030-12-31"}, recurrence)
+	})
+
+	t.Run("Unsupported frequency", func(t *testing.T) {
+		_, err := ParseRecurrence("FREQ=DAILY;COUNT=4")
+		assert.Error(t, err)
+		assert.Equal(t, "RRULE frequency must be WEEKLY", err.Error())
+	})
+
+	t.Run("Unsupported part", func(t *testing.T) {
+		_, err := ParseRecurrence("FREQ=WEEKLY;COUNT=4;BYDAY=MO")
+		assert.Error(t, err)
+		assert.Equal(t, "RRULE part BYDAY is not supported", err.Error())
+	})
+}
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	tz := DefaultLocation()
+
+	t.Run("Keeps wall-clock time across DST", func(t *testing.T) {
+		recurrence := &Recurrence{Interval: 1, Count: 3}
+		startsAt := time.Date(2030, 10, 28, 9, 0, 0, 0, tz) // DST ends 2030-11-03
+
+		occurrences, err := recurrence.Occurrences(startsAt, startsAt.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, occurrences, 3)
+
+		for _, occurrence := range occurrences {
+			assert.Equal(t, 9, occurrence.StartsAt.Hour())
+			assert.Equal(t, 10, occurrence.EndsAt.Hour())
+		}
+		assert.Equal(t, "2030-11-11T09:00:00-08:00", occurrences[2].StartsAt.Format(time.RFC3339))
+	})
+
+	t.Run("Biweekly until date", func(t *testing.T) {
+		recurrence := &Recurrence{Interval: 2, Until: "2030-08-05"}
+		startsAt := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+
+		occurrences, err := recurrence.Occurrences(startsAt, startsAt.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, occurrences, 3)
+		assert.Equal(t, startsAt.AddDate(0, 0, 28), occurrences[2].StartsAt)
+	})
+
+	t.Run("Too many occurrences", func(t *testing.T) {
+		recurrence := &Recurrence{Interval: 1, Until: "2032-01-01"}
+		startsAt := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+
+		_, err := recurrence.Occurrences(startsAt, startsAt.Add(time.Hour))
+		assert.Error(t, err)
+		assert.Equal(t, "Series cannot have more than 52 occurrences", err.Error())
+	})
+
+	t.Run("No occurrences", func(t *testing.T) {
+		recurrence := &Recurrence{Interval: 1, Until: "2030-07-01"}
+		startsAt := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+
+		_, err := recurrence.Occurrences(startsAt, startsAt.Add(time.Hour))
+		assert.Error(t, err)
+		assert.Equal(t, "Series must have at least one occurrence", err.Error())
+	})
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type TimeOff struct {
+	ID        int       `json:"id"`
+	TrainerID int       `json:"trainer_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	AllDay    bool      `json:"all_day"`
+	Reason    string    `json:"reason"`
+}
+
+// NewTimeOff creates time off in the trainer's time zone. All-day time off
+// covers every calendar day from startsAt through endsAt.
+func NewTimeOff(trainerID int, startsAt, endsAt time.Time, allDay bool, reason string, loc *time.Location) (*TimeOff, error) {
+	if trainerID < 1 {
+		return nil, errors.New("TrainerID must be greater than 0")
+	}
+
+	startsAt = ConvertToTZ(startsAt, loc)
+	endsAt = ConvertToTZ(endsAt, loc)
+
+	if allDay {
+		startsAt = time.Date(startsAt.Year(), startsAt.Month(), startsAt.Day(), 0, 0, 0, 0, loc)
+		endsAt = time.Date(endsAt.Year(), endsAt.Month(), endsAt.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	}
+
+	if !startsAt.Before(endsAt) {
+		return nil, errors.New("Time off start time must be before end time")
+	}
+
+	return &TimeOff{
+		TrainerID: trainerID,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		AllDay:    allDay,
+		Reason:    reason,
+	}, nil
+}
+
+func (t *TimeOff) In(loc *time.Location) *TimeOff {
+	timeOff := *t
+	timeOff.StartsAt = ConvertToTZ(t.StartsAt, loc)
+	timeOff.EndsAt = ConvertToTZ(t.EndsAt, loc)
+	return &timeOff
+}
+
+func (t *TimeOff) Timeslot() Timeslot {
+	return NewTimeslot(t.StartsAt, t.EndsAt)
+}
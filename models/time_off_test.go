@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTimeOff(t *testing.T) {
+	tz := DefaultLocation()
+	monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+
+	testCases := []struct {
+		name     string
+		startsAt time.Time
+		endsAt   time.Time
+		allDay   bool
+		hasErr   bool
+		errMsg   string
+		expected *TimeOff
+	}{
+		{
+			name:     "partial day",
+			startsAt: monday.Add(time.Hour * 9),
+			endsAt:   monday.Add(time.Hour * 12),
+			expected: &TimeOff{TrainerID: 1, StartsAt: monday.Add(time.Hour * 9), EndsAt: monday.Add(time.Hour * 12), Reason: "Dentist"},
+		},
+		{
+			name:     "all day covers whole calendar days",
+			startsAt: monday.Add(time.Hour * 9),
+			endsAt:   monday.AddDate(0, 0, 2).Add(time.Hour * 9),
+			allDay:   true,
+			expected: &TimeOff{TrainerID: 1, StartsAt: monday, EndsAt: monday.AddDate(0, 0, 3), AllDay: true, Reason: "Dentist"},
+		},
+		{
+			name:     "single all day",
+			startsAt: monday,
+			endsAt:   monday,
+			allDay:   true,
+			expected: &TimeOff{TrainerID: 1, StartsAt: monday, EndsAt: monday.AddDate(0, 0, 1), AllDay: true, Reason: "Dentist"},
+		},
+		{
+			name:     "start time after end time",
+			startsAt: monday.Add(time.Hour * 12),
+			endsAt:   monday.Add(time.Hour * 9),
+			hasErr:   true,
+			errMsg:   "Time off start time must be before end time",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			timeOff, err := NewTimeOff(1, tc.startsAt, tc.endsAt, tc.allDay, "Dentist", tz)
+			if tc.hasErr {
+				assert.Error(t, err)
+				assert.Nil(t, timeOff)
+				assert.Equal(t, tc.errMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, timeOff)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var DefaultDurations = []int{30, 45, 60, 90}
+
+// MaxCapacity is the most users a trainer can take in a single group session.
+const MaxCapacity = 20
+
+// MaxBuffer is the longest buffer in minutes a trainer can keep before or
+// after a session.
+const MaxBuffer = 120
+
+type TrainerSettings struct {
+	TrainerID    int    `json:"trainer_id"`
+	TimeZone     string `json:"time_zone"`
+	Durations    []int  `json:"durations"`
+	Capacity     int    `json:"capacity"`
+	BufferBefore int    `json:"buffer_before"`
+	BufferAfter  int    `json:"buffer_after"`
+}
+
+func NewTrainerSettings(trainerID int, timeZone string, durations []int, capacity, bufferBefore, bufferAfter int) (*TrainerSettings, error) {
+	if trainerID < 1 {
+		return nil, errors.New("TrainerID must be greater than 0")
+	}
+
+	if _, err := LoadLocation(timeZone); err != nil {
+		return nil, errors.New("Time zone must be a valid IANA time zone")
+	}
+
+	if len(durations) == 0 {
+		durations = DefaultDurations
+	}
+
+	if capacity == 0 {
+		capacity = 1
+	}
+
+	if capacity < 1 || capacity > MaxCapacity {
+		return nil, fmt.Errorf("Capacity must be between 1 and %d", MaxCapacity)
+	}
+
+	for _, buffer := range []int{bufferBefore, bufferAfter} {
+		if buffer < 0 || buffer > MaxBuffer || buffer%5 != 0 {
+			return nil, fmt.Errorf("Buffers must be multiples of 5 minutes between 0 and %d minutes", MaxBuffer)
+		}
+	}
+
+	sortedDurations := make([]int, len(durations))
+	copy(sortedDurations, durations)
+	sort.Ints(sortedDurations)
+
+	for i, duration := range sortedDurations {
+		if duration < 15 || duration > 8*60 || duration%15 != 0 {
+			return nil, errors.New("Durations must be multiples of 15 minutes between 15 and 480 minutes")
+		}
+
+		if i > 0 && sortedDurations[i-1] == duration {
+			return nil, errors.New("Durations must be unique")
+		}
+	}
+
+	return &TrainerSettings{
+		TrainerID:    trainerID,
+		TimeZone:     timeZone,
+		Durations:    sortedDurations,
+		Capacity:     capacity,
+		BufferBefore: bufferBefore,
+		BufferAfter:  bufferAfter,
+	}, nil
+}
+
+func DefaultTrainerSettings(trainerID int) *TrainerSettings {
+	return &TrainerSettings{
+		TrainerID: trainerID,
+		TimeZone:  DEFAULT_TZ,
+		Durations: DefaultDurations,
+		Capacity:  1,
+	}
+}
+
+func (ts *TrainerSettings) Location() *time.Location {
+	loc, err := LoadLocation(ts.TimeZone)
+	if err != nil {
+		return DefaultLocation()
+	}
+	return loc
+}
+
+// Seats is the number of users that can book the same timeslot with the
+// trainer.
+func (ts *TrainerSettings) Seats() int {
+	if ts.Capacity < 1 {
+		return 1
+	}
+	return ts.Capacity
+}
+
+// BufferGap is the least time the trainer needs between two sessions: the
+// buffer after the first plus the buffer before the next.
+func (ts *TrainerSettings) BufferGap() time.Duration {
+	return time.Duration(ts.BufferBefore+ts.BufferAfter) * time.Minute
+}
+
+func (ts *TrainerSettings) AllowsDuration(duration time.Duration) bool {
+	for _, minutes := range ts.Durations {
+		if time.Duration(minutes)*time.Minute == duration {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultDuration is the shortest duration offered by the trainer.
+func (ts *TrainerSettings) DefaultDuration() time.Duration {
+	if len(ts.Durations) == 0 {
+		return time.Duration(DefaultDurations[0]) * time.Minute
+	}
+	return time.Duration(ts.Durations[0]) * time.Minute
+}
+
+func (ts *TrainerSettings) DurationsString() string {
+	durations := make([]string, len(ts.Durations))
+	for i, duration := range ts.Durations {
+		durations[i] = fmt.Sprint(duration)
+	}
+	return strings.Join(durations, ", ")
+}
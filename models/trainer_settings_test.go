@@ -0,0 +1,130 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTrainerSettings(t *testing.T) {
+	testCases := []struct {
+		name      string
+		trainerID int
+		timeZone  string
+		durations []int
+		capacity  int
+		buffers   [2]int
+		hasErr    bool
+		errMsg    string
+		expected  *TrainerSettings
+	}{
+		{
+			name:      "default durations",
+			trainerID: 1,
+			timeZone:  "Europe/London",
+			expected:  &TrainerSettings{TrainerID: 1, TimeZone: "Europe/London", Durations: DefaultDurations, Capacity: 1},
+		},
+		{
+			name:      "durations are sorted",
+			trainerID: 1,
+			timeZone:  "Europe/London",
+			durations: []int{90, 45},
+			expected:  &TrainerSettings{TrainerID: 1, TimeZone: "Europe/London", Durations: []int{45, 90}, Capacity: 1},
+		},
+		{
+			name:      "group sessions",
+			trainerID: 1,
+			timeZone:  "Europe/London",
+			capacity:  4,
+			expected:  &TrainerSettings{TrainerID: 1, TimeZone: "Europe/London", Durations: DefaultDurations, Capacity: 4},
+		},
+		{
+			name:      "capacity too large",
+			trainerID: 1,
+			timeZone:  "Europe/London",
+			capacity:  21,
+			hasErr:    true,
+			errMsg:    "Capacity must be between 1 and 20",
+		},
+		{
+			name:      "buffers",
+			trainerID: 1,
+			timeZone:  "Europe/London",
+			buffers:   [2]int{10, 15},
+			expected:  &TrainerSettings{TrainerID: 1, TimeZone: "Europe/London", Durations: DefaultDurations, Capacity: 1, BufferBefore: 10, BufferAfter: 15},
+		},
+		{
+			name:      "buffer not a multiple of 5 minutes",
+			trainerID: 1,
+			timeZone:  "Europe/London",
+			buffers:   [2]int{0, 12},
+			hasErr:    true,
+			errMsg:    "Buffers must be multiples of 5 minutes between 0 and 120 minutes",
+		},
+		{
+			name:      "buffer too long",
+			trainerID: 1,
+			timeZone:  "Europe/London",
+			buffers:   [2]int{125, 0},
+			hasErr:    true,
+			errMsg:    "Buffers must be multiples of 5 minutes between 0 and 120 minutes",
+		},
+		{
+			name:      "invalid time zone",
+			trainerID: 1,
+			timeZone:  "Mars/Olympus_Mons",
+			hasErr:    true,
+			errMsg:    "Time zone must be a valid IANA time zone",
+		},
+		{
+			name:      "invalid duration",
+			trainerID: 1,
+			timeZone:  "Europe/London",
+			durations: []int{20},
+			hasErr:    true,
+			errMsg:    "Durations must be multiples of 15 minutes between 15 and 480 minutes",
+		},
+		{
+			name:      "duplicate durations",
+			trainerID: 1,
+			timeZone:  "Europe/London",
+			durations: []int{60, 60},
+			hasErr:    true,
+			errMsg:    "Durations must be unique",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings, err := NewTrainerSettings(tc.trainerID, tc.timeZone, tc.durations, tc.capacity, tc.buffers[0], tc.buffers[1])
+			if tc.hasErr {
+				assert.Error(t, err)
+				assert.Nil(t, settings)
+				assert.Equal(t, tc.errMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, settings)
+			}
+		})
+	}
+}
+
+func TestTrainerSettingsDurations(t *testing.T) {
+	settings := &TrainerSettings{TrainerID: 1, TimeZone: DEFAULT_TZ, Durations: []int{45, 60}}
+
+	assert.True(t, settings.AllowsDuration(time.Minute*45))
+	assert.False(t, settings.AllowsDuration(time.Minute*30))
+	assert.Equal(t, time.Minute*45, settings.DefaultDuration())
+	assert.Equal(t, "45, 60", settings.DurationsString())
+}
+
+func TestTrainerSettingsSeats(t *testing.T) {
+	assert.Equal(t, 1, (&TrainerSettings{}).Seats())
+	assert.Equal(t, 4, (&TrainerSettings{Capacity: 4}).Seats())
+}
+
+func TestTrainerSettingsBufferGap(t *testing.T) {
+	assert.Equal(t, time.Duration(0), (&TrainerSettings{}).BufferGap())
+	assert.Equal(t, time.Minute*25, (&TrainerSettings{BufferBefore: 10, BufferAfter: 15}).BufferGap())
+}
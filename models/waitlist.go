@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+const (
+	WaitlistStatusWaiting   = "waiting"
+	WaitlistStatusOffered   = "offered"
+	WaitlistStatusBooked    = "booked"
+	WaitlistStatusExpired   = "expired"
+	WaitlistStatusCancelled = "cancelled"
+)
+
+// WaitlistOfferTTL is how long a waitlisted user has to accept a freed slot
+// before it is offered to the next user in the queue.
+const WaitlistOfferTTL = 30 * time.Minute
+
+type WaitlistEntry struct {
+	ID             int        `json:"id"`
+	UserID         int        `json:"user_id"`
+	TrainerID      int        `json:"trainer_id"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         time.Time  `json:"ends_at"`
+	AutoBook       bool       `json:"auto_book"`
+	Status         string     `json:"status"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	AppointmentID  *int       `json:"appointment_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// NewWaitlistEntry validates the requested slot with the same rules as a
+// booking so an offer can always be turned into an appointment.
+func NewWaitlistEntry(userID, trainerID int, startsAt, endsAt time.Time, autoBook bool, settings *TrainerSettings, schedule WeeklySchedule) (*WaitlistEntry, error) {
+	appointment, err := NewAppointment(userID, trainerID, startsAt, endsAt, settings, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WaitlistEntry{
+		UserID:    appointment.UserID,
+		TrainerID: appointment.TrainerID,
+		StartsAt:  appointment.StartsAt,
+		EndsAt:    appointment.EndsAt,
+		AutoBook:  autoBook,
+		Status:    WaitlistStatusWaiting,
+	}, nil
+}
+
+// Appointment returns the appointment the entry would book.
+func (e *WaitlistEntry) Appointment() *Appointment {
+	return &Appointment{
+		UserID:    e.UserID,
+		TrainerID: e.TrainerID,
+		StartsAt:  e.StartsAt,
+		EndsAt:    e.EndsAt,
+		Status:    AppointmentStatusScheduled,
+	}
+}
+
+// OfferExpiry is when an offer made at the given time lapses. Offers never
+// outlive the one hour booking notice before the slot starts.
+func (e *WaitlistEntry) OfferExpiry(now time.Time) time.Time {
+	expiresAt := now.Add(WaitlistOfferTTL)
+	if deadline := e.StartsAt.Add(-time.Hour); deadline.Before(expiresAt) {
+		return deadline
+	}
+	return expiresAt
+}
+
+func (e *WaitlistEntry) In(loc *time.Location) *WaitlistEntry {
+	entry := *e
+	entry.StartsAt = ConvertToTZ(e.StartsAt, loc)
+	entry.EndsAt = ConvertToTZ(e.EndsAt, loc)
+	if e.OfferExpiresAt != nil {
+		offerExpiresAt := ConvertToTZ(*e.OfferExpiresAt, loc)
+		entry.OfferExpiresAt = &offerExpiresAt
+	}
+	return &entry
+}
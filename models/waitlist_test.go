@@ -0,0 +1,23 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitlistEntryOfferExpiry(t *testing.T) {
+	tz := DefaultLocation()
+	now := time.Date(2030, 7, 8, 6, 0, 0, 0, tz)
+
+	t.Run("Offer lasts the full TTL", func(t *testing.T) {
+		entry := &WaitlistEntry{StartsAt: now.Add(time.Hour * 3)}
+		assert.Equal(t, now.Add(WaitlistOfferTTL), entry.OfferExpiry(now))
+	})
+
+	t.Run("Offer ends at the booking notice", func(t *testing.T) {
+		entry := &WaitlistEntry{StartsAt: now.Add(time.Hour).Add(time.Minute * 10)}
+		assert.Equal(t, now.Add(time.Minute*10), entry.OfferExpiry(now))
+	})
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+const WorkingHoursTimeFormat = "15:04"
+
+type WorkingHours struct {
+	Weekday   time.Weekday `json:"weekday"`
+	StartTime string       `json:"start_time"`
+	EndTime   string       `json:"end_time"`
+}
+
+func NewWorkingHours(weekday int, startTime, endTime string) (*WorkingHours, error) {
+	if weekday < int(time.Sunday) || weekday > int(time.Saturday) {
+		return nil, errors.New("Weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+
+	parsedStartTime, err := time.Parse(WorkingHoursTimeFormat, startTime)
+	if err != nil {
+		return nil, errors.New("Start time must be in HH:MM format")
+	}
+
+	parsedEndTime, err := time.Parse(WorkingHoursTimeFormat, endTime)
+	if err != nil {
+		return nil, errors.New("End time must be in HH:MM format")
+	}
+
+	// INFO: 24:00 is not parseable, so an interval ending at midnight is written as 00:00
+	if parsedEndTime.Hour() == 0 && parsedEndTime.Minute() == 0 {
+		parsedEndTime = parsedEndTime.Add(24 * time.Hour)
+	}
+
+	if !parsedStartTime.Before(parsedEndTime) {
+		return nil, errors.New("Working hours start time must be before end time")
+	}
+
+	if parsedStartTime.Minute()%30 != 0 || parsedEndTime.Minute()%30 != 0 {
+		return nil, errors.New("Working hours must start and end on the hour or half hour")
+	}
+
+	return &WorkingHours{
+		Weekday:   time.Weekday(weekday),
+		StartTime: parsedStartTime.Format(WorkingHoursTimeFormat),
+		EndTime:   parsedEndTime.Format(WorkingHoursTimeFormat),
+	}, nil
+}
+
+// On returns the start and end of the working hours on the given date, in the
+// date's location.
+func (w WorkingHours) On(date time.Time) (time.Time, time.Time) {
+	startTime, _ := time.Parse(WorkingHoursTimeFormat, w.StartTime)
+	endTime, _ := time.Parse(WorkingHoursTimeFormat, w.EndTime)
+
+	startsAt := time.Date(date.Year(), date.Month(), date.Day(), startTime.Hour(), startTime.Minute(), 0, 0, date.Location())
+	endsAt := time.Date(date.Year(), date.Month(), date.Day(), endTime.Hour(), endTime.Minute(), 0, 0, date.Location())
+
+	if !endsAt.After(startsAt) {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+
+	return startsAt, endsAt
+}
+
+type WeeklySchedule []WorkingHours
+
+func NewWeeklySchedule(workingHours []WorkingHours) (WeeklySchedule, error) {
+	schedule := make(WeeklySchedule, len(workingHours))
+	copy(schedule, workingHours)
+
+	sort.Slice(schedule, func(i, j int) bool {
+		if schedule[i].Weekday != schedule[j].Weekday {
+			return schedule[i].Weekday < schedule[j].Weekday
+		}
+		return schedule[i].StartTime < schedule[j].StartTime
+	})
+
+	for i := 1; i < len(schedule); i++ {
+		if schedule[i].Weekday != schedule[i-1].Weekday {
+			continue
+		}
+
+		if schedule[i].StartTime < schedule[i-1].EndTime || schedule[i-1].EndTime == "00:00" {
+			return nil, errors.New("Working hours must not overlap")
+		}
+	}
+
+	return schedule, nil
+}
+
+// DefaultWeeklySchedule is used for trainers without configured working hours.
+func DefaultWeeklySchedule() WeeklySchedule {
+	schedule := make(WeeklySchedule, 0, 5)
+	for weekday := time.Monday; weekday <= time.Friday; weekday++ {
+		schedule = append(schedule, WorkingHours{Weekday: weekday, StartTime: "08:00", EndTime: "17:00"})
+	}
+	return schedule
+}
+
+// On returns the working hours intervals on the given date, ordered by start time.
+func (ws WeeklySchedule) On(date time.Time) []Timeslot {
+	intervals := make([]Timeslot, 0)
+
+	for _, workingHours := range ws {
+		if workingHours.Weekday != date.Weekday() {
+			continue
+		}
+
+		startsAt, endsAt := workingHours.On(date)
+		intervals = append(intervals, Timeslot{StartsAt: startsAt, EndsAt: endsAt})
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].StartsAt.Before(intervals[j].StartsAt)
+	})
+
+	return intervals
+}
+
+// Contains reports whether the range falls entirely within a single interval.
+func (ws WeeklySchedule) Contains(startsAt, endsAt time.Time) bool {
+	for _, interval := range ws.On(startsAt) {
+		if !startsAt.Before(interval.StartsAt) && !endsAt.After(interval.EndsAt) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkingHours(t *testing.T) {
+	testCases := []struct {
+		name      string
+		weekday   int
+		startTime string
+		endTime   string
+		hasErr    bool
+		errMsg    string
+		expected  *WorkingHours
+	}{
+		{
+			name:      "valid working hours",
+			weekday:   1,
+			startTime: "06:00",
+			endTime:   "10:30",
+			expected:  &WorkingHours{Weekday: time.Monday, StartTime: "06:00", EndTime: "10:30"},
+		},
+		{
+			name:      "valid working hours until midnight",
+			weekday:   6,
+			startTime: "18:00",
+			endTime:   "00:00",
+			expected:  &WorkingHours{Weekday: time.Saturday, StartTime: "18:00", EndTime: "00:00"},
+		},
+		{
+			name:      "invalid weekday",
+			weekday:   7,
+			startTime: "08:00",
+			endTime:   "12:00",
+			hasErr:    true,
+			errMsg:    "Weekday must be between 0 (Sunday) and 6 (Saturday)",
+		},
+		{
+			name:      "invalid time format",
+			weekday:   1,
+			startTime: "8am",
+			endTime:   "12:00",
+			hasErr:    true,
+			errMsg:    "Start time must be in HH:MM format",
+		},
+		{
+			name:      "start time after end time",
+			weekday:   1,
+			startTime: "12:00",
+			endTime:   "08:00",
+			hasErr:    true,
+			errMsg:    "Working hours start time must be before end time",
+		},
+		{
+			name:      "not on the hour or half hour",
+			weekday:   1,
+			startTime: "08:15",
+			endTime:   "12:00",
+			hasErr:    true,
+			errMsg:    "Working hours must start and end on the hour or half hour",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			workingHours, err := NewWorkingHours(tc.weekday, tc.startTime, tc.endTime)
+			if tc.hasErr {
+				assert.Error(t, err)
+				assert.Nil(t, workingHours)
+				assert.Equal(t, tc.errMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, workingHours)
+			}
+		})
+	}
+}
+
+func TestNewWeeklySchedule(t *testing.T) {
+	t.Run("Split shift", func(t *testing.T) {
+		schedule, err := NewWeeklySchedule([]WorkingHours{
+			{Weekday: time.Monday, StartTime: "16:00", EndTime: "20:00"},
+			{Weekday: time.Monday, StartTime: "06:00", EndTime: "10:00"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "06:00", schedule[0].StartTime)
+		assert.Equal(t, "16:00", schedule[1].StartTime)
+	})
+
+	t.Run("Overlapping working hours", func(t *testing.T) {
+		_, err := NewWeeklySchedule([]WorkingHours{
+			{Weekday: time.Monday, StartTime: "06:00", EndTime: "10:00"},
+			{Weekday: time.Monday, StartTime: "09:30", EndTime: "12:00"},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "Working hours must not overlap", err.Error())
+	})
+}
+
+func TestWeeklyScheduleContains(t *testing.T) {
+	tz := DefaultLocation()
+	monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+	saturday := time.Date(2030, 7, 13, 0, 0, 0, 0, tz)
+
+	schedule := WeeklySchedule{
+		{Weekday: time.Monday, StartTime: "06:00", EndTime: "10:00"},
+		{Weekday: time.Monday, StartTime: "16:00", EndTime: "20:00"},
+		{Weekday: time.Saturday, StartTime: "07:00", EndTime: "09:00"},
+	}
+
+	testCases := []struct {
+		name     string
+		startsAt time.Time
+		endsAt   time.Time
+		expected bool
+	}{
+		{
+			name:     "within morning shift",
+			startsAt: monday.Add(time.Hour * 6),
+			endsAt:   monday.Add(time.Hour * 6).Add(time.Minute * 30),
+			expected: true,
+		},
+		{
+			name:     "end of evening shift",
+			startsAt: monday.Add(time.Hour * 19).Add(time.Minute * 30),
+			endsAt:   monday.Add(time.Hour * 20),
+			expected: true,
+		},
+		{
+			name:     "between shifts",
+			startsAt: monday.Add(time.Hour * 12),
+			endsAt:   monday.Add(time.Hour * 12).Add(time.Minute * 30),
+			expected: false,
+		},
+		{
+			name:     "spans end of shift",
+			startsAt: monday.Add(time.Hour * 9).Add(time.Minute * 30),
+			endsAt:   monday.Add(time.Hour * 10).Add(time.Minute * 30),
+			expected: false,
+		},
+		{
+			name:     "weekend shift",
+			startsAt: saturday.Add(time.Hour * 7),
+			endsAt:   saturday.Add(time.Hour * 7).Add(time.Minute * 30),
+			expected: true,
+		},
+		{
+			name:     "day without working hours",
+			startsAt: saturday.Add(time.Hour * 24).Add(time.Hour * 7),
+			endsAt:   saturday.Add(time.Hour * 24).Add(time.Hour * 7).Add(time.Minute * 30),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, schedule.Contains(tc.startsAt, tc.endsAt))
+		})
+	}
+}
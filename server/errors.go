@@ -0,0 +1,78 @@
+package server
+
+import (
+	"errors"
+	"future-app/store"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	ErrCodeTimeslotUnavailable      = "timeslot_unavailable"
+	ErrCodeAlreadyWaitlisted        = "already_waitlisted"
+	ErrCodeWaitlistOfferExpired     = "waitlist_offer_expired"
+	ErrCodeWaitlistOfferUnavailable = "waitlist_offer_unavailable"
+	ErrCodeEmailTaken               = "email_taken"
+	ErrCodeResourceUnavailable      = "resource_unavailable"
+)
+
+// HeaderNextCursor carries the cursor for the next page of a listing.
+const HeaderNextCursor = "X-Next-Cursor"
+
+// HeaderActor names who is making a change. It is recorded in the appointment
+// history, or "anonymous" when the header is missing.
+const HeaderActor = "X-Actor"
+
+const anonymousActor = "anonymous"
+
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewConflictError(code string, err error) *echo.HTTPError {
+	return echo.NewHTTPError(http.StatusConflict, ErrorResponse{Code: code, Message: err.Error()})
+}
+
+// NewParticipantError maps unknown users and trainers to 404 and deactivated
+// ones to 422. It returns nil for any other error.
+func NewParticipantError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, store.ErrUserNotFound), errors.Is(err, store.ErrTrainerNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, store.ErrUserInactive), errors.Is(err, store.ErrTrainerInactive):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return nil
+}
+
+// NewResourceError maps unknown resources to 404 and deactivated ones to 422.
+// It returns nil for any other error.
+func NewResourceError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, store.ErrResourceNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, store.ErrResourceInactive):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return nil
+}
+
+// NewBookingError maps the errors from booking a timeslot: a taken timeslot or
+// resource is a 409 conflict, and participant and resource errors are mapped
+// as above. It returns nil for any other error.
+func NewBookingError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, store.ErrTimeslotUnavailable):
+		return NewConflictError(ErrCodeTimeslotUnavailable, err)
+	case errors.Is(err, store.ErrResourceUnavailable):
+		return NewConflictError(ErrCodeResourceUnavailable, err)
+	}
+
+	if httpErr := NewParticipantError(err); httpErr != nil {
+		return httpErr
+	}
+
+	return NewResourceError(err)
+}
@@ -1,13 +1,121 @@
 package server
 
 import (
+	"errors"
+	"fmt"
 	"future-app/models"
+	"future-app/store"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// getResponseLocation returns the time zone requested through the tz query
+// parameter, falling back to the given location.
+func getResponseLocation(c echo.Context, fallback *time.Location) (*time.Location, error) {
+	tz := c.QueryParam("tz")
+	if tz == "" {
+		return fallback, nil
+	}
+
+	loc, err := models.LoadLocation(tz)
+	if err != nil {
+		return nil, errors.New("Invalid time zone")
+	}
+
+	return loc, nil
+}
+
+// setNextPage advertises the next page of a listing through the Link and
+// X-Next-Cursor headers. The body stays a plain array.
+func setNextPage(c echo.Context, next *models.Cursor) {
+	if next == nil {
+		return
+	}
+
+	cursor := next.String()
+	u := *c.Request().URL
+	query := u.Query()
+	query.Set("cursor", cursor)
+	u.RawQuery = query.Encode()
+
+	c.Response().Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, u.RequestURI()))
+	c.Response().Header().Set(HeaderNextCursor, cursor)
+}
+
+// auditedStore scopes the store to the request, so the appointment history
+// records the actor from the X-Actor header and the request ID.
+func (s *APIServer) auditedStore(c echo.Context) store.Repository {
+	actor := strings.TrimSpace(c.Request().Header.Get(HeaderActor))
+	if actor == "" {
+		actor = anonymousActor
+	}
+
+	return s.store.WithAudit(models.Audit{
+		Actor:     actor,
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+	})
+}
+
+// validateTrainer returns 404 for unknown trainers and, when requireActive is
+// set, 422 for deactivated ones.
+func (s *APIServer) validateTrainer(trainerID int, requireActive bool) error {
+	trainer, err := s.store.GetTrainer(trainerID)
+	if err == nil && requireActive && !trainer.Active {
+		err = store.ErrTrainerInactive
+	}
+
+	if httpErr := NewParticipantError(err); httpErr != nil {
+		return httpErr
+	}
+
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+// validateUser returns 404 for unknown users and, when requireActive is set,
+// 422 for deactivated ones.
+func (s *APIServer) validateUser(userID int, requireActive bool) error {
+	user, err := s.store.GetUser(userID)
+	if err == nil && requireActive && !user.Active {
+		err = store.ErrUserInactive
+	}
+
+	if httpErr := NewParticipantError(err); httpErr != nil {
+		return httpErr
+	}
+
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+// validateResource returns 404 for unknown resources and, when requireActive is
+// set, 422 for deactivated ones.
+func (s *APIServer) validateResource(resourceID int, requireActive bool) error {
+	resource, err := s.store.GetResource(resourceID)
+	if err == nil && requireActive && !resource.Active {
+		err = store.ErrResourceInactive
+	}
+
+	if httpErr := NewResourceError(err); httpErr != nil {
+		return httpErr
+	}
+
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
 func (s *APIServer) handlePostAppointment(c echo.Context) error {
 	req := new(PostAppointmentReq)
 	logger := GetEchoLogger(c)
@@ -22,29 +130,67 @@ func (s *APIServer) handlePostAppointment(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	if err := s.validateUser(req.UserID, true); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate user")
+		return err
+	}
+
+	if err := s.validateTrainer(req.TrainerID, true); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	for _, resourceID := range req.ResourceIDs {
+		if err := s.validateResource(resourceID, true); err != nil {
+			logger.Error().Err(err).Msg("Failed to validate resource")
+			return err
+		}
+	}
+
 	parsedStartsAt, _ := models.ParseDateStr(req.StartsAt)
 	parsedEndsAt, _ := models.ParseDateStr(req.EndsAt)
 
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	loc, err := getResponseLocation(c, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	schedule, err := s.store.GetTrainerWorkingHours(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get working hours")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
 	appointment, err := models.NewAppointment(
 		req.UserID,
 		req.TrainerID,
 		parsedStartsAt,
 		parsedEndsAt,
+		settings,
+		schedule,
 	)
 
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create appointment")
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-
-	if err := s.store.ValidateAvailableTimeslot(appointment); err != nil {
-		logger.Error().Err(err).Msg("Failed to validate timeslot")
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-	}
+	appointment.ResourceIDs = req.ResourceIDs
 
 	logger.Info().Interface("appointment", appointment).Msg("Creating appointment")
 
-	res, err := s.store.CreateAppointment(appointment)
+	res, err := s.auditedStore(c).CreateAppointment(appointment)
+
+	if httpErr := NewBookingError(err); httpErr != nil {
+		logger.Error().Err(err).Msg("Failed to book appointment")
+		return httpErr
+	}
 
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create appointment")
@@ -53,11 +199,11 @@ func (s *APIServer) handlePostAppointment(c echo.Context) error {
 
 	logger.Info().Int("appointment_id", res.ID).Msg("Appointment created")
 
-	return c.JSON(http.StatusCreated, res)
+	return c.JSON(http.StatusCreated, res.In(loc))
 }
 
-func (s *APIServer) handleGetTrainerAppointments(c echo.Context) error {
-	req := new(GetTrainerAppointmentsReq)
+func (s *APIServer) handleGetAppointment(c echo.Context) error {
+	req := new(GetAppointmentReq)
 	logger := GetEchoLogger(c)
 
 	if err := c.Bind(req); err != nil {
@@ -70,30 +216,71 @@ func (s *APIServer) handleGetTrainerAppointments(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	parsedStartsAt := time.Time{}
-	parsedEndsAt := time.Time{}
+	res, err := s.store.GetAppointmentDetails(req.ID)
 
-	if req.StartsAt != "" && req.EndsAt != "" {
-		parsedStartsAt, _ = models.ParseDateStr(req.StartsAt)
-		parsedEndsAt, _ = models.ParseDateStr(req.EndsAt)
+	if errors.Is(err, store.ErrAppointmentNotFound) {
+		logger.Error().Err(err).Msg("Failed to find appointment")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
 
-	appointments, err := s.store.GetAppointmentsByTrainerID(
-		req.TrainerID,
-		parsedStartsAt,
-		parsedEndsAt,
-	)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get appointment")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 
+	loc, err := getResponseLocation(c, res.StartsAt.Location())
 	if err != nil {
-		logger.Error().Err(err).Msg("Failed to get appointments")
+		logger.Error().Err(err).Msg("Failed to validate request")
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	return c.JSON(http.StatusOK, appointments)
+	return c.JSON(http.StatusOK, res.In(loc))
 }
 
-func (s *APIServer) handleGetTrainerAvailability(c echo.Context) error {
-	req := new(GetTrainerAvailabilityReq)
+func (s *APIServer) handleGetAppointmentHistory(c echo.Context) error {
+	req := new(GetAppointmentHistoryReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// INFO: Changes are already in the trainer's time zone unless another one is requested
+	loc, err := getResponseLocation(c, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := s.store.GetAppointmentHistory(req.ID)
+
+	if errors.Is(err, store.ErrAppointmentNotFound) {
+		logger.Error().Err(err).Msg("Failed to find appointment")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get appointment history")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if loc != nil {
+		for i, change := range res {
+			res[i] = change.In(loc)
+		}
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handlePatchAppointment(c echo.Context) error {
+	req := new(PatchAppointmentReq)
 	logger := GetEchoLogger(c)
 
 	if err := c.Bind(req); err != nil {
@@ -109,16 +296,1774 @@ func (s *APIServer) handleGetTrainerAvailability(c echo.Context) error {
 	parsedStartsAt, _ := models.ParseDateStr(req.StartsAt)
 	parsedEndsAt, _ := models.ParseDateStr(req.EndsAt)
 
-	timeSlots, err := s.store.GetTrainerAvailability(
-		req.TrainerID,
-		parsedStartsAt,
-		parsedEndsAt,
-	)
+	// INFO: The tz parameter is checked before the change is saved
+	loc, err := getResponseLocation(c, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("appointment_id", req.ID).Msg("Rescheduling appointment")
+
+	res, err := s.auditedStore(c).RescheduleAppointment(req.ID, parsedStartsAt, parsedEndsAt)
+
+	if errors.Is(err, store.ErrAppointmentNotFound) {
+		logger.Error().Err(err).Msg("Failed to find appointment")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if httpErr := NewParticipantError(err); httpErr != nil {
+		logger.Error().Err(err).Msg("Failed to validate participants")
+		return httpErr
+	}
+
+	if errors.Is(err, store.ErrTimeslotUnavailable) {
+		logger.Error().Err(err).Msg("Failed to book timeslot")
+		return NewConflictError(ErrCodeTimeslotUnavailable, err)
+	}
+
+	if errors.Is(err, store.ErrResourceUnavailable) {
+		logger.Error().Err(err).Msg("Failed to book resources")
+		return NewConflictError(ErrCodeResourceUnavailable, err)
+	}
+
+	if httpErr := NewResourceError(err); httpErr != nil {
+		logger.Error().Err(err).Msg("Failed to validate resources")
+		return httpErr
+	}
 
 	if err != nil {
-		logger.Error().Err(err).Msg("Failed to get availability")
+		logger.Error().Err(err).Msg("Failed to reschedule appointment")
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	return c.JSON(http.StatusOK, timeSlots)
+	logger.Info().Int("appointment_id", res.ID).Msg("Appointment rescheduled")
+
+	if loc == nil {
+		loc = res.StartsAt.Location()
+	}
+
+	return c.JSON(http.StatusOK, res.In(loc))
+}
+
+func (s *APIServer) handleDeleteAppointment(c echo.Context) error {
+	req := new(DeleteAppointmentReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// INFO: The tz parameter is checked before the change is saved
+	loc, err := getResponseLocation(c, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("appointment_id", req.ID).Msg("Cancelling appointment")
+
+	res, err := s.auditedStore(c).CancelAppointment(req.ID)
+
+	if errors.Is(err, store.ErrAppointmentNotFound) {
+		logger.Error().Err(err).Msg("Failed to find appointment")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to cancel appointment")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("appointment_id", res.ID).Msg("Appointment cancelled")
+
+	if loc == nil {
+		loc = res.StartsAt.Location()
+	}
+
+	return c.JSON(http.StatusOK, res.In(loc))
+}
+
+func (s *APIServer) handlePostAppointmentSeries(c echo.Context) error {
+	req := new(PostAppointmentSeriesReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateUser(req.UserID, true); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate user")
+		return err
+	}
+
+	if err := s.validateTrainer(req.TrainerID, true); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	parsedStartsAt, _ := models.ParseDateStr(req.StartsAt)
+	parsedEndsAt, _ := models.ParseDateStr(req.EndsAt)
+
+	var recurrence *models.Recurrence
+	var err error
+
+	if req.RRule != "" {
+		recurrence, err = models.ParseRecurrence(req.RRule)
+	} else {
+		recurrence, err = models.NewRecurrence(req.Frequency, req.Count, req.Until)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate recurrence")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	loc, err := getResponseLocation(c, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Str("rrule", recurrence.String()).Msg("Creating appointment series")
+
+	res, err := s.auditedStore(c).CreateAppointmentSeries(req.UserID, req.TrainerID, parsedStartsAt, parsedEndsAt, recurrence)
+
+	if errors.Is(err, store.ErrTimeslotUnavailable) {
+		logger.Error().Err(err).Msg("Failed to book any occurrence")
+		return NewConflictError(ErrCodeTimeslotUnavailable, err)
+	}
+
+	if httpErr := NewParticipantError(err); httpErr != nil {
+		logger.Error().Err(err).Msg("Failed to validate participants")
+		return httpErr
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create appointment series")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().
+		Int("series_id", res.Series.ID).
+		Int("booked", len(res.Booked)).
+		Int("conflicts", len(res.Conflicts)).
+		Msg("Appointment series created")
+
+	return c.JSON(http.StatusCreated, res.In(loc))
+}
+
+func (s *APIServer) handlePatchFollowingAppointments(c echo.Context) error {
+	req := new(PatchAppointmentReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	parsedStartsAt, _ := models.ParseDateStr(req.StartsAt)
+	parsedEndsAt, _ := models.ParseDateStr(req.EndsAt)
+
+	// INFO: The tz parameter is checked before the change is saved
+	loc, err := getResponseLocation(c, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("appointment_id", req.ID).Msg("Rescheduling following appointments")
+
+	res, err := s.auditedStore(c).RescheduleFollowingAppointments(req.ID, parsedStartsAt, parsedEndsAt)
+
+	if errors.Is(err, store.ErrAppointmentNotFound) {
+		logger.Error().Err(err).Msg("Failed to find appointment")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if httpErr := NewParticipantError(err); httpErr != nil {
+		logger.Error().Err(err).Msg("Failed to validate participants")
+		return httpErr
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to reschedule following appointments")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().
+		Int("booked", len(res.Booked)).
+		Int("conflicts", len(res.Conflicts)).
+		Msg("Following appointments rescheduled")
+
+	// INFO: Occurrences are returned in the trainer's time zone
+	if loc == nil {
+		loc = parsedStartsAt.Location()
+		if len(res.Booked) > 0 {
+			loc = res.Booked[0].StartsAt.Location()
+		} else if len(res.Conflicts) > 0 {
+			loc = res.Conflicts[0].StartsAt.Location()
+		}
+	}
+
+	return c.JSON(http.StatusOK, res.In(loc))
+}
+
+func (s *APIServer) handleDeleteFollowingAppointments(c echo.Context) error {
+	req := new(DeleteAppointmentReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// INFO: The tz parameter is checked before the change is saved
+	loc, err := getResponseLocation(c, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("appointment_id", req.ID).Msg("Cancelling following appointments")
+
+	res, err := s.auditedStore(c).CancelFollowingAppointments(req.ID)
+
+	if errors.Is(err, store.ErrAppointmentNotFound) {
+		logger.Error().Err(err).Msg("Failed to find appointment")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to cancel following appointments")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("cancelled", len(res)).Msg("Following appointments cancelled")
+
+	if len(res) == 0 {
+		return c.JSON(http.StatusOK, res)
+	}
+
+	if loc == nil {
+		loc = res[0].StartsAt.Location()
+	}
+
+	for i, appointment := range res {
+		res[i] = appointment.In(loc)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleGetTrainerAppointments(c echo.Context) error {
+	req := new(GetTrainerAppointmentsReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, true); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	page, err := models.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	parsedStartsAt := time.Time{}
+	parsedEndsAt := time.Time{}
+
+	if req.StartsAt != "" && req.EndsAt != "" {
+		parsedStartsAt, _ = models.ParseDateStr(req.StartsAt)
+		parsedEndsAt, _ = models.ParseDateStr(req.EndsAt)
+	}
+
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	loc, err := getResponseLocation(c, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	appointments, next, err := s.store.GetAppointmentsByTrainerID(
+		req.TrainerID,
+		parsedStartsAt,
+		parsedEndsAt,
+		page,
+	)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get appointments")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	for i, appointment := range appointments {
+		appointments[i] = appointment.In(loc)
+	}
+
+	setNextPage(c, next)
+	return c.JSON(http.StatusOK, appointments)
+}
+
+func (s *APIServer) handleGetUserAppointments(c echo.Context) error {
+	req := new(GetUserAppointmentsReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateUser(req.UserID, true); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate user")
+		return err
+	}
+
+	page, err := models.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	parsedStartsAt := time.Time{}
+	parsedEndsAt := time.Time{}
+
+	if req.StartsAt != "" && req.EndsAt != "" {
+		parsedStartsAt, _ = models.ParseDateStr(req.StartsAt)
+		parsedEndsAt, _ = models.ParseDateStr(req.EndsAt)
+	}
+
+	// INFO: Without a tz query parameter each appointment stays in its trainer's time zone
+	loc, err := getResponseLocation(c, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	appointments, next, err := s.store.GetAppointmentsByUserID(
+		req.UserID,
+		parsedStartsAt,
+		parsedEndsAt,
+		req.Filter,
+		page,
+	)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get appointments")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if loc != nil {
+		for i, appointment := range appointments {
+			appointments[i] = appointment.In(loc)
+		}
+	}
+
+	setNextPage(c, next)
+	return c.JSON(http.StatusOK, appointments)
+}
+
+func (s *APIServer) handleGetTrainerAvailability(c echo.Context) error {
+	req := new(GetTrainerAvailabilityReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, true); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	if req.UserID != 0 {
+		if err := s.validateUser(req.UserID, true); err != nil {
+			logger.Error().Err(err).Msg("Failed to validate user")
+			return err
+		}
+	}
+
+	for _, resourceID := range req.ResourceIDs {
+		if err := s.validateResource(resourceID, true); err != nil {
+			logger.Error().Err(err).Msg("Failed to validate resource")
+			return err
+		}
+	}
+
+	parsedStartsAt, _ := models.ParseDateStr(req.StartsAt)
+	parsedEndsAt, _ := models.ParseDateStr(req.EndsAt)
+
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	loc, err := getResponseLocation(c, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	duration := settings.DefaultDuration()
+	if req.Duration != 0 {
+		duration = time.Duration(req.Duration) * time.Minute
+	}
+
+	if !settings.AllowsDuration(duration) {
+		err := fmt.Errorf("Duration must be one of %s minutes", settings.DurationsString())
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	timeSlots, err := s.store.GetTrainerAvailability(
+		req.TrainerID,
+		parsedStartsAt,
+		parsedEndsAt,
+		duration,
+		req.UserID,
+		req.ResourceIDs,
+	)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get availability")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	for i, timeslot := range *timeSlots {
+		(*timeSlots)[i] = timeslot.In(loc)
+	}
+
+	return c.JSON(http.StatusOK, timeSlots)
+}
+
+func (s *APIServer) handleGetAvailability(c echo.Context) error {
+	req := new(GetAvailabilityReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	for _, trainerID := range req.TrainerIDs {
+		if err := s.validateTrainer(trainerID, true); err != nil {
+			logger.Error().Err(err).Msg("Failed to validate trainer")
+			return err
+		}
+	}
+
+	if req.UserID != 0 {
+		if err := s.validateUser(req.UserID, true); err != nil {
+			logger.Error().Err(err).Msg("Failed to validate user")
+			return err
+		}
+	}
+
+	for _, resourceID := range req.ResourceIDs {
+		if err := s.validateResource(resourceID, true); err != nil {
+			logger.Error().Err(err).Msg("Failed to validate resource")
+			return err
+		}
+	}
+
+	loc, err := getResponseLocation(c, models.DefaultLocation())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	parsedStartsAt, _ := models.ParseDateStr(req.StartsAt)
+	parsedEndsAt, _ := models.ParseDateStr(req.EndsAt)
+
+	duration := time.Duration(models.DefaultDurations[0]) * time.Minute
+	if req.Duration != 0 {
+		duration = time.Duration(req.Duration) * time.Minute
+	}
+
+	timeslots, err := s.store.GetAvailability(
+		req.TrainerIDs,
+		parsedStartsAt,
+		parsedEndsAt,
+		duration,
+		req.UserID,
+		req.ResourceIDs,
+		req.First,
+	)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get availability")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	for i, timeslot := range timeslots {
+		timeslots[i] = timeslot.In(loc)
+	}
+
+	return c.JSON(http.StatusOK, timeslots)
+}
+
+func (s *APIServer) handleGetTrainerWorkingHours(c echo.Context) error {
+	req := new(GetTrainerWorkingHoursReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	schedule, err := s.store.GetTrainerWorkingHours(req.TrainerID)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get working hours")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, schedule)
+}
+
+func (s *APIServer) handlePutTrainerWorkingHours(c echo.Context) error {
+	req := new(PutTrainerWorkingHoursReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	workingHours := make([]models.WorkingHours, 0, len(req.WorkingHours))
+	for _, interval := range req.WorkingHours {
+		wh, err := models.NewWorkingHours(*interval.Weekday, interval.StartTime, interval.EndTime)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to create working hours")
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		workingHours = append(workingHours, *wh)
+	}
+
+	schedule, err := models.NewWeeklySchedule(workingHours)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create working hours")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("trainer_id", req.TrainerID).Interface("working_hours", schedule).Msg("Updating working hours")
+
+	res, err := s.store.SetTrainerWorkingHours(req.TrainerID, schedule)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update working hours")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleGetTrainerSettings(c echo.Context) error {
+	req := new(GetTrainerSettingsReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
+
+func (s *APIServer) handlePutTrainerSettings(c echo.Context) error {
+	req := new(PutTrainerSettingsReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	settings, err := models.NewTrainerSettings(
+		req.TrainerID,
+		req.TimeZone,
+		req.Durations,
+		req.Capacity,
+		req.BufferBefore,
+		req.BufferAfter,
+	)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Interface("settings", settings).Msg("Updating trainer settings")
+
+	res, err := s.store.SetTrainerSettings(settings)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleGetTrainerTimeOff(c echo.Context) error {
+	req := new(GetTrainerTimeOffReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	parsedStartsAt := time.Time{}
+	parsedEndsAt := time.Time{}
+
+	if req.StartsAt != "" && req.EndsAt != "" {
+		parsedStartsAt, _ = models.ParseDateStr(req.StartsAt)
+		parsedEndsAt, _ = models.ParseDateStr(req.EndsAt)
+	}
+
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	loc, err := getResponseLocation(c, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	timeOff, err := s.store.GetTimeOffByTrainerID(req.TrainerID, parsedStartsAt, parsedEndsAt)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get time off")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	for i, entry := range timeOff {
+		timeOff[i] = entry.In(loc)
+	}
+
+	return c.JSON(http.StatusOK, timeOff)
+}
+
+func (s *APIServer) handlePostTrainerTimeOff(c echo.Context) error {
+	req := new(PostTrainerTimeOffReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	parsedStartsAt, _ := models.ParseDateStr(req.StartsAt)
+	parsedEndsAt, _ := models.ParseDateStr(req.EndsAt)
+
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	loc, err := getResponseLocation(c, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	timeOff, err := models.NewTimeOff(req.TrainerID, parsedStartsAt, parsedEndsAt, req.AllDay, req.Reason, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create time off")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Interface("time_off", timeOff).Msg("Creating time off")
+
+	res, err := s.store.CreateTimeOff(timeOff)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create time off")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("time_off_id", res.ID).Msg("Time off created")
+
+	return c.JSON(http.StatusCreated, res.In(loc))
+}
+
+func (s *APIServer) handlePutTrainerTimeOff(c echo.Context) error {
+	req := new(PutTrainerTimeOffReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	parsedStartsAt, _ := models.ParseDateStr(req.StartsAt)
+	parsedEndsAt, _ := models.ParseDateStr(req.EndsAt)
+
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	loc, err := getResponseLocation(c, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	timeOff, err := models.NewTimeOff(req.TrainerID, parsedStartsAt, parsedEndsAt, req.AllDay, req.Reason, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update time off")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	timeOff.ID = req.ID
+
+	logger.Info().Interface("time_off", timeOff).Msg("Updating time off")
+
+	res, err := s.store.UpdateTimeOff(timeOff)
+
+	if errors.Is(err, store.ErrTimeOffNotFound) {
+		logger.Error().Err(err).Msg("Failed to find time off")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update time off")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res.In(loc))
+}
+
+func (s *APIServer) handleDeleteTrainerTimeOff(c echo.Context) error {
+	req := new(DeleteTrainerTimeOffReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	logger.Info().Int("time_off_id", req.ID).Msg("Deleting time off")
+
+	err := s.store.DeleteTimeOff(req.TrainerID, req.ID)
+
+	if errors.Is(err, store.ErrTimeOffNotFound) {
+		logger.Error().Err(err).Msg("Failed to find time off")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to delete time off")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *APIServer) handleGetTrainerWaitlist(c echo.Context) error {
+	req := new(GetTrainerWaitlistReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateTrainer(req.TrainerID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	loc, err := getResponseLocation(c, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	entries, err := s.store.GetWaitlistByTrainerID(req.TrainerID)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get waitlist")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	for i, entry := range entries {
+		entries[i] = entry.In(loc)
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (s *APIServer) handlePostTrainerWaitlist(c echo.Context) error {
+	req := new(PostTrainerWaitlistReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateUser(req.UserID, true); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate user")
+		return err
+	}
+
+	if err := s.validateTrainer(req.TrainerID, true); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate trainer")
+		return err
+	}
+
+	parsedStartsAt, _ := models.ParseDateStr(req.StartsAt)
+	parsedEndsAt, _ := models.ParseDateStr(req.EndsAt)
+
+	settings, err := s.store.GetTrainerSettings(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer settings")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	loc, err := getResponseLocation(c, settings.Location())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	schedule, err := s.store.GetTrainerWorkingHours(req.TrainerID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get working hours")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	entry, err := models.NewWaitlistEntry(req.UserID, req.TrainerID, parsedStartsAt, parsedEndsAt, req.AutoBook, settings, schedule)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create waitlist entry")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Interface("waitlist_entry", entry).Msg("Joining waitlist")
+
+	res, err := s.auditedStore(c).CreateWaitlistEntry(entry)
+
+	if errors.Is(err, store.ErrAlreadyWaitlisted) {
+		logger.Error().Err(err).Msg("Failed to join waitlist")
+		return NewConflictError(ErrCodeAlreadyWaitlisted, err)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create waitlist entry")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("waitlist_entry_id", res.ID).Str("status", res.Status).Msg("Waitlist entry created")
+
+	return c.JSON(http.StatusCreated, res.In(loc))
+}
+
+func (s *APIServer) handleAcceptWaitlistOffer(c echo.Context) error {
+	req := new(WaitlistEntryReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// INFO: The tz parameter is checked before the change is saved
+	loc, err := getResponseLocation(c, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("waitlist_entry_id", req.ID).Msg("Accepting waitlist offer")
+
+	res, err := s.auditedStore(c).AcceptWaitlistOffer(req.ID)
+
+	if errors.Is(err, store.ErrWaitlistEntryNotFound) {
+		logger.Error().Err(err).Msg("Failed to find waitlist entry")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if errors.Is(err, store.ErrWaitlistOfferExpired) {
+		logger.Error().Err(err).Msg("Failed to accept waitlist offer")
+		return NewConflictError(ErrCodeWaitlistOfferExpired, err)
+	}
+
+	if errors.Is(err, store.ErrWaitlistOfferUnavailable) {
+		logger.Error().Err(err).Msg("Failed to accept waitlist offer")
+		return NewConflictError(ErrCodeWaitlistOfferUnavailable, err)
+	}
+
+	// INFO: Accepting an offer books the slot, so it fails the same way as POST /appointments
+	if httpErr := NewBookingError(err); httpErr != nil {
+		logger.Error().Err(err).Msg("Failed to book appointment")
+		return httpErr
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to accept waitlist offer")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("appointment_id", res.ID).Msg("Appointment created")
+
+	if loc == nil {
+		loc = res.StartsAt.Location()
+	}
+
+	return c.JSON(http.StatusCreated, res.In(loc))
+}
+
+func (s *APIServer) handleDeleteWaitlistEntry(c echo.Context) error {
+	req := new(WaitlistEntryReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// INFO: The tz parameter is checked before the change is saved
+	loc, err := getResponseLocation(c, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("waitlist_entry_id", req.ID).Msg("Leaving waitlist")
+
+	res, err := s.auditedStore(c).CancelWaitlistEntry(req.ID)
+
+	if errors.Is(err, store.ErrWaitlistEntryNotFound) {
+		logger.Error().Err(err).Msg("Failed to find waitlist entry")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to cancel waitlist entry")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if loc == nil {
+		loc = res.StartsAt.Location()
+	}
+
+	return c.JSON(http.StatusOK, res.In(loc))
+}
+
+func (s *APIServer) handleGetHolidays(c echo.Context) error {
+	req := new(GetHolidaysReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	holidays, err := s.store.GetHolidays(req.From, req.To)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get holidays")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, holidays)
+}
+
+func (s *APIServer) handlePostHoliday(c echo.Context) error {
+	req := new(PostHolidayReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	holiday, err := models.NewHoliday(req.Date, req.Name, req.StartTime, req.EndTime)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create holiday")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Interface("holiday", holiday).Msg("Creating holiday")
+
+	res, err := s.store.CreateHoliday(holiday)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create holiday")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("holiday_id", res.ID).Msg("Holiday created")
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (s *APIServer) handlePutHoliday(c echo.Context) error {
+	req := new(PutHolidayReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	holiday, err := models.NewHoliday(req.Date, req.Name, req.StartTime, req.EndTime)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update holiday")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	holiday.ID = req.ID
+
+	logger.Info().Interface("holiday", holiday).Msg("Updating holiday")
+
+	res, err := s.store.UpdateHoliday(holiday)
+
+	if errors.Is(err, store.ErrHolidayNotFound) {
+		logger.Error().Err(err).Msg("Failed to find holiday")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update holiday")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleDeleteHoliday(c echo.Context) error {
+	req := new(DeleteHolidayReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("holiday_id", req.ID).Msg("Deleting holiday")
+
+	err := s.store.DeleteHoliday(req.ID)
+
+	if errors.Is(err, store.ErrHolidayNotFound) {
+		logger.Error().Err(err).Msg("Failed to find holiday")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to delete holiday")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *APIServer) handleGetUsers(c echo.Context) error {
+	logger := GetEchoLogger(c)
+
+	users, err := s.store.GetUsers()
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get users")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, users)
+}
+
+func (s *APIServer) handleGetUser(c echo.Context) error {
+	req := new(GetUserReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	user, err := s.store.GetUser(req.UserID)
+
+	if errors.Is(err, store.ErrUserNotFound) {
+		logger.Error().Err(err).Msg("Failed to find user")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get user")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+func (s *APIServer) handlePostUser(c echo.Context) error {
+	req := new(PostUserReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	active := req.Active == nil || *req.Active
+
+	user, err := models.NewUser(req.Name, req.Email, active)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create user")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := s.store.CreateUser(user)
+
+	if errors.Is(err, store.ErrEmailTaken) {
+		logger.Error().Err(err).Msg("Failed to create user")
+		return NewConflictError(ErrCodeEmailTaken, err)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create user")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("user_id", res.ID).Msg("User created")
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (s *APIServer) handlePutUser(c echo.Context) error {
+	req := new(PutUserReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	user, err := models.NewUser(req.Name, req.Email, *req.Active)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update user")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	user.ID = req.UserID
+
+	res, err := s.store.UpdateUser(user)
+
+	if errors.Is(err, store.ErrUserNotFound) {
+		logger.Error().Err(err).Msg("Failed to find user")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if errors.Is(err, store.ErrEmailTaken) {
+		logger.Error().Err(err).Msg("Failed to update user")
+		return NewConflictError(ErrCodeEmailTaken, err)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update user")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleDeleteUser(c echo.Context) error {
+	req := new(GetUserReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("user_id", req.UserID).Msg("Deactivating user")
+
+	res, err := s.store.DeactivateUser(req.UserID)
+
+	if errors.Is(err, store.ErrUserNotFound) {
+		logger.Error().Err(err).Msg("Failed to find user")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to deactivate user")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleGetTrainers(c echo.Context) error {
+	logger := GetEchoLogger(c)
+
+	trainers, err := s.store.GetTrainers()
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainers")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, trainers)
+}
+
+func (s *APIServer) handleGetTrainer(c echo.Context) error {
+	req := new(GetTrainerReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	trainer, err := s.store.GetTrainer(req.TrainerID)
+
+	if errors.Is(err, store.ErrTrainerNotFound) {
+		logger.Error().Err(err).Msg("Failed to find trainer")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get trainer")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, trainer)
+}
+
+func (s *APIServer) handlePostTrainer(c echo.Context) error {
+	req := new(PostTrainerReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	active := req.Active == nil || *req.Active
+
+	trainer, err := models.NewTrainer(req.Name, req.Email, active)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create trainer")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := s.store.CreateTrainer(trainer)
+
+	if errors.Is(err, store.ErrEmailTaken) {
+		logger.Error().Err(err).Msg("Failed to create trainer")
+		return NewConflictError(ErrCodeEmailTaken, err)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create trainer")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("trainer_id", res.ID).Msg("Trainer created")
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (s *APIServer) handlePutTrainer(c echo.Context) error {
+	req := new(PutTrainerReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	trainer, err := models.NewTrainer(req.Name, req.Email, *req.Active)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update trainer")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	trainer.ID = req.TrainerID
+
+	res, err := s.store.UpdateTrainer(trainer)
+
+	if errors.Is(err, store.ErrTrainerNotFound) {
+		logger.Error().Err(err).Msg("Failed to find trainer")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if errors.Is(err, store.ErrEmailTaken) {
+		logger.Error().Err(err).Msg("Failed to update trainer")
+		return NewConflictError(ErrCodeEmailTaken, err)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update trainer")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleDeleteTrainer(c echo.Context) error {
+	req := new(GetTrainerReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("trainer_id", req.TrainerID).Msg("Deactivating trainer")
+
+	res, err := s.store.DeactivateTrainer(req.TrainerID)
+
+	if errors.Is(err, store.ErrTrainerNotFound) {
+		logger.Error().Err(err).Msg("Failed to find trainer")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to deactivate trainer")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleGetResources(c echo.Context) error {
+	logger := GetEchoLogger(c)
+
+	resources, err := s.store.GetResources()
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get resources")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resources)
+}
+
+func (s *APIServer) handleGetResource(c echo.Context) error {
+	req := new(GetResourceReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	resource, err := s.store.GetResource(req.ResourceID)
+
+	if errors.Is(err, store.ErrResourceNotFound) {
+		logger.Error().Err(err).Msg("Failed to find resource")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get resource")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resource)
+}
+
+func (s *APIServer) handlePostResource(c echo.Context) error {
+	req := new(PostResourceReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	active := req.Active == nil || *req.Active
+
+	resource, err := models.NewResource(req.Name, req.Kind, active)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create resource")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res, err := s.store.CreateResource(resource)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create resource")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("resource_id", res.ID).Msg("Resource created")
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (s *APIServer) handlePutResource(c echo.Context) error {
+	req := new(PutResourceReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	resource, err := models.NewResource(req.Name, req.Kind, *req.Active)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update resource")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	resource.ID = req.ResourceID
+
+	res, err := s.store.UpdateResource(resource)
+
+	if errors.Is(err, store.ErrResourceNotFound) {
+		logger.Error().Err(err).Msg("Failed to find resource")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to update resource")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleDeleteResource(c echo.Context) error {
+	req := new(GetResourceReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	logger.Info().Int("resource_id", req.ResourceID).Msg("Deactivating resource")
+
+	res, err := s.store.DeactivateResource(req.ResourceID)
+
+	if errors.Is(err, store.ErrResourceNotFound) {
+		logger.Error().Err(err).Msg("Failed to find resource")
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to deactivate resource")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *APIServer) handleGetResourceAppointments(c echo.Context) error {
+	req := new(GetResourceAppointmentsReq)
+	logger := GetEchoLogger(c)
+
+	if err := c.Bind(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to bind request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.validateResource(req.ResourceID, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to validate resource")
+		return err
+	}
+
+	page, err := models.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	parsedStartsAt := time.Time{}
+	parsedEndsAt := time.Time{}
+
+	if req.StartsAt != "" && req.EndsAt != "" {
+		parsedStartsAt, _ = models.ParseDateStr(req.StartsAt)
+		parsedEndsAt, _ = models.ParseDateStr(req.EndsAt)
+	}
+
+	// INFO: Without a tz query parameter each appointment stays in its trainer's time zone
+	loc, err := getResponseLocation(c, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to validate request")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	appointments, next, err := s.store.GetAppointmentsByResourceID(
+		req.ResourceID,
+		parsedStartsAt,
+		parsedEndsAt,
+		page,
+	)
+
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get appointments")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if loc != nil {
+		for i, appointment := range appointments {
+			appointments[i] = appointment.In(loc)
+		}
+	}
+
+	setNextPage(c, next)
+	return c.JSON(http.StatusOK, appointments)
 }
@@ -3,6 +3,7 @@ package server
 import (
 	s "future-app/store"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -11,10 +12,10 @@ import (
 type APIServer struct {
 	echo  *echo.Echo
 	port  string
-	store *s.Store
+	store s.Repository
 }
 
-func NewAPIServer(port string, store *s.Store) *APIServer {
+func NewAPIServer(port string, store s.Repository) *APIServer {
 	e := echo.New()
 	NewLogger()
 
@@ -30,13 +31,73 @@ func NewAPIServer(port string, store *s.Store) *APIServer {
 		return c.JSON(http.StatusOK, map[string]string{"status": "OK"})
 	})
 
+	e.GET("/users", s.handleGetUsers)
+	e.POST("/users", s.handlePostUser)
+	e.GET("/users/:user_id", s.handleGetUser)
+	e.PUT("/users/:user_id", s.handlePutUser)
+	e.DELETE("/users/:user_id", s.handleDeleteUser)
+	e.GET("/users/:user_id/appointments", s.handleGetUserAppointments)
+	e.GET("/trainers", s.handleGetTrainers)
+	e.POST("/trainers", s.handlePostTrainer)
+	e.GET("/trainers/:trainer_id", s.handleGetTrainer)
+	e.PUT("/trainers/:trainer_id", s.handlePutTrainer)
+	e.DELETE("/trainers/:trainer_id", s.handleDeleteTrainer)
 	e.POST("/appointments", s.handlePostAppointment)
+	e.POST("/appointments/series", s.handlePostAppointmentSeries)
+	e.GET("/appointments/:id", s.handleGetAppointment)
+	e.GET("/appointments/:id/history", s.handleGetAppointmentHistory)
+	e.PATCH("/appointments/:id", s.handlePatchAppointment)
+	e.PATCH("/appointments/:id/following", s.handlePatchFollowingAppointments)
+	e.DELETE("/appointments/:id", s.handleDeleteAppointment)
+	e.DELETE("/appointments/:id/following", s.handleDeleteFollowingAppointments)
 	e.GET("/trainers/:trainer_id/appointments", s.handleGetTrainerAppointments)
 	e.GET("/trainers/:trainer_id/availability", s.handleGetTrainerAvailability)
+	e.GET("/trainers/:trainer_id/working-hours", s.handleGetTrainerWorkingHours)
+	e.PUT("/trainers/:trainer_id/working-hours", s.handlePutTrainerWorkingHours)
+	e.GET("/trainers/:trainer_id/settings", s.handleGetTrainerSettings)
+	e.PUT("/trainers/:trainer_id/settings", s.handlePutTrainerSettings)
+	e.GET("/trainers/:trainer_id/time-off", s.handleGetTrainerTimeOff)
+	e.POST("/trainers/:trainer_id/time-off", s.handlePostTrainerTimeOff)
+	e.PUT("/trainers/:trainer_id/time-off/:id", s.handlePutTrainerTimeOff)
+	e.DELETE("/trainers/:trainer_id/time-off/:id", s.handleDeleteTrainerTimeOff)
+	e.GET("/trainers/:trainer_id/waitlist", s.handleGetTrainerWaitlist)
+	e.POST("/trainers/:trainer_id/waitlist", s.handlePostTrainerWaitlist)
+	e.POST("/waitlist/:id/accept", s.handleAcceptWaitlistOffer)
+	e.DELETE("/waitlist/:id", s.handleDeleteWaitlistEntry)
+	e.GET("/availability", s.handleGetAvailability)
+	e.GET("/resources", s.handleGetResources)
+	e.POST("/resources", s.handlePostResource)
+	e.GET("/resources/:resource_id", s.handleGetResource)
+	e.PUT("/resources/:resource_id", s.handlePutResource)
+	e.DELETE("/resources/:resource_id", s.handleDeleteResource)
+	e.GET("/resources/:resource_id/appointments", s.handleGetResourceAppointments)
+	e.GET("/holidays", s.handleGetHolidays)
+	e.POST("/holidays", s.handlePostHoliday)
+	e.PUT("/holidays/:id", s.handlePutHoliday)
+	e.DELETE("/holidays/:id", s.handleDeleteHoliday)
 
 	return s
 }
 
+// waitlistExpiryInterval is how often lapsed waitlist offers are moved on to
+// the next user in the queue.
+const waitlistExpiryInterval = time.Minute
+
 func (s *APIServer) Run() {
+	go s.expireWaitlistOffers(waitlistExpiryInterval)
+
 	Logger.Fatal().Msg(s.echo.Start(s.port).Error())
 }
+
+// expireWaitlistOffers passes lapsed waitlist offers on in the background, so
+// that reads never have to book appointments.
+func (s *APIServer) expireWaitlistOffers(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.store.ExpireWaitlistOffers(); err != nil {
+			Logger.Error().Err(err).Msg("Failed to expire waitlist offers")
+		}
+	}
+}
@@ -11,13 +11,14 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
 
-var testStore *store.Store
+var testStore store.Repository
 var apiServer *APIServer
 
 func setup() error {
@@ -31,17 +32,35 @@ func setup() error {
 		port = "8081"
 	}
 
-	db, err := store.NewTestStore()
-	if err != nil {
-		return err
+	// INFO: TEST_STORE=memory runs the suite against the in-memory backend
+	if os.Getenv("TEST_STORE") == store.BackendMemory {
+		testStore = store.NewMemoryStore()
+	} else {
+		db, err := store.NewTestStore()
+		if err != nil {
+			return err
+		}
+		testStore = db
 	}
 
-	testStore = db
 	err = testStore.Init()
 	if err != nil {
 		return err
 	}
 
+	// INFO: Tests book for users 1-10 and trainers 1-5
+	for i := 1; i <= 10; i++ {
+		if _, err := testStore.CreateUser(&models.User{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i), Active: true}); err != nil {
+			return err
+		}
+	}
+
+	for i := 1; i <= 5; i++ {
+		if _, err := testStore.CreateTrainer(&models.Trainer{Name: fmt.Sprintf("Trainer %d", i), Email: fmt.Sprintf("trainer%d@example.com", i), Active: true}); err != nil {
+			return err
+		}
+	}
+
 	apiServer = NewAPIServer(fmt.Sprintf(":%s", port), testStore)
 
 	return nil
@@ -80,7 +99,7 @@ func TestPostAppointment(t *testing.T) {
 		}
 	})
 
-	t.Run("Valid dates, timezone should be changed to PST -8", func(t *testing.T) {
+	t.Run("Valid dates, timezone should be changed to trainer's time zone", func(t *testing.T) {
 		body := `{
         "user_id":    1,
         "trainer_id": 1,
@@ -98,15 +117,37 @@ func TestPostAppointment(t *testing.T) {
             "id":1,
             "user_id":1,
             "trainer_id":1,
-            "starts_at":"2030-07-08T12:00:00-08:00",
-            "ends_at":"2030-07-08T12:30:00-08:00"
+            "starts_at":"2030-07-08T13:00:00-07:00",
+            "ends_at":"2030-07-08T13:30:00-07:00",
+            "status":"scheduled"
             }`
 			assert.JSONEq(t, expectedBody, rec.Body.String())
 		}
 	})
+
+	t.Run("Timeslot already booked", func(t *testing.T) {
+		body := `{
+        "user_id":    2,
+        "trainer_id": 1,
+        "starts_at": "2030-07-08T20:00:00Z",
+        "ends_at":   "2030-07-08T20:30:00Z"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostAppointment(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if assert.True(t, ok) {
+				assert.Equal(t, http.StatusConflict, he.Code)
+				assert.Equal(t, ErrorResponse{Code: ErrCodeTimeslotUnavailable, Message: "Timeslot is not available"}, he.Message)
+			}
+		}
+	})
 }
 
-func TestGetTrainerAppointments(t *testing.T) {
+func TestPatchAppointment(t *testing.T) {
 	err := setup()
 	if err != nil {
 		t.Fatalf("failed to setup test: %v", err)
@@ -115,37 +156,52 @@ func TestGetTrainerAppointments(t *testing.T) {
 
 	e := apiServer.echo
 
-	t.Run("Invalid date format", func(t *testing.T) {
-		q := make(url.Values)
-		q.Set("starts_at", "2020-07-08")
-		q.Set("ends_at", "2020-07-08")
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?"+q.Encode(), nil)
+	// INFO: Create test appointment
+	appointment, err := testStore.CreateAppointment(&models.Appointment{
+		UserID:    1,
+		TrainerID: 1,
+		StartsAt:  time.Date(2030, 7, 8, 12, 0, 0, 0, models.DefaultLocation()),
+		EndsAt:    time.Date(2030, 7, 8, 12, 30, 0, 0, models.DefaultLocation()),
+	})
+	if err != nil {
+		t.Fatalf("failed to create appointment: %v", err)
+	}
+
+	t.Run("Appointment not found", func(t *testing.T) {
+		body := `{
+        "starts_at": "2030-07-08T13:00:00-07:00",
+        "ends_at":   "2030-07-08T13:30:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPatch, "/appointments/999", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/appointments")
-		c.SetParamNames("trainer_id")
-		c.SetParamValues("1")
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("999")
 
-		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
+		if err := apiServer.handlePatchAppointment(c); assert.NotNil(t, err) {
 			he, ok := err.(*echo.HTTPError)
 			if ok {
-				assert.Equal(t, http.StatusBadRequest, he.Code)
+				assert.Equal(t, http.StatusNotFound, he.Code)
 			}
 		}
 	})
 
-	t.Run("Invalid timeframe (starts_at after ends_at)", func(t *testing.T) {
-		q := make(url.Values)
-		q.Set("starts_at", "2020-07-08T00:00:00Z")
-		q.Set("ends_at", "2020-07-05T00:00:00Z")
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?"+q.Encode(), nil)
+	t.Run("Invalid timeslot", func(t *testing.T) {
+		body := `{
+        "starts_at": "2030-07-08T20:00:00-08:00",
+        "ends_at":   "2030-07-08T20:30:00-08:00"
+        }`
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/appointments/%d", appointment.ID), strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/appointments")
-		c.SetParamNames("trainer_id")
-		c.SetParamValues("1")
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(appointment.ID))
 
-		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
+		if err := apiServer.handlePatchAppointment(c); assert.NotNil(t, err) {
 			he, ok := err.(*echo.HTTPError)
 			if ok {
 				assert.Equal(t, http.StatusBadRequest, he.Code)
@@ -153,57 +209,137 @@ func TestGetTrainerAppointments(t *testing.T) {
 		}
 	})
 
-	t.Run("Invalid timeframe (only starts_at)", func(t *testing.T) {
-		q := make(url.Values)
-		q.Set("starts_at", "2020-07-01T00:00:00Z")
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?"+q.Encode(), nil)
+	t.Run("Invalid time zone", func(t *testing.T) {
+		body := `{
+        "starts_at": "2030-07-08T13:00:00-07:00",
+        "ends_at":   "2030-07-08T13:30:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/appointments/%d?tz=Bogus", appointment.ID), strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/appointments")
-		c.SetParamNames("trainer_id")
-		c.SetParamValues("1")
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(appointment.ID))
 
-		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
+		if err := apiServer.handlePatchAppointment(c); assert.NotNil(t, err) {
 			he, ok := err.(*echo.HTTPError)
 			if ok {
 				assert.Equal(t, http.StatusBadRequest, he.Code)
 			}
 		}
+
+		// INFO: The appointment is not rescheduled when the response time zone is invalid
+		unchanged, err := testStore.GetAppointmentByID(appointment.ID)
+		if assert.NoError(t, err) {
+			assert.True(t, appointment.StartsAt.Equal(unchanged.StartsAt))
+		}
 	})
 
-	t.Run("Valid without timeframe", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments", nil)
+	t.Run("Valid reschedule", func(t *testing.T) {
+		body := `{
+        "starts_at": "2030-07-08T13:00:00-07:00",
+        "ends_at":   "2030-07-08T13:30:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/appointments/%d", appointment.ID), strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/appointments")
-		c.SetParamNames("trainer_id")
-		c.SetParamValues("1")
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(appointment.ID))
 
-		if assert.NoError(t, apiServer.handleGetTrainerAppointments(c)) {
+		if assert.NoError(t, apiServer.handlePatchAppointment(c)) {
 			assert.Equal(t, http.StatusOK, rec.Code)
-			assert.JSONEq(t, `[]`, rec.Body.String())
+			expectedBody := `{
+            "id":1,
+            "user_id":1,
+            "trainer_id":1,
+            "starts_at":"2030-07-08T13:00:00-07:00",
+            "ends_at":"2030-07-08T13:30:00-07:00",
+            "status":"scheduled"
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
 		}
 	})
+}
 
-	t.Run("Valid timeframe", func(t *testing.T) {
-		q := make(url.Values)
-		q.Set("starts_at", "2020-07-01T00:00:00Z")
-		q.Set("ends_at", "2020-09-29T00:00:00Z")
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?"+q.Encode(), nil)
+func TestGetAppointment(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	// INFO: Create test appointment
+	appointment, err := testStore.CreateAppointment(&models.Appointment{
+		UserID:    2,
+		TrainerID: 1,
+		StartsAt:  time.Date(2030, 7, 8, 12, 0, 0, 0, models.DefaultLocation()),
+		EndsAt:    time.Date(2030, 7, 8, 12, 30, 0, 0, models.DefaultLocation()),
+	})
+	if err != nil {
+		t.Fatalf("failed to create appointment: %v", err)
+	}
+
+	t.Run("Appointment not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/appointments/999", nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/appointments")
-		c.SetParamNames("trainer_id")
-		c.SetParamValues("1")
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("999")
 
-		if assert.NoError(t, apiServer.handleGetTrainerAppointments(c)) {
+		if err := apiServer.handleGetAppointment(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+
+	t.Run("Valid appointment", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/appointments/%d", appointment.ID), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(appointment.ID))
+
+		if assert.NoError(t, apiServer.handleGetAppointment(c)) {
 			assert.Equal(t, http.StatusOK, rec.Code)
-			assert.JSONEq(t, `[]`, rec.Body.String())
+			expectedBody := `{
+            "id":1,
+            "user_id":2,
+            "trainer_id":1,
+            "starts_at":"2030-07-08T12:00:00-07:00",
+            "ends_at":"2030-07-08T12:30:00-07:00",
+            "status":"scheduled",
+            "user":{"id":2,"name":"User 2","active":true},
+            "trainer":{"id":1,"name":"Trainer 1","active":true}
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Response time zone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/appointments/%d?tz=America/New_York", appointment.ID), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(appointment.ID))
+
+		if assert.NoError(t, apiServer.handleGetAppointment(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"starts_at":"2030-07-08T15:00:00-04:00"`)
 		}
 	})
 }
 
-func TestGetTrainerAvailability(t *testing.T) {
+func TestDeleteAppointment(t *testing.T) {
 	err := setup()
 	if err != nil {
 		t.Fatalf("failed to setup test: %v", err)
@@ -212,75 +348,202 @@ func TestGetTrainerAvailability(t *testing.T) {
 
 	e := apiServer.echo
 
-	t.Run("Invalid date format", func(t *testing.T) {
-		q := make(url.Values)
-		q.Set("starts_at", "2020-07-01")
-		q.Set("ends_at", "2020-10-01")
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+	// INFO: Create test appointment
+	appointment, err := testStore.CreateAppointment(&models.Appointment{
+		UserID:    1,
+		TrainerID: 1,
+		StartsAt:  time.Date(2030, 7, 8, 12, 0, 0, 0, models.DefaultLocation()),
+		EndsAt:    time.Date(2030, 7, 8, 12, 30, 0, 0, models.DefaultLocation()),
+	})
+	if err != nil {
+		t.Fatalf("failed to create appointment: %v", err)
+	}
+
+	t.Run("Appointment not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/appointments/999", nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/availability")
-		c.SetParamNames("trainer_id")
-		c.SetParamValues("1")
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("999")
 
-		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
+		if err := apiServer.handleDeleteAppointment(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+
+	t.Run("Invalid time zone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/appointments/%d?tz=Bogus", appointment.ID), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(appointment.ID))
+
+		if err := apiServer.handleDeleteAppointment(c); assert.NotNil(t, err) {
 			he, ok := err.(*echo.HTTPError)
 			if ok {
 				assert.Equal(t, http.StatusBadRequest, he.Code)
 			}
 		}
+
+		// INFO: The appointment is not cancelled when the response time zone is invalid
+		unchanged, err := testStore.GetAppointmentByID(appointment.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, models.AppointmentStatusScheduled, unchanged.Status)
+		}
 	})
 
-	t.Run("Invalid timeframe (starts_at after ends_at)", func(t *testing.T) {
-		q := make(url.Values)
-		q.Set("starts_at", "2030-07-08T00:00:00Z")
-		q.Set("ends_at", "2030-07-05T00:00:00Z")
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+	t.Run("Valid cancellation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/appointments/%d", appointment.ID), nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/availability")
-		c.SetParamNames("trainer_id")
-		c.SetParamValues("1")
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(appointment.ID))
 
-		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+		if assert.NoError(t, apiServer.handleDeleteAppointment(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			expectedBody := `{
+            "id":1,
+            "user_id":1,
+            "trainer_id":1,
+            "starts_at":"2030-07-08T12:00:00-07:00",
+            "ends_at":"2030-07-08T12:30:00-07:00",
+            "status":"cancelled"
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Already cancelled", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/appointments/%d", appointment.ID), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(appointment.ID))
+
+		if err := apiServer.handleDeleteAppointment(c); assert.NotNil(t, err) {
 			he, ok := err.(*echo.HTTPError)
 			if ok {
 				assert.Equal(t, http.StatusBadRequest, he.Code)
 			}
 		}
 	})
+}
 
-	t.Run("Invalid timeframe (more than 90 days)", func(t *testing.T) {
-		q := make(url.Values)
-		q.Set("starts_at", "2030-07-01T00:00:00Z")
-		q.Set("ends_at", "2030-10-01T00:00:00Z")
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+func TestAppointmentHistory(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	getHistory := func(target string, id string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/availability")
-		c.SetParamNames("trainer_id")
+		c.SetPath("/appointments/:id/history")
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+
+		return rec, apiServer.handleGetAppointmentHistory(c)
+	}
+
+	t.Run("Changes record the actor and request ID", func(t *testing.T) {
+		body := `{
+        "user_id":    1,
+        "trainer_id": 1,
+        "starts_at": "2030-07-08T12:00:00-07:00",
+        "ends_at":   "2030-07-08T12:30:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(HeaderActor, "front-desk")
+		rec := httptest.NewRecorder()
+		// INFO: The request ID middleware sets the response header before the handler runs
+		rec.Header().Set(echo.HeaderXRequestID, "req-1")
+		c := e.NewContext(req, rec)
+		assert.NoError(t, apiServer.handlePostAppointment(c))
+
+		body = `{
+        "starts_at": "2030-07-08T13:00:00-07:00",
+        "ends_at":   "2030-07-08T13:30:00-07:00"
+        }`
+		req = httptest.NewRequest(http.MethodPatch, "/appointments/1", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+		c.SetPath("/appointments/:id")
+		c.SetParamNames("id")
 		c.SetParamValues("1")
+		assert.NoError(t, apiServer.handlePatchAppointment(c))
 
-		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+		rec, err := getHistory("/appointments/1/history", "1")
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var changes []models.AppointmentChange
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &changes))
+			if assert.Len(t, changes, 2) {
+				assert.Equal(t, models.AppointmentActionCreated, changes[0].Action)
+				assert.Equal(t, "front-desk", changes[0].Actor)
+				assert.Equal(t, "req-1", changes[0].RequestID)
+				assert.Nil(t, changes[0].Before)
+
+				assert.Equal(t, models.AppointmentActionRescheduled, changes[1].Action)
+				assert.Equal(t, "anonymous", changes[1].Actor)
+				assert.Contains(t, rec.Body.String(), `"starts_at":"2030-07-08T12:00:00-07:00"`)
+				assert.Contains(t, rec.Body.String(), `"starts_at":"2030-07-08T13:00:00-07:00"`)
+			}
+		}
+	})
+
+	t.Run("Response time zone", func(t *testing.T) {
+		rec, err := getHistory("/appointments/1/history?tz=America/New_York", "1")
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"starts_at":"2030-07-08T16:00:00-04:00"`)
+		}
+	})
+
+	t.Run("Appointment not found", func(t *testing.T) {
+		if _, err := getHistory("/appointments/999/history", "999"); assert.NotNil(t, err) {
 			he, ok := err.(*echo.HTTPError)
 			if ok {
-				assert.Equal(t, http.StatusBadRequest, he.Code)
+				assert.Equal(t, http.StatusNotFound, he.Code)
 			}
 		}
 	})
+}
 
-	t.Run("Invalid timeframe (in past)", func(t *testing.T) {
+func TestGetTrainerAppointments(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	t.Run("Invalid date format", func(t *testing.T) {
 		q := make(url.Values)
-		q.Set("starts_at", "2020-07-01T00:00:00Z")
-		q.Set("ends_at", "2020-09-29T00:00:00Z")
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		q.Set("starts_at", "2020-07-08")
+		q.Set("ends_at", "2020-07-08")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?"+q.Encode(), nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetPath("/trainers/:trainer_id/appointments")
 		c.SetParamNames("trainer_id")
 		c.SetParamValues("1")
 
-		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
 			he, ok := err.(*echo.HTTPError)
 			if ok {
 				assert.Equal(t, http.StatusBadRequest, he.Code)
@@ -288,26 +551,1922 @@ func TestGetTrainerAvailability(t *testing.T) {
 		}
 	})
 
-	t.Run("Valid timeframe (tz updated to PST -8)", func(t *testing.T) {
+	t.Run("Invalid timeframe (starts_at after ends_at)", func(t *testing.T) {
 		q := make(url.Values)
-		q.Set("starts_at", "2030-07-08T20:00:00Z")
-		q.Set("ends_at", "2030-07-09T20:00:00Z")
-		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		q.Set("starts_at", "2020-07-08T00:00:00Z")
+		q.Set("ends_at", "2020-07-05T00:00:00Z")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?"+q.Encode(), nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetPath("/trainers/:trainer_id/appointments")
 		c.SetParamNames("trainer_id")
 		c.SetParamValues("1")
 
-		if assert.NoError(t, apiServer.handleGetTrainerAvailability(c)) {
-			assert.Equal(t, http.StatusOK, rec.Code)
-
-			var timeslots []models.Timeslot
-			err := json.Unmarshal(rec.Body.Bytes(), &timeslots)
-			if assert.NoError(t, err) && assert.NotEmpty(t, timeslots) {
-				timeFormat := "2006-01-02T15:04:05-07:00"
-				assert.Equal(t, "2030-07-08T08:00:00-08:00", timeslots[0].StartsAt.Format(timeFormat))
-				assert.Equal(t, "2030-07-08T17:00:00-08:00", timeslots[len(timeslots)-1].EndsAt.Format(timeFormat))
+		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Invalid timeframe (only starts_at)", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2020-07-01T00:00:00Z")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/appointments")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Valid without timeframe", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/appointments")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetTrainerAppointments(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `[]`, rec.Body.String())
+		}
+	})
+
+	t.Run("Valid timeframe", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2020-07-01T00:00:00Z")
+		q.Set("ends_at", "2020-09-29T00:00:00Z")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/appointments")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetTrainerAppointments(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `[]`, rec.Body.String())
+		}
+	})
+
+	t.Run("Invalid cursor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?cursor=garbage", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/appointments")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Limit too large", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/appointments?limit=501", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/appointments")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Paginated", func(t *testing.T) {
+		for day := 8; day <= 10; day++ {
+			startsAt := time.Date(2030, 7, day, 9, 0, 0, 0, models.DefaultLocation())
+			if _, err := testStore.CreateAppointment(&models.Appointment{
+				UserID:    1,
+				TrainerID: 1,
+				StartsAt:  startsAt,
+				EndsAt:    startsAt.Add(time.Minute * 30),
+			}); err != nil {
+				t.Fatalf("failed to create appointment: %v", err)
+			}
+		}
+
+		get := func(target string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/trainers/:trainer_id/appointments")
+			c.SetParamNames("trainer_id")
+			c.SetParamValues("1")
+
+			assert.NoError(t, apiServer.handleGetTrainerAppointments(c))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			return rec
+		}
+
+		rec := get("/trainers/1/appointments?limit=2")
+		var appointments []models.Appointment
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &appointments))
+		assert.Len(t, appointments, 2)
+
+		cursor := rec.Header().Get(HeaderNextCursor)
+		if assert.NotEmpty(t, cursor) {
+			next := "/trainers/1/appointments?cursor=" + cursor + "&limit=2"
+			assert.Equal(t, `<`+next+`>; rel="next"`, rec.Header().Get("Link"))
+
+			rec = get(next)
+			assert.JSONEq(t, `[{
+            "id":3,
+            "user_id":1,
+            "trainer_id":1,
+            "starts_at":"2030-07-10T09:00:00-07:00",
+            "ends_at":"2030-07-10T09:30:00-07:00",
+            "status":"scheduled"
+            }]`, rec.Body.String())
+			assert.Empty(t, rec.Header().Get(HeaderNextCursor))
+			assert.Empty(t, rec.Header().Get("Link"))
+		}
+	})
+}
+
+func TestGetTrainerAvailability(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	t.Run("Invalid date format", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2020-07-01")
+		q.Set("ends_at", "2020-10-01")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAppointments(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Invalid timeframe (starts_at after ends_at)", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T00:00:00Z")
+		q.Set("ends_at", "2030-07-05T00:00:00Z")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Invalid timeframe (more than 90 days)", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-01T00:00:00Z")
+		q.Set("ends_at", "2030-10-01T00:00:00Z")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Invalid timeframe (in past)", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2020-07-01T00:00:00Z")
+		q.Set("ends_at", "2020-09-29T00:00:00Z")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Valid timeframe (tz updated to trainer's time zone)", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetTrainerAvailability(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var timeslots []models.Timeslot
+			err := json.Unmarshal(rec.Body.Bytes(), &timeslots)
+			if assert.NoError(t, err) && assert.NotEmpty(t, timeslots) {
+				timeFormat := "2006-01-02T15:04:05-07:00"
+				assert.Equal(t, "2030-07-08T08:00:00-07:00", timeslots[0].StartsAt.Format(timeFormat))
+				assert.Equal(t, "2030-07-08T17:00:00-07:00", timeslots[len(timeslots)-1].EndsAt.Format(timeFormat))
+			}
+		}
+	})
+
+	t.Run("Valid timeframe (tz from query)", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Set("tz", "America/New_York")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetTrainerAvailability(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var timeslots []models.Timeslot
+			err := json.Unmarshal(rec.Body.Bytes(), &timeslots)
+			if assert.NoError(t, err) && assert.NotEmpty(t, timeslots) {
+				timeFormat := "2006-01-02T15:04:05-07:00"
+				assert.Equal(t, "2030-07-08T11:00:00-04:00", timeslots[0].StartsAt.Format(timeFormat))
+				assert.Equal(t, "2030-07-08T20:00:00-04:00", timeslots[len(timeslots)-1].EndsAt.Format(timeFormat))
+			}
+		}
+	})
+
+	t.Run("Valid timeframe with duration", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Set("duration", "90")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetTrainerAvailability(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var timeslots []models.Timeslot
+			err := json.Unmarshal(rec.Body.Bytes(), &timeslots)
+			if assert.NoError(t, err) && assert.NotEmpty(t, timeslots) {
+				timeFormat := "2006-01-02T15:04:05-07:00"
+				assert.Equal(t, "2030-07-08T09:30:00-07:00", timeslots[0].EndsAt.Format(timeFormat))
+				assert.Equal(t, "2030-07-08T15:30:00-07:00", timeslots[len(timeslots)-1].StartsAt.Format(timeFormat))
+			}
+		}
+	})
+
+	t.Run("Invalid duration", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Set("duration", "120")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Invalid tz", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Set("tz", "Mars/Olympus_Mons")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Set("user_id", "99")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+
+	t.Run("Excludes the user's own appointments", func(t *testing.T) {
+		startsAt := time.Date(2030, 7, 8, 8, 0, 0, 0, models.DefaultLocation())
+		if _, err := testStore.CreateAppointment(&models.Appointment{
+			UserID:    3,
+			TrainerID: 2,
+			StartsAt:  startsAt,
+			EndsAt:    startsAt.Add(time.Minute * 30),
+		}); err != nil {
+			t.Fatalf("failed to create appointment: %v", err)
+		}
+
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Set("user_id", "3")
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetTrainerAvailability(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var timeslots []models.Timeslot
+			err := json.Unmarshal(rec.Body.Bytes(), &timeslots)
+			if assert.NoError(t, err) && assert.NotEmpty(t, timeslots) {
+				timeFormat := "2006-01-02T15:04:05-07:00"
+				assert.Equal(t, "2030-07-08T08:30:00-07:00", timeslots[0].StartsAt.Format(timeFormat))
+			}
+		}
+	})
+}
+
+func TestGetAvailability(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	get := func(q url.Values) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, "/availability?"+q.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/availability")
+
+		return rec, apiServer.handleGetAvailability(c)
+	}
+
+	t.Run("Invalid timeframe (more than 90 days)", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-01T00:00:00Z")
+		q.Set("ends_at", "2030-10-01T00:00:00Z")
+
+		if _, err := get(q); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Duplicate trainers", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Add("trainer_ids", "1")
+		q.Add("trainer_ids", "1")
+
+		if _, err := get(q); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Unknown trainer", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Add("trainer_ids", "1")
+		q.Add("trainer_ids", "99")
+
+		if _, err := get(q); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+
+	t.Run("First available timeslots", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Add("trainer_ids", "2")
+		q.Add("trainer_ids", "1")
+		q.Set("first", "2")
+
+		rec, err := get(q)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `[
+            {
+                "starts_at":"2030-07-08T08:00:00-07:00",
+                "ends_at":"2030-07-08T08:30:00-07:00",
+                "trainer_ids":[1,2],
+                "seats":2
+            },
+            {
+                "starts_at":"2030-07-08T08:30:00-07:00",
+                "ends_at":"2030-07-08T09:00:00-07:00",
+                "trainer_ids":[1,2],
+                "seats":2
+            }
+            ]`, rec.Body.String())
+		}
+	})
+
+	t.Run("All active trainers", func(t *testing.T) {
+		_, err := testStore.DeactivateTrainer(5)
+		assert.NoError(t, err)
+
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Set("first", "1")
+		q.Set("tz", "America/New_York")
+
+		rec, err := get(q)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `[{
+                "starts_at":"2030-07-08T11:00:00-04:00",
+                "ends_at":"2030-07-08T11:30:00-04:00",
+                "trainer_ids":[1,2,3,4],
+                "seats":4
+            }]`, rec.Body.String())
+		}
+	})
+
+	t.Run("Inactive trainer", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Add("trainer_ids", "5")
+
+		if _, err := get(q); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+			}
+		}
+	})
+
+	t.Run("Excludes the user's own appointments", func(t *testing.T) {
+		startsAt := time.Date(2030, 7, 8, 8, 0, 0, 0, models.DefaultLocation())
+		if _, err := testStore.CreateAppointment(&models.Appointment{
+			UserID:    3,
+			TrainerID: 4,
+			StartsAt:  startsAt,
+			EndsAt:    startsAt.Add(time.Minute * 30),
+		}); err != nil {
+			t.Fatalf("failed to create appointment: %v", err)
+		}
+
+		q := make(url.Values)
+		q.Set("starts_at", "2030-07-08T20:00:00Z")
+		q.Set("ends_at", "2030-07-09T20:00:00Z")
+		q.Add("trainer_ids", "1")
+		q.Add("trainer_ids", "2")
+		q.Set("user_id", "3")
+		q.Set("first", "1")
+
+		rec, err := get(q)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `[{
+                "starts_at":"2030-07-08T08:30:00-07:00",
+                "ends_at":"2030-07-08T09:00:00-07:00",
+                "trainer_ids":[1,2],
+                "seats":2
+            }]`, rec.Body.String())
+		}
+	})
+}
+
+func TestTrainerWorkingHours(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	t.Run("Overlapping working hours", func(t *testing.T) {
+		body := `{
+        "working_hours": [
+            {"weekday": 1, "start_time": "06:00", "end_time": "10:00"},
+            {"weekday": 1, "start_time": "09:00", "end_time": "12:00"}
+        ]
+        }`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/working-hours", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/working-hours")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handlePutTrainerWorkingHours(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Missing weekday", func(t *testing.T) {
+		body := `{
+        "working_hours": [
+            {"start_time": "06:00", "end_time": "10:00"}
+        ]
+        }`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/working-hours", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/working-hours")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handlePutTrainerWorkingHours(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Valid working hours", func(t *testing.T) {
+		body := `{
+        "working_hours": [
+            {"weekday": 6, "start_time": "07:00", "end_time": "09:00"},
+            {"weekday": 1, "start_time": "06:00", "end_time": "10:00"}
+        ]
+        }`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/working-hours", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/working-hours")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		expectedBody := `[
+            {"weekday": 1, "start_time": "06:00", "end_time": "10:00"},
+            {"weekday": 6, "start_time": "07:00", "end_time": "09:00"}
+        ]`
+
+		if assert.NoError(t, apiServer.handlePutTrainerWorkingHours(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/trainers/1/working-hours", nil)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/working-hours")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetTrainerWorkingHours(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Booking outside working hours", func(t *testing.T) {
+		body := `{
+        "user_id":    1,
+        "trainer_id": 1,
+        "starts_at": "2030-07-08T12:00:00-08:00",
+        "ends_at":   "2030-07-08T12:30:00-08:00"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostAppointment(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+}
+
+func TestTrainerSettings(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	t.Run("Default settings", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/settings", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/settings")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetTrainerSettings(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"trainer_id":1,"time_zone":"America/Los_Angeles","durations":[30,45,60,90],"capacity":1,"buffer_before":0,"buffer_after":0}`, rec.Body.String())
+		}
+	})
+
+	t.Run("Invalid time zone", func(t *testing.T) {
+		body := `{"time_zone": "Mars/Olympus_Mons"}`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/settings", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/settings")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handlePutTrainerSettings(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Valid time zone", func(t *testing.T) {
+		body := `{"time_zone": "Europe/London", "durations": [60, 30]}`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/settings", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/settings")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handlePutTrainerSettings(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"trainer_id":1,"time_zone":"Europe/London","durations":[30,60],"capacity":1,"buffer_before":0,"buffer_after":0}`, rec.Body.String())
+		}
+	})
+
+	t.Run("Booking uses trainer's time zone", func(t *testing.T) {
+		body := `{
+        "user_id":    1,
+        "trainer_id": 1,
+        "starts_at": "2030-07-08T08:00:00Z",
+        "ends_at":   "2030-07-08T08:30:00Z"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, apiServer.handlePostAppointment(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			expectedBody := `{
+            "id":1,
+            "user_id":1,
+            "trainer_id":1,
+            "starts_at":"2030-07-08T09:00:00+01:00",
+            "ends_at":"2030-07-08T09:30:00+01:00",
+            "status":"scheduled"
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Group session capacity", func(t *testing.T) {
+		body := `{"time_zone": "Europe/London", "durations": [60, 30], "capacity": 4}`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/settings", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/settings")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handlePutTrainerSettings(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"trainer_id":1,"time_zone":"Europe/London","durations":[30,60],"capacity":4,"buffer_before":0,"buffer_after":0}`, rec.Body.String())
+		}
+	})
+
+	t.Run("Buffers", func(t *testing.T) {
+		body := `{"time_zone": "Europe/London", "buffer_before": 10, "buffer_after": 15}`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/settings", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/settings")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handlePutTrainerSettings(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"trainer_id":1,"time_zone":"Europe/London","durations":[30,45,60,90],"capacity":1,"buffer_before":10,"buffer_after":15}`, rec.Body.String())
+		}
+	})
+
+	t.Run("Invalid buffer", func(t *testing.T) {
+		body := `{"time_zone": "Europe/London", "buffer_after": 7}`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/settings", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/settings")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handlePutTrainerSettings(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+				assert.Equal(t, "Buffers must be multiples of 5 minutes between 0 and 120 minutes", he.Message)
+			}
+		}
+	})
+
+	t.Run("Capacity too large", func(t *testing.T) {
+		body := `{"time_zone": "Europe/London", "capacity": 21}`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/settings", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/settings")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handlePutTrainerSettings(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+}
+
+func TestTrainerTimeOff(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	t.Run("Invalid timeframe", func(t *testing.T) {
+		body := `{
+        "starts_at": "2030-07-08T12:00:00-07:00",
+        "ends_at":   "2030-07-08T09:00:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/trainers/1/time-off", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/time-off")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handlePostTrainerTimeOff(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Valid all day time off", func(t *testing.T) {
+		body := `{
+        "starts_at": "2030-07-08T12:00:00-07:00",
+        "ends_at":   "2030-07-08T12:00:00-07:00",
+        "all_day":   true,
+        "reason":    "Vacation"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/trainers/1/time-off", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/time-off")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handlePostTrainerTimeOff(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			expectedBody := `{
+            "id":1,
+            "trainer_id":1,
+            "starts_at":"2030-07-08T00:00:00-07:00",
+            "ends_at":"2030-07-09T00:00:00-07:00",
+            "all_day":true,
+            "reason":"Vacation"
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Booking during time off", func(t *testing.T) {
+		body := `{
+        "user_id":    1,
+        "trainer_id": 1,
+        "starts_at": "2030-07-08T10:00:00-07:00",
+        "ends_at":   "2030-07-08T10:30:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostAppointment(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+				assert.Equal(t, "Trainer is unavailable during timeslot", he.Message)
+			}
+		}
+	})
+
+	t.Run("List time off", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trainers/1/time-off", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/time-off")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetTrainerTimeOff(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var timeOff []models.TimeOff
+			err := json.Unmarshal(rec.Body.Bytes(), &timeOff)
+			if assert.NoError(t, err) {
+				assert.Len(t, timeOff, 1)
+			}
+		}
+	})
+
+	t.Run("Update time off not found", func(t *testing.T) {
+		body := `{
+        "starts_at": "2030-07-08T09:00:00-07:00",
+        "ends_at":   "2030-07-08T12:00:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/time-off/999", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/time-off/:id")
+		c.SetParamNames("trainer_id", "id")
+		c.SetParamValues("1", "999")
+
+		if err := apiServer.handlePutTrainerTimeOff(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+
+	t.Run("Update time off", func(t *testing.T) {
+		body := `{
+        "starts_at": "2030-07-08T09:00:00-07:00",
+        "ends_at":   "2030-07-08T12:00:00-07:00",
+        "reason":    "Dentist"
+        }`
+		req := httptest.NewRequest(http.MethodPut, "/trainers/1/time-off/1", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/time-off/:id")
+		c.SetParamNames("trainer_id", "id")
+		c.SetParamValues("1", "1")
+
+		if assert.NoError(t, apiServer.handlePutTrainerTimeOff(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			expectedBody := `{
+            "id":1,
+            "trainer_id":1,
+            "starts_at":"2030-07-08T09:00:00-07:00",
+            "ends_at":"2030-07-08T12:00:00-07:00",
+            "all_day":false,
+            "reason":"Dentist"
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Delete time off", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/trainers/1/time-off/1", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/time-off/:id")
+		c.SetParamNames("trainer_id", "id")
+		c.SetParamValues("1", "1")
+
+		if assert.NoError(t, apiServer.handleDeleteTrainerTimeOff(c)) {
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+		}
+	})
+}
+
+func TestHolidays(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	t.Run("Invalid partial holiday", func(t *testing.T) {
+		body := `{
+        "date":       "2030-07-08",
+        "name":       "Early Close",
+        "start_time": "12:00"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/holidays", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostHoliday(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+				assert.Equal(t, "Partial day holidays must have both a start time and an end time", he.Message)
+			}
+		}
+	})
+
+	t.Run("Valid holiday", func(t *testing.T) {
+		body := `{
+        "date": "2030-07-08",
+        "name": "Company Retreat"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/holidays", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, apiServer.handlePostHoliday(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			expectedBody := `{
+            "id":1,
+            "date":"2030-07-08",
+            "name":"Company Retreat"
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Booking on a holiday", func(t *testing.T) {
+		body := `{
+        "user_id":    1,
+        "trainer_id": 1,
+        "starts_at": "2030-07-08T10:00:00-07:00",
+        "ends_at":   "2030-07-08T10:30:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostAppointment(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+				assert.Equal(t, "Timeslot falls on a holiday", he.Message)
+			}
+		}
+	})
+
+	t.Run("List holidays", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/holidays?from=2030-07-01&to=2030-07-31", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, apiServer.handleGetHolidays(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var holidays []models.Holiday
+			err := json.Unmarshal(rec.Body.Bytes(), &holidays)
+			if assert.NoError(t, err) {
+				assert.Len(t, holidays, 1)
+			}
+		}
+	})
+
+	t.Run("Update holiday", func(t *testing.T) {
+		body := `{
+        "date":       "2030-07-08",
+        "name":       "Early Close",
+        "start_time": "12:00",
+        "end_time":   "00:00"
+        }`
+		req := httptest.NewRequest(http.MethodPut, "/holidays/1", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/holidays/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handlePutHoliday(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			expectedBody := `{
+            "id":1,
+            "date":"2030-07-08",
+            "name":"Early Close",
+            "start_time":"12:00",
+            "end_time":"00:00"
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Delete holiday not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/holidays/999", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/holidays/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("999")
+
+		if err := apiServer.handleDeleteHoliday(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+
+	t.Run("Delete holiday", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/holidays/1", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/holidays/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleDeleteHoliday(c)) {
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+		}
+	})
+}
+
+func TestAppointmentSeries(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	if _, err := apiServer.store.CreateHoliday(&models.Holiday{Date: "2030-07-02", Name: "Team Offsite"}); err != nil {
+		t.Fatalf("failed to create holiday: %v", err)
+	}
+
+	t.Run("Invalid rrule", func(t *testing.T) {
+		body := `{
+        "user_id":    1,
+        "trainer_id": 1,
+        "starts_at": "2030-07-08T09:00:00-07:00",
+        "ends_at":   "2030-07-08T09:30:00-07:00",
+        "rrule":     "FREQ=DAILY;COUNT=3"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments/series", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostAppointmentSeries(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+				assert.Equal(t, "RRULE frequency must be WEEKLY", he.Message)
+			}
+		}
+	})
+
+	t.Run("Valid series", func(t *testing.T) {
+		body := `{
+        "user_id":    1,
+        "trainer_id": 1,
+        "starts_at": "2030-06-25T09:00:00-07:00",
+        "ends_at":   "2030-06-25T09:30:00-07:00",
+        "frequency": "weekly",
+        "count":     3
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments/series", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, apiServer.handlePostAppointmentSeries(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			expectedBody := `{
+            "series": {
+                "id":1,
+                "user_id":1,
+                "trainer_id":1,
+                "rrule":"FREQ=WEEKLY;INTERVAL=1;COUNT=3",
+                "starts_at":"2030-06-25T09:00:00-07:00",
+                "ends_at":"2030-06-25T09:30:00-07:00"
+            },
+            "booked": [
+                {"id":1,"user_id":1,"trainer_id":1,"starts_at":"2030-06-25T09:00:00-07:00","ends_at":"2030-06-25T09:30:00-07:00","status":"scheduled","series_id":1},
+                {"id":2,"user_id":1,"trainer_id":1,"starts_at":"2030-07-09T09:00:00-07:00","ends_at":"2030-07-09T09:30:00-07:00","status":"scheduled","series_id":1}
+            ],
+            "conflicts": [
+                {"starts_at":"2030-07-02T09:00:00-07:00","ends_at":"2030-07-02T09:30:00-07:00","reason":"Timeslot falls on a holiday"}
+            ]
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+	t.Run("Invalid time zone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/appointments/1/following?tz=Bogus", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/appointments/:id/following")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleDeleteFollowingAppointments(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+
+		// INFO: The series is not cancelled when the response time zone is invalid
+		unchanged, err := apiServer.store.GetAppointmentByID(1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, models.AppointmentStatusScheduled, unchanged.Status)
+		}
+	})
+
+	t.Run("Cancel this and following", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/appointments/1/following", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/appointments/:id/following")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleDeleteFollowingAppointments(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var appointments []models.Appointment
+			err := json.Unmarshal(rec.Body.Bytes(), &appointments)
+			if assert.NoError(t, err) {
+				assert.Len(t, appointments, 2)
+				for _, appointment := range appointments {
+					assert.Equal(t, models.AppointmentStatusCancelled, appointment.Status)
+				}
+			}
+		}
+	})
+}
+
+func TestWaitlist(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	appointment, err := apiServer.store.CreateAppointment(&models.Appointment{
+		UserID:    1,
+		TrainerID: 1,
+		StartsAt:  time.Date(2030, 7, 8, 9, 0, 0, 0, models.DefaultLocation()),
+		EndsAt:    time.Date(2030, 7, 8, 9, 30, 0, 0, models.DefaultLocation()),
+	})
+	if err != nil {
+		t.Fatalf("failed to create appointment: %v", err)
+	}
+
+	body := `{
+        "user_id":   2,
+        "starts_at": "2030-07-08T09:00:00-07:00",
+        "ends_at":   "2030-07-08T09:30:00-07:00"
+        }`
+
+	t.Run("Join waitlist", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/trainers/1/waitlist", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/waitlist")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handlePostTrainerWaitlist(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+
+			var entry models.WaitlistEntry
+			err := json.Unmarshal(rec.Body.Bytes(), &entry)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 1, entry.ID)
+				assert.Equal(t, models.WaitlistStatusWaiting, entry.Status)
+			}
+		}
+	})
+
+	t.Run("Join waitlist twice", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/trainers/1/waitlist", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/waitlist")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handlePostTrainerWaitlist(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusConflict, he.Code)
+				assert.Equal(t, ErrorResponse{Code: ErrCodeAlreadyWaitlisted, Message: "User is already on the waitlist for this timeslot"}, he.Message)
+			}
+		}
+	})
+
+	t.Run("Leave waitlist with invalid time zone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/waitlist/1?tz=Bogus", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/waitlist/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleDeleteWaitlistEntry(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+
+		// INFO: The entry stays on the waitlist when the response time zone is invalid
+		entry, err := apiServer.store.GetWaitlistEntry(1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, models.WaitlistStatusWaiting, entry.Status)
+		}
+	})
+
+	t.Run("Accept offer after cancellation", func(t *testing.T) {
+		if _, err := apiServer.store.CancelAppointment(appointment.ID); err != nil {
+			t.Fatalf("failed to cancel appointment: %v", err)
+		}
+
+		// INFO: The offer is not accepted when the response time zone is invalid
+		req := httptest.NewRequest(http.MethodPost, "/waitlist/1/accept?tz=Bogus", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/waitlist/:id/accept")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleAcceptWaitlistOffer(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+
+		entry, err := apiServer.store.GetWaitlistEntry(1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, models.WaitlistStatusOffered, entry.Status)
+		}
+
+		req = httptest.NewRequest(http.MethodPost, "/waitlist/1/accept", nil)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+		c.SetPath("/waitlist/:id/accept")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleAcceptWaitlistOffer(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			expectedBody := `{
+            "id":2,
+            "user_id":2,
+            "trainer_id":1,
+            "starts_at":"2030-07-08T09:00:00-07:00",
+            "ends_at":"2030-07-08T09:30:00-07:00",
+            "status":"scheduled"
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Leave waitlist after booking", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/waitlist/1", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/waitlist/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleDeleteWaitlistEntry(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+}
+
+func TestUsers(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	t.Run("Invalid email", func(t *testing.T) {
+		body := `{"name": "Jane Doe", "email": "jane"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostUser(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Valid user", func(t *testing.T) {
+		body := `{"name": "Jane Doe", "email": "Jane@Example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, apiServer.handlePostUser(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			expectedBody := `{"id":11,"name":"Jane Doe","email":"jane@example.com","active":true}`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Duplicate email", func(t *testing.T) {
+		body := `{"name": "Jane Doe", "email": "jane@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostUser(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusConflict, he.Code)
+				assert.Equal(t, ErrorResponse{Code: ErrCodeEmailTaken, Message: "Email is already in use"}, he.Message)
+			}
+		}
+	})
+
+	t.Run("Update user", func(t *testing.T) {
+		body := `{"name": "Jane Smith", "email": "jane@example.com", "active": true}`
+		req := httptest.NewRequest(http.MethodPut, "/users/11", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/users/:user_id")
+		c.SetParamNames("user_id")
+		c.SetParamValues("11")
+
+		if assert.NoError(t, apiServer.handlePutUser(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			expectedBody := `{"id":11,"name":"Jane Smith","email":"jane@example.com","active":true}`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Deactivate user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/users/11", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/users/:user_id")
+		c.SetParamNames("user_id")
+		c.SetParamValues("11")
+
+		if assert.NoError(t, apiServer.handleDeleteUser(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			expectedBody := `{"id":11,"name":"Jane Smith","email":"jane@example.com","active":false}`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Booking for a deactivated user", func(t *testing.T) {
+		body := `{
+        "user_id":    11,
+        "trainer_id": 1,
+        "starts_at": "2030-07-08T10:00:00-07:00",
+        "ends_at":   "2030-07-08T10:30:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostAppointment(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+				assert.Equal(t, "User is not active", he.Message)
+			}
+		}
+	})
+
+	t.Run("Get unknown user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/999", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/users/:user_id")
+		c.SetParamNames("user_id")
+		c.SetParamValues("999")
+
+		if err := apiServer.handleGetUser(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+}
+
+func TestTrainers(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	t.Run("Valid trainer", func(t *testing.T) {
+		body := `{"name": "John Smith", "email": "john@example.com", "active": false}`
+		req := httptest.NewRequest(http.MethodPost, "/trainers", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, apiServer.handlePostTrainer(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			expectedBody := `{"id":6,"name":"John Smith","email":"john@example.com","active":false}`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("List trainers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trainers", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, apiServer.handleGetTrainers(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var trainers []models.Trainer
+			err := json.Unmarshal(rec.Body.Bytes(), &trainers)
+			if assert.NoError(t, err) {
+				assert.Len(t, trainers, 6)
+			}
+		}
+	})
+
+	t.Run("Booking an unknown trainer", func(t *testing.T) {
+		body := `{
+        "user_id":    1,
+        "trainer_id": 999999,
+        "starts_at": "2030-07-08T10:00:00-07:00",
+        "ends_at":   "2030-07-08T10:30:00-07:00"
+        }`
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostAppointment(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+				assert.Equal(t, "Trainer not found", he.Message)
+			}
+		}
+	})
+
+	t.Run("Availability for a deactivated trainer", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trainers/6/availability?starts_at=2030-07-08T00:00:00-07:00&ends_at=2030-07-09T00:00:00-07:00", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("6")
+
+		if err := apiServer.handleGetTrainerAvailability(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+			}
+		}
+	})
+
+	t.Run("Settings for an unknown trainer", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trainers/999999/settings", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/settings")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("999999")
+
+		if err := apiServer.handleGetTrainerSettings(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+}
+
+func TestGetUserAppointments(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	startsAt := time.Date(2030, 7, 8, 9, 0, 0, 0, models.DefaultLocation())
+	for _, appointment := range []*models.Appointment{
+		{UserID: 1, TrainerID: 1, StartsAt: startsAt, EndsAt: startsAt.Add(time.Minute * 30)},
+		{UserID: 1, TrainerID: 2, StartsAt: startsAt.Add(time.Hour), EndsAt: startsAt.Add(time.Hour).Add(time.Minute * 30), Status: models.AppointmentStatusCancelled},
+	} {
+		if _, err := apiServer.store.CreateAppointment(appointment); err != nil {
+			t.Fatalf("failed to create appointment: %v", err)
+		}
+	}
+
+	t.Run("Invalid filter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/1/appointments?filter=soon", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/users/:user_id/appointments")
+		c.SetParamNames("user_id")
+		c.SetParamValues("1")
+
+		if err := apiServer.handleGetUserAppointments(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Upcoming appointments", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/1/appointments?filter=upcoming&tz=UTC", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/users/:user_id/appointments")
+		c.SetParamNames("user_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetUserAppointments(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			expectedBody := `[{
+            "id":1,
+            "user_id":1,
+            "trainer_id":1,
+            "starts_at":"2030-07-08T16:00:00Z",
+            "ends_at":"2030-07-08T16:30:00Z",
+            "status":"scheduled"
+            }]`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Cancelled appointments", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/1/appointments?filter=cancelled", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/users/:user_id/appointments")
+		c.SetParamNames("user_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetUserAppointments(c)) {
+			var appointments []models.Appointment
+			err := json.Unmarshal(rec.Body.Bytes(), &appointments)
+			if assert.NoError(t, err) {
+				assert.Len(t, appointments, 1)
+				assert.Equal(t, 2, appointments[0].ID)
+			}
+		}
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/999/appointments", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/users/:user_id/appointments")
+		c.SetParamNames("user_id")
+		c.SetParamValues("999")
+
+		if err := apiServer.handleGetUserAppointments(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+			}
+		}
+	})
+}
+
+func TestResources(t *testing.T) {
+	err := setup()
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown()
+
+	e := apiServer.echo
+
+	postAppointment := func(body string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		return rec, apiServer.handlePostAppointment(c)
+	}
+
+	t.Run("Invalid kind", func(t *testing.T) {
+		body := `{"name": "Van", "kind": "vehicle"}`
+		req := httptest.NewRequest(http.MethodPost, "/resources", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := apiServer.handlePostResource(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+			}
+		}
+	})
+
+	t.Run("Valid resource", func(t *testing.T) {
+		body := `{"name": "Studio A", "kind": "room"}`
+		req := httptest.NewRequest(http.MethodPost, "/resources", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, apiServer.handlePostResource(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			expectedBody := `{"id":1,"name":"Studio A","kind":"room","active":true}`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Update resource", func(t *testing.T) {
+		body := `{"name": "Studio 1", "kind": "room", "active": true}`
+		req := httptest.NewRequest(http.MethodPut, "/resources/1", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/resources/:resource_id")
+		c.SetParamNames("resource_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handlePutResource(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			expectedBody := `{"id":1,"name":"Studio 1","kind":"room","active":true}`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Book with a resource", func(t *testing.T) {
+		rec, err := postAppointment(`{
+        "user_id":      1,
+        "trainer_id":   1,
+        "starts_at":    "2030-07-08T10:00:00-07:00",
+        "ends_at":      "2030-07-08T10:30:00-07:00",
+        "resource_ids": [1]
+        }`)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			expectedBody := `{
+            "id":1,
+            "user_id":1,
+            "trainer_id":1,
+            "starts_at":"2030-07-08T10:00:00-07:00",
+            "ends_at":"2030-07-08T10:30:00-07:00",
+            "status":"scheduled",
+            "resource_ids":[1]
+            }`
+			assert.JSONEq(t, expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("Resource already booked", func(t *testing.T) {
+		_, err := postAppointment(`{
+        "user_id":      2,
+        "trainer_id":   2,
+        "starts_at":    "2030-07-08T10:00:00-07:00",
+        "ends_at":      "2030-07-08T10:30:00-07:00",
+        "resource_ids": [1]
+        }`)
+
+		if assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if assert.True(t, ok) {
+				assert.Equal(t, http.StatusConflict, he.Code)
+				assert.Equal(t, ErrorResponse{Code: ErrCodeResourceUnavailable, Message: "Resource is not available"}, he.Message)
+			}
+		}
+	})
+
+	t.Run("Unknown resource", func(t *testing.T) {
+		_, err := postAppointment(`{
+        "user_id":      2,
+        "trainer_id":   2,
+        "starts_at":    "2030-07-08T10:00:00-07:00",
+        "ends_at":      "2030-07-08T10:30:00-07:00",
+        "resource_ids": [999999]
+        }`)
+
+		if assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
+				assert.Equal(t, "Resource not found", he.Message)
+			}
+		}
+	})
+
+	t.Run("Availability with resources", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/trainers/2/availability?starts_at=2030-07-08T10:00:00-07:00&ends_at=2030-07-08T11:00:00-07:00&resource_ids=1", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/trainers/:trainer_id/availability")
+		c.SetParamNames("trainer_id")
+		c.SetParamValues("2")
+
+		if assert.NoError(t, apiServer.handleGetTrainerAvailability(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var timeslots []models.OpenTimeslot
+			err := json.Unmarshal(rec.Body.Bytes(), &timeslots)
+			if assert.NoError(t, err) {
+				starts := make([]string, 0)
+				for _, timeslot := range timeslots {
+					starts = append(starts, timeslot.StartsAt.Format("15:04"))
+				}
+				// INFO: Trainer 1 holds the room from 10:00 to 10:30
+				assert.NotContains(t, starts, "10:00")
+				assert.Contains(t, starts, "10:30")
+			}
+		}
+	})
+
+	t.Run("Resource calendar", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/resources/1/appointments", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/resources/:resource_id/appointments")
+		c.SetParamNames("resource_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleGetResourceAppointments(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var appointments []models.Appointment
+			err := json.Unmarshal(rec.Body.Bytes(), &appointments)
+			if assert.NoError(t, err) && assert.Len(t, appointments, 1) {
+				assert.Equal(t, []int{1}, appointments[0].ResourceIDs)
+			}
+		}
+	})
+
+	t.Run("Booking a deactivated resource", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/resources/1", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/resources/:resource_id")
+		c.SetParamNames("resource_id")
+		c.SetParamValues("1")
+
+		if assert.NoError(t, apiServer.handleDeleteResource(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+
+		_, err := postAppointment(`{
+        "user_id":      2,
+        "trainer_id":   2,
+        "starts_at":    "2030-07-09T10:00:00-07:00",
+        "ends_at":      "2030-07-09T10:30:00-07:00",
+        "resource_ids": [1]
+        }`)
+
+		if assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+			}
+		}
+	})
+
+	t.Run("Get unknown resource", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/resources/999999", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/resources/:resource_id")
+		c.SetParamNames("resource_id")
+		c.SetParamValues("999999")
+
+		if err := apiServer.handleGetResource(c); assert.NotNil(t, err) {
+			he, ok := err.(*echo.HTTPError)
+			if ok {
+				assert.Equal(t, http.StatusNotFound, he.Code)
 			}
 		}
 	})
@@ -24,6 +24,10 @@ func NewCustomValidator() *CustomValidator {
 	validate := validator.New()
 	validate.RegisterStructValidation(AppointmentTimeframeValidation, GetTrainerAppointmentsReq{})
 	validate.RegisterStructValidation(AvailabilityTimeframeValidation, GetTrainerAvailabilityReq{})
+	validate.RegisterStructValidation(MultiAvailabilityTimeframeValidation, GetAvailabilityReq{})
+	validate.RegisterStructValidation(TimeOffTimeframeValidation, GetTrainerTimeOffReq{})
+	validate.RegisterStructValidation(UserAppointmentTimeframeValidation, GetUserAppointmentsReq{})
+	validate.RegisterStructValidation(ResourceAppointmentTimeframeValidation, GetResourceAppointmentsReq{})
 	validate.RegisterValidation("is-future-date", ValidateFutureDate)
 
 	en_translations.RegisterDefaultTranslations(validate, trans)
@@ -67,10 +71,40 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 }
 
 type PostAppointmentReq struct {
+	UserID      int    `json:"user_id" validate:"required,min=1"`
+	TrainerID   int    `json:"trainer_id" validate:"required,min=1"`
+	StartsAt    string `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	EndsAt      string `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	ResourceIDs []int  `json:"resource_ids" validate:"omitempty,max=10,unique,dive,min=1"`
+}
+
+type PostAppointmentSeriesReq struct {
 	UserID    int    `json:"user_id" validate:"required,min=1"`
 	TrainerID int    `json:"trainer_id" validate:"required,min=1"`
 	StartsAt  string `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
 	EndsAt    string `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	Frequency string `json:"frequency" validate:"required_without=RRule,omitempty,oneof=weekly biweekly"`
+	Count     int    `json:"count" validate:"omitempty,min=1,max=52"`
+	Until     string `json:"until" validate:"omitempty,datetime=2006-01-02"`
+	RRule     string `json:"rrule" validate:"omitempty,max=255"`
+}
+
+type PatchAppointmentReq struct {
+	ID       int    `param:"id" validate:"required,min=1"`
+	StartsAt string `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	EndsAt   string `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+}
+
+type GetAppointmentReq struct {
+	ID int `param:"id" validate:"required,min=1"`
+}
+
+type GetAppointmentHistoryReq struct {
+	ID int `param:"id" validate:"required,min=1"`
+}
+
+type DeleteAppointmentReq struct {
+	ID int `param:"id" validate:"required,min=1"`
 }
 
 func ValidateFutureDate(fl validator.FieldLevel) bool {
@@ -85,22 +119,41 @@ type GetTrainerAppointmentsReq struct {
 	TrainerID int    `param:"trainer_id" validate:"required,min=1"`
 	StartsAt  string `query:"starts_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 	EndsAt    string `query:"ends_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=500"`
+	Cursor    string `query:"cursor"`
 }
 
 func AppointmentTimeframeValidation(sl validator.StructLevel) {
 	req := sl.Current().Interface().(GetTrainerAppointmentsReq)
+	validateOptionalTimeframe(sl, req.StartsAt, req.EndsAt)
+}
 
-	if (req.StartsAt == "" && req.EndsAt != "") || (req.StartsAt != "" && req.EndsAt == "") {
-		sl.ReportError(req.StartsAt, "starts_at", "StartsAt", "timeframe-invalid", "")
+type GetUserAppointmentsReq struct {
+	UserID   int    `param:"user_id" validate:"required,min=1"`
+	StartsAt string `query:"starts_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt   string `query:"ends_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Filter   string `query:"filter" validate:"omitempty,oneof=upcoming past cancelled"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=500"`
+	Cursor   string `query:"cursor"`
+}
+
+func UserAppointmentTimeframeValidation(sl validator.StructLevel) {
+	req := sl.Current().Interface().(GetUserAppointmentsReq)
+	validateOptionalTimeframe(sl, req.StartsAt, req.EndsAt)
+}
+
+func validateOptionalTimeframe(sl validator.StructLevel, startsAt, endsAt string) {
+	if (startsAt == "" && endsAt != "") || (startsAt != "" && endsAt == "") {
+		sl.ReportError(startsAt, "starts_at", "StartsAt", "timeframe-invalid", "")
 	}
 
-	if req.StartsAt != "" && req.EndsAt != "" {
-		parsedStartsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if startsAt != "" && endsAt != "" {
+		parsedStartsAt, err := time.Parse(time.RFC3339, startsAt)
 		if err != nil {
 			sl.ReportError(parsedStartsAt, "starts_at", "StartsAt", "datetime", "")
 		}
 
-		parsedEndsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+		parsedEndsAt, err := time.Parse(time.RFC3339, endsAt)
 		if err != nil {
 			sl.ReportError(parsedEndsAt, "ends_at", "EndsAt", "datetime", "")
 		}
@@ -112,20 +165,41 @@ func AppointmentTimeframeValidation(sl validator.StructLevel) {
 }
 
 type GetTrainerAvailabilityReq struct {
-	TrainerID int    `param:"trainer_id" validate:"required,min=1"`
-	StartsAt  string `query:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
-	EndsAt    string `query:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	TrainerID   int    `param:"trainer_id" validate:"required,min=1"`
+	StartsAt    string `query:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	EndsAt      string `query:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	Duration    int    `query:"duration" validate:"omitempty,min=1"`
+	UserID      int    `query:"user_id" validate:"omitempty,min=1"`
+	ResourceIDs []int  `query:"resource_ids" validate:"omitempty,max=10,unique,dive,min=1"`
 }
 
 func AvailabilityTimeframeValidation(sl validator.StructLevel) {
 	req := sl.Current().Interface().(GetTrainerAvailabilityReq)
+	validateAvailabilityTimeframe(sl, req.StartsAt, req.EndsAt)
+}
 
-	parsedStartsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+type GetAvailabilityReq struct {
+	TrainerIDs  []int  `query:"trainer_ids" validate:"omitempty,max=50,unique,dive,min=1"`
+	StartsAt    string `query:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	EndsAt      string `query:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	Duration    int    `query:"duration" validate:"omitempty,min=1"`
+	UserID      int    `query:"user_id" validate:"omitempty,min=1"`
+	ResourceIDs []int  `query:"resource_ids" validate:"omitempty,max=10,unique,dive,min=1"`
+	First       int    `query:"first" validate:"omitempty,min=1,max=500"`
+}
+
+func MultiAvailabilityTimeframeValidation(sl validator.StructLevel) {
+	req := sl.Current().Interface().(GetAvailabilityReq)
+	validateAvailabilityTimeframe(sl, req.StartsAt, req.EndsAt)
+}
+
+func validateAvailabilityTimeframe(sl validator.StructLevel, startsAt, endsAt string) {
+	parsedStartsAt, err := time.Parse(time.RFC3339, startsAt)
 	if err != nil {
 		sl.ReportError(parsedStartsAt, "starts_at", "StartsAt", "datetime", "")
 	}
 
-	parsedEndsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+	parsedEndsAt, err := time.Parse(time.RFC3339, endsAt)
 	if err != nil {
 		sl.ReportError(parsedEndsAt, "ends_at", "EndsAt", "datetime", "")
 	}
@@ -138,3 +212,168 @@ func AvailabilityTimeframeValidation(sl validator.StructLevel) {
 		sl.ReportError(parsedEndsAt, "ends_at", "EndsAt", "timeframe-max", "")
 	}
 }
+
+type GetTrainerWorkingHoursReq struct {
+	TrainerID int `param:"trainer_id" validate:"required,min=1"`
+}
+
+type WorkingHoursReq struct {
+	Weekday   *int   `json:"weekday" validate:"required,min=0,max=6"`
+	StartTime string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"required,datetime=15:04"`
+}
+
+type PutTrainerWorkingHoursReq struct {
+	TrainerID    int               `param:"trainer_id" validate:"required,min=1"`
+	WorkingHours []WorkingHoursReq `json:"working_hours" validate:"required,min=1,dive"`
+}
+
+type GetTrainerSettingsReq struct {
+	TrainerID int `param:"trainer_id" validate:"required,min=1"`
+}
+
+type PutTrainerSettingsReq struct {
+	TrainerID    int    `param:"trainer_id" validate:"required,min=1"`
+	TimeZone     string `json:"time_zone" validate:"required,timezone"`
+	Durations    []int  `json:"durations" validate:"omitempty,dive,min=1"`
+	Capacity     int    `json:"capacity" validate:"omitempty,min=1"`
+	BufferBefore int    `json:"buffer_before" validate:"min=0"`
+	BufferAfter  int    `json:"buffer_after" validate:"min=0"`
+}
+
+type GetTrainerTimeOffReq struct {
+	TrainerID int    `param:"trainer_id" validate:"required,min=1"`
+	StartsAt  string `query:"starts_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt    string `query:"ends_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+func TimeOffTimeframeValidation(sl validator.StructLevel) {
+	req := sl.Current().Interface().(GetTrainerTimeOffReq)
+	validateOptionalTimeframe(sl, req.StartsAt, req.EndsAt)
+}
+
+type PostTrainerTimeOffReq struct {
+	TrainerID int    `param:"trainer_id" validate:"required,min=1"`
+	StartsAt  string `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt    string `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	AllDay    bool   `json:"all_day"`
+	Reason    string `json:"reason" validate:"max=255"`
+}
+
+type PutTrainerTimeOffReq struct {
+	TrainerID int    `param:"trainer_id" validate:"required,min=1"`
+	ID        int    `param:"id" validate:"required,min=1"`
+	StartsAt  string `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt    string `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	AllDay    bool   `json:"all_day"`
+	Reason    string `json:"reason" validate:"max=255"`
+}
+
+type DeleteTrainerTimeOffReq struct {
+	TrainerID int `param:"trainer_id" validate:"required,min=1"`
+	ID        int `param:"id" validate:"required,min=1"`
+}
+
+type GetHolidaysReq struct {
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type PostHolidayReq struct {
+	Date      string `json:"date" validate:"required,datetime=2006-01-02"`
+	Name      string `json:"name" validate:"required,max=255"`
+	StartTime string `json:"start_time" validate:"omitempty,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"omitempty,datetime=15:04"`
+}
+
+type PutHolidayReq struct {
+	ID        int    `param:"id" validate:"required,min=1"`
+	Date      string `json:"date" validate:"required,datetime=2006-01-02"`
+	Name      string `json:"name" validate:"required,max=255"`
+	StartTime string `json:"start_time" validate:"omitempty,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"omitempty,datetime=15:04"`
+}
+
+type DeleteHolidayReq struct {
+	ID int `param:"id" validate:"required,min=1"`
+}
+
+type GetTrainerWaitlistReq struct {
+	TrainerID int `param:"trainer_id" validate:"required,min=1"`
+}
+
+type PostTrainerWaitlistReq struct {
+	TrainerID int    `param:"trainer_id" validate:"required,min=1"`
+	UserID    int    `json:"user_id" validate:"required,min=1"`
+	StartsAt  string `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	EndsAt    string `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00,is-future-date"`
+	AutoBook  bool   `json:"auto_book"`
+}
+
+type WaitlistEntryReq struct {
+	ID int `param:"id" validate:"required,min=1"`
+}
+
+type GetUserReq struct {
+	UserID int `param:"user_id" validate:"required,min=1"`
+}
+
+type PostUserReq struct {
+	Name   string `json:"name" validate:"required,max=255"`
+	Email  string `json:"email" validate:"required,email,max=255"`
+	Active *bool  `json:"active"`
+}
+
+type PutUserReq struct {
+	UserID int    `param:"user_id" validate:"required,min=1"`
+	Name   string `json:"name" validate:"required,max=255"`
+	Email  string `json:"email" validate:"required,email,max=255"`
+	Active *bool  `json:"active" validate:"required"`
+}
+
+type GetTrainerReq struct {
+	TrainerID int `param:"trainer_id" validate:"required,min=1"`
+}
+
+type PostTrainerReq struct {
+	Name   string `json:"name" validate:"required,max=255"`
+	Email  string `json:"email" validate:"required,email,max=255"`
+	Active *bool  `json:"active"`
+}
+
+type PutTrainerReq struct {
+	TrainerID int    `param:"trainer_id" validate:"required,min=1"`
+	Name      string `json:"name" validate:"required,max=255"`
+	Email     string `json:"email" validate:"required,email,max=255"`
+	Active    *bool  `json:"active" validate:"required"`
+}
+
+type GetResourceReq struct {
+	ResourceID int `param:"resource_id" validate:"required,min=1"`
+}
+
+type PostResourceReq struct {
+	Name   string `json:"name" validate:"required,max=255"`
+	Kind   string `json:"kind" validate:"required,oneof=room equipment"`
+	Active *bool  `json:"active"`
+}
+
+type PutResourceReq struct {
+	ResourceID int    `param:"resource_id" validate:"required,min=1"`
+	Name       string `json:"name" validate:"required,max=255"`
+	Kind       string `json:"kind" validate:"required,oneof=room equipment"`
+	Active     *bool  `json:"active" validate:"required"`
+}
+
+type GetResourceAppointmentsReq struct {
+	ResourceID int    `param:"resource_id" validate:"required,min=1"`
+	StartsAt   string `query:"starts_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt     string `query:"ends_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit      int    `query:"limit" validate:"omitempty,min=1,max=500"`
+	Cursor     string `query:"cursor"`
+}
+
+func ResourceAppointmentTimeframeValidation(sl validator.StructLevel) {
+	req := sl.Current().Interface().(GetResourceAppointmentsReq)
+	validateOptionalTimeframe(sl, req.StartsAt, req.EndsAt)
+}
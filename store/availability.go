@@ -0,0 +1,342 @@
+package store
+
+import (
+	"fmt"
+	"future-app/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// availabilitySource is the data availability is computed from. Both backends
+// implement it so they share the rules for open timeslots.
+type availabilitySource interface {
+	GetTrainerSettings(trainerID int) (*models.TrainerSettings, error)
+	GetTrainerWorkingHours(trainerID int) (models.WeeklySchedule, error)
+	getTrainerSettingsByIDs(trainerIDs []int) (map[int]*models.TrainerSettings, error)
+	getTrainerWorkingHoursByIDs(trainerIDs []int) (map[int]models.WeeklySchedule, error)
+	GetAppointmentsByTrainerID(trainerID int, startsAt, endsAt time.Time, page models.Page) ([]*models.Appointment, *models.Cursor, error)
+	GetTimeOffByTrainerID(trainerID int, startsAt, endsAt time.Time) ([]*models.TimeOff, error)
+	GetHolidays(from, to string) ([]*models.Holiday, error)
+	getActiveTrainerIDs() ([]int, error)
+	getBusyTimeslots(trainerIDs []int, startsAt, endsAt time.Time) (map[int][]models.Timeslot, map[int][]models.Timeslot, error)
+	getUserBusyTimeslots(userID int, startsAt, endsAt time.Time) ([]models.Timeslot, error)
+	getHeldTimeslots(trainerIDs []int, userID int, startsAt, endsAt time.Time) (map[int][]models.Timeslot, error)
+	getResourceBusyTimeslots(resourceIDs []int, startsAt, endsAt time.Time) (map[int][]models.Timeslot, error)
+}
+
+// GetAvailability returns the timeslots in which at least one of the trainers
+// has a seat open, each annotated with every such trainer and the seats open
+// across them. Seats held by another user's open waitlist offer count as
+// taken. Without trainer IDs every active trainer is searched, and trainers
+// that do not offer the duration are skipped. With a user ID, timeslots that
+// overlap the user's own appointments are left out, and with resource IDs each
+// trainer is only offered timeslots in which no other trainer holds any of the
+// resources. A positive first stops after that many timeslots.
+func (s *Store) GetAvailability(trainerIDs []int, startsAt, endsAt time.Time, duration time.Duration, userID int, resourceIDs []int, first int) ([]*models.AvailableTimeslot, error) {
+	return availability(s, trainerIDs, startsAt, endsAt, duration, userID, resourceIDs, first)
+}
+
+func availability(s availabilitySource, trainerIDs []int, startsAt, endsAt time.Time, duration time.Duration, userID int, resourceIDs []int, first int) ([]*models.AvailableTimeslot, error) {
+	if len(trainerIDs) == 0 {
+		ids, err := s.getActiveTrainerIDs()
+		if err != nil {
+			return nil, err
+		}
+		trainerIDs = ids
+	}
+
+	timeslots := make([]*models.AvailableTimeslot, 0)
+	if len(trainerIDs) == 0 {
+		return timeslots, nil
+	}
+
+	// INFO: Slots are generated for whole days in each trainer's time zone, so the window is padded to cover all of them
+	from := startsAt.AddDate(0, 0, -1)
+	to := endsAt.AddDate(0, 0, 2)
+
+	booked, busy, err := s.getBusyTimeslots(trainerIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	userBusy, err := s.getUserBusyTimeslots(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := s.getHeldTimeslots(trainerIDs, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceBusy, err := s.getResourceBusyTimeslots(resourceIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.GetHolidays(
+		from.UTC().Format(models.HolidayDateFormat),
+		to.UTC().Format(models.HolidayDateFormat),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	settingsByID, err := s.getTrainerSettingsByIDs(trainerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.getTrainerWorkingHoursByIDs(trainerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byStart := make(map[int64]*models.AvailableTimeslot)
+
+	sortedIDs := make([]int, len(trainerIDs))
+	copy(sortedIDs, trainerIDs)
+	sort.Ints(sortedIDs)
+
+	for _, trainerID := range sortedIDs {
+		settings := settingsByID[trainerID]
+		if !settings.AllowsDuration(duration) {
+			continue
+		}
+
+		// INFO: A fresh slice keeps the appends below from writing into the map's backing array
+		trainerBusy := make([]models.Timeslot, 0, len(busy[trainerID])+len(userBusy))
+		trainerBusy = append(trainerBusy, busy[trainerID]...)
+		trainerBusy = append(trainerBusy, userBusy...)
+		trainerBusy = append(trainerBusy, resourceBusyFor(resourceBusy, trainerID)...)
+		for _, holiday := range holidays {
+			trainerBusy = append(trainerBusy, holiday.On(settings.Location()))
+		}
+
+		// INFO: Seats held by an open waitlist offer are taken like booked ones
+		trainerBooked := make([]models.Timeslot, 0, len(booked[trainerID])+len(held[trainerID]))
+		trainerBooked = append(trainerBooked, booked[trainerID]...)
+		trainerBooked = append(trainerBooked, held[trainerID]...)
+
+		for _, timeslot := range openTimeslots(schedules[trainerID], trainerBusy, trainerBooked, settings, startsAt, endsAt, duration) {
+			available, ok := byStart[timeslot.StartsAt.Unix()]
+			if !ok {
+				available = &models.AvailableTimeslot{Timeslot: timeslot.Timeslot, TrainerIDs: make([]int, 0, 1)}
+				byStart[timeslot.StartsAt.Unix()] = available
+				timeslots = append(timeslots, available)
+			}
+
+			available.TrainerIDs = append(available.TrainerIDs, trainerID)
+			available.Seats += timeslot.Seats
+		}
+	}
+
+	sort.Slice(timeslots, func(i, j int) bool {
+		return timeslots[i].StartsAt.Before(timeslots[j].StartsAt)
+	})
+
+	if first > 0 && len(timeslots) > first {
+		timeslots = timeslots[:first]
+	}
+
+	return timeslots, nil
+}
+
+func (s *Store) getActiveTrainerIDs() ([]int, error) {
+	ids := make([]int, 0)
+
+	rows, err := s.DB.Query(`SELECT id FROM trainers WHERE active = TRUE ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// getBusyTimeslots returns the scheduled appointments and the time off of every
+// trainer overlapping the window, each keyed by trainer ID.
+func (s *Store) getBusyTimeslots(trainerIDs []int, startsAt, endsAt time.Time) (map[int][]models.Timeslot, map[int][]models.Timeslot, error) {
+	booked := make(map[int][]models.Timeslot, len(trainerIDs))
+	busy := make(map[int][]models.Timeslot, len(trainerIDs))
+
+	// INFO: Placeholders are numbered in order of appearance, so the window must come first
+	in, ids := inTrainerIDs(trainerIDs, 3)
+	args := append([]any{formatTime(startsAt), formatTime(endsAt)}, ids...)
+
+	query := `
+	SELECT trainer_id, starts_at, ends_at, TRUE
+	FROM appointments
+	WHERE ends_at > $1 AND starts_at < $2
+	AND status = 'scheduled' AND trainer_id IN (` + in + `)
+	UNION ALL
+	SELECT trainer_id, starts_at, ends_at, FALSE
+	FROM trainer_time_off
+	WHERE ends_at > $1 AND starts_at < $2
+	AND trainer_id IN (` + in + `)
+	`
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var trainerID int
+		var timeslot models.Timeslot
+		var isAppointment bool
+		if err := rows.Scan(&trainerID, &timeslot.StartsAt, &timeslot.EndsAt, &isAppointment); err != nil {
+			return nil, nil, err
+		}
+
+		if isAppointment {
+			booked[trainerID] = append(booked[trainerID], timeslot)
+		} else {
+			busy[trainerID] = append(busy[trainerID], timeslot)
+		}
+	}
+
+	return booked, busy, rows.Err()
+}
+
+// inTrainerIDs returns the placeholders for an IN list of the trainer IDs,
+// numbered from first, along with their arguments.
+func inTrainerIDs(trainerIDs []int, first int) (string, []any) {
+	placeholders := make([]string, len(trainerIDs))
+	args := make([]any, len(trainerIDs))
+	for i, trainerID := range trainerIDs {
+		placeholders[i] = fmt.Sprintf("$%d", first+i)
+		args[i] = trainerID
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// getTrainerSettingsByIDs returns the settings of every trainer, keyed by
+// trainer ID. Trainers without saved settings get the defaults.
+func (s *Store) getTrainerSettingsByIDs(trainerIDs []int) (map[int]*models.TrainerSettings, error) {
+	settingsByID := make(map[int]*models.TrainerSettings, len(trainerIDs))
+
+	in, args := inTrainerIDs(trainerIDs, 1)
+	query := `
+	SELECT trainer_id, time_zone, durations, capacity, buffer_before, buffer_after
+	FROM trainer_settings
+	WHERE trainer_id IN (` + in + `)
+	`
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		settings, err := scanTrainerSettings(rows)
+		if err != nil {
+			return nil, err
+		}
+		settingsByID[settings.TrainerID] = settings
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, trainerID := range trainerIDs {
+		if _, ok := settingsByID[trainerID]; !ok {
+			settingsByID[trainerID] = models.DefaultTrainerSettings(trainerID)
+		}
+	}
+
+	return settingsByID, nil
+}
+
+// getTrainerWorkingHoursByIDs returns the weekly schedule of every trainer,
+// keyed by trainer ID. Trainers without saved hours get the default schedule.
+func (s *Store) getTrainerWorkingHoursByIDs(trainerIDs []int) (map[int]models.WeeklySchedule, error) {
+	schedules := make(map[int]models.WeeklySchedule, len(trainerIDs))
+
+	in, args := inTrainerIDs(trainerIDs, 1)
+	query := `
+	SELECT trainer_id, weekday, start_time, end_time
+	FROM trainer_working_hours
+	WHERE trainer_id IN (` + in + `)
+	ORDER BY trainer_id ASC, weekday ASC, start_time ASC
+	`
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var trainerID, weekday int
+		var workingHours models.WorkingHours
+		if err := rows.Scan(&trainerID, &weekday, &workingHours.StartTime, &workingHours.EndTime); err != nil {
+			return nil, err
+		}
+
+		workingHours.Weekday = time.Weekday(weekday)
+		schedules[trainerID] = append(schedules[trainerID], workingHours)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, trainerID := range trainerIDs {
+		if len(schedules[trainerID]) == 0 {
+			schedules[trainerID] = models.DefaultWeeklySchedule()
+		}
+	}
+
+	return schedules, nil
+}
+
+// getUserBusyTimeslots returns the user's scheduled appointments overlapping
+// the window. A zero user ID returns none.
+func (s *Store) getUserBusyTimeslots(userID int, startsAt, endsAt time.Time) ([]models.Timeslot, error) {
+	busy := make([]models.Timeslot, 0)
+	if userID == 0 {
+		return busy, nil
+	}
+
+	query := `
+	SELECT starts_at, ends_at
+	FROM appointments
+	WHERE user_id = $1 AND status = 'scheduled'
+	AND ends_at > $2 AND starts_at < $3
+	`
+
+	rows, err := s.DB.Query(query, userID, formatTime(startsAt), formatTime(endsAt))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var timeslot models.Timeslot
+		if err := rows.Scan(&timeslot.StartsAt, &timeslot.EndsAt); err != nil {
+			return nil, err
+		}
+
+		busy = append(busy, timeslot)
+	}
+
+	return busy, rows.Err()
+}
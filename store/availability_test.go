@@ -0,0 +1,129 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAvailability(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	if _, err := store.CreateAppointment(&models.Appointment{
+		UserID:    1,
+		TrainerID: 1,
+		StartsAt:  monday.Add(time.Hour * 9),
+		EndsAt:    monday.Add(time.Hour * 9).Add(time.Minute * 30),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	timeOff, err := models.NewTimeOff(2, monday.Add(time.Hour*9), monday.Add(time.Hour*12), false, "Dentist", tz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateTimeOff(timeOff); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.DeactivateTrainer(5); err != nil {
+		t.Fatal(err)
+	}
+
+	find := func(timeslots []*models.AvailableTimeslot, hour, minute int) *models.AvailableTimeslot {
+		startsAt := monday.Add(time.Hour*time.Duration(hour) + time.Minute*time.Duration(minute))
+		for _, timeslot := range timeslots {
+			if timeslot.StartsAt.Equal(startsAt) {
+				return timeslot
+			}
+		}
+		return nil
+	}
+
+	t.Run("Selected trainers", func(t *testing.T) {
+		timeslots, err := store.GetAvailability([]int{2, 1}, monday, tuesday, time.Minute*30, 0, nil, 0)
+		assert.NoError(t, err)
+		assert.Len(t, timeslots, 17)
+
+		assert.Equal(t, []int{1, 2}, find(timeslots, 8, 0).TrainerIDs)
+		assert.Nil(t, find(timeslots, 9, 0))
+		assert.Equal(t, []int{1}, find(timeslots, 9, 30).TrainerIDs)
+		assert.Equal(t, []int{1, 2}, find(timeslots, 12, 0).TrainerIDs)
+
+		// INFO: Timeslots are in order
+		for i := 1; i < len(timeslots); i++ {
+			assert.True(t, timeslots[i].StartsAt.After(timeslots[i-1].StartsAt))
+		}
+	})
+
+	t.Run("All active trainers", func(t *testing.T) {
+		timeslots, err := store.GetAvailability(nil, monday, tuesday, time.Minute*30, 0, nil, 0)
+		assert.NoError(t, err)
+		assert.Len(t, timeslots, 18)
+		assert.Equal(t, []int{1, 2, 3, 4}, find(timeslots, 8, 0).TrainerIDs)
+		assert.Equal(t, []int{3, 4}, find(timeslots, 9, 0).TrainerIDs)
+	})
+
+	t.Run("First available timeslots", func(t *testing.T) {
+		timeslots, err := store.GetAvailability([]int{1, 2}, monday, tuesday, time.Minute*30, 0, nil, 3)
+		assert.NoError(t, err)
+
+		starts := make([]string, 0)
+		for _, timeslot := range timeslots {
+			starts = append(starts, timeslot.StartsAt.Format("15:04"))
+		}
+		assert.Equal(t, []string{"08:00", "08:30", "09:30"}, starts)
+	})
+
+	t.Run("Trainers without the duration are skipped", func(t *testing.T) {
+		_, err := store.SetTrainerSettings(&models.TrainerSettings{TrainerID: 3, TimeZone: models.DEFAULT_TZ, Durations: []int{60}})
+		assert.NoError(t, err)
+
+		timeslots, err := store.GetAvailability([]int{3, 4}, monday, tuesday, time.Minute*30, 0, nil, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{4}, find(timeslots, 8, 0).TrainerIDs)
+	})
+
+	t.Run("Trainers in different time zones", func(t *testing.T) {
+		_, err := store.SetTrainerSettings(&models.TrainerSettings{TrainerID: 4, TimeZone: "America/New_York", Durations: models.DefaultDurations})
+		assert.NoError(t, err)
+
+		timeslots, err := store.GetAvailability([]int{1, 4}, monday, tuesday, time.Minute*30, 0, nil, 0)
+		assert.NoError(t, err)
+
+		// INFO: 08:00 in New York is 05:00 in Los Angeles
+		assert.Equal(t, []int{4}, find(timeslots, 5, 0).TrainerIDs)
+		assert.Equal(t, []int{1, 4}, find(timeslots, 8, 0).TrainerIDs)
+		assert.Equal(t, []int{1}, find(timeslots, 14, 0).TrainerIDs)
+	})
+
+	t.Run("Each trainer keeps their own working hours", func(t *testing.T) {
+		_, err := store.SetTrainerWorkingHours(2, models.WeeklySchedule{
+			{Weekday: time.Monday, StartTime: "13:00", EndTime: "15:00"},
+		})
+		assert.NoError(t, err)
+
+		timeslots, err := store.GetAvailability([]int{1, 2}, monday, tuesday, time.Minute*30, 0, nil, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1}, find(timeslots, 8, 0).TrainerIDs)
+		assert.Equal(t, []int{1, 2}, find(timeslots, 13, 0).TrainerIDs)
+	})
+
+	t.Run("Excludes the user's own appointments", func(t *testing.T) {
+		// INFO: User 1 is booked with trainer 1 from 9:00 to 9:30
+		timeslots, err := store.GetAvailability([]int{4}, monday, tuesday, time.Minute*30, 1, nil, 0)
+		assert.NoError(t, err)
+		assert.Nil(t, find(timeslots, 9, 0))
+		assert.Equal(t, []int{4}, find(timeslots, 9, 30).TrainerIDs)
+	})
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"future-app/models"
+	"time"
+)
+
+// WithAudit returns a copy of the store that records its appointment changes
+// under the given actor and request ID. The copy shares the database.
+func (s *Store) WithAudit(audit models.Audit) Repository {
+	scoped := *s
+	scoped.audit = audit
+	return &scoped
+}
+
+// recordAppointmentChange appends the appointment's current state to its
+// history, next to the state it had before the change. Snapshots are kept in
+// UTC like every other timestamp.
+func recordAppointmentChange(q querier, audit models.Audit, before *models.Appointment, id int) error {
+	after, err := getAppointmentByID(q, id)
+	if err != nil {
+		return err
+	}
+
+	actor := audit.Actor
+	if actor == "" {
+		actor = models.ActorSystem
+	}
+
+	var beforeState *string
+	if before != nil {
+		encoded, err := json.Marshal(before.In(time.UTC))
+		if err != nil {
+			return err
+		}
+		state := string(encoded)
+		beforeState = &state
+	}
+
+	afterState, err := json.Marshal(after.In(time.UTC))
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO appointment_history (appointment_id, action, actor, request_id, changed_at, before_state, after_state)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = q.Exec(
+		query,
+		id,
+		models.AppointmentAction(before, after),
+		actor,
+		audit.RequestID,
+		formatTime(time.Now()),
+		beforeState,
+		string(afterState),
+	)
+	return err
+}
+
+// GetAppointmentHistory returns every recorded change to the appointment,
+// oldest first, in the trainer's time zone.
+func (s *Store) GetAppointmentHistory(id int) ([]*models.AppointmentChange, error) {
+	appointment, err := getAppointmentByID(s.DB, id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT id, appointment_id, action, actor, request_id, changed_at, before_state, after_state
+	FROM appointment_history
+	WHERE appointment_id = $1
+	ORDER BY id ASC
+	`
+
+	rows, err := s.DB.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	changes := make([]*models.AppointmentChange, 0)
+	for rows.Next() {
+		var change models.AppointmentChange
+		var beforeState sql.NullString
+		var afterState string
+
+		if err := rows.Scan(
+			&change.ID,
+			&change.AppointmentID,
+			&change.Action,
+			&change.Actor,
+			&change.RequestID,
+			&change.ChangedAt,
+			&beforeState,
+			&afterState,
+		); err != nil {
+			return nil, err
+		}
+
+		if beforeState.Valid {
+			change.Before = new(models.Appointment)
+			if err := json.Unmarshal([]byte(beforeState.String), change.Before); err != nil {
+				return nil, err
+			}
+		}
+
+		change.After = new(models.Appointment)
+		if err := json.Unmarshal([]byte(afterState), change.After); err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, change.In(appointment.StartsAt.Location()))
+	}
+
+	return changes, rows.Err()
+}
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppointmentHistory(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+	audited := store.WithAudit(models.Audit{Actor: "front-desk", RequestID: "req-1"})
+
+	var appointment *models.Appointment
+
+	t.Run("Create records the new state", func(t *testing.T) {
+		appointment, err = audited.CreateAppointment(&models.Appointment{
+			UserID:    1,
+			TrainerID: 1,
+			StartsAt:  monday,
+			EndsAt:    monday.Add(time.Minute * 30),
+		})
+		assert.NoError(t, err)
+
+		changes, err := store.GetAppointmentHistory(appointment.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, changes, 1) {
+			assert.Equal(t, models.AppointmentActionCreated, changes[0].Action)
+			assert.Equal(t, "front-desk", changes[0].Actor)
+			assert.Equal(t, "req-1", changes[0].RequestID)
+			assert.Nil(t, changes[0].Before)
+			assert.Equal(t, monday, changes[0].After.StartsAt)
+			assert.Equal(t, tz, changes[0].ChangedAt.Location())
+		}
+	})
+
+	t.Run("Reschedule and cancel record before and after", func(t *testing.T) {
+		startsAt := monday.Add(time.Hour)
+		_, err := audited.RescheduleAppointment(appointment.ID, startsAt, startsAt.Add(time.Minute*30))
+		assert.NoError(t, err)
+
+		_, err = store.CancelAppointment(appointment.ID)
+		assert.NoError(t, err)
+
+		changes, err := store.GetAppointmentHistory(appointment.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, changes, 3) {
+			assert.Equal(t, models.AppointmentActionRescheduled, changes[1].Action)
+			assert.Equal(t, monday, changes[1].Before.StartsAt)
+			assert.Equal(t, startsAt, changes[1].After.StartsAt)
+
+			// INFO: The unscoped store records changes as the system
+			assert.Equal(t, models.AppointmentActionCancelled, changes[2].Action)
+			assert.Equal(t, models.ActorSystem, changes[2].Actor)
+			assert.Empty(t, changes[2].RequestID)
+			assert.Equal(t, models.AppointmentStatusScheduled, changes[2].Before.Status)
+			assert.Equal(t, models.AppointmentStatusCancelled, changes[2].After.Status)
+		}
+	})
+
+	t.Run("Failed changes are not recorded", func(t *testing.T) {
+		_, err := audited.CancelAppointment(appointment.ID)
+		assert.ErrorIs(t, err, ErrAppointmentCancelled)
+
+		changes, err := store.GetAppointmentHistory(appointment.ID)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 3)
+	})
+
+	t.Run("Waitlist bookings are recorded under the change that freed the slot", func(t *testing.T) {
+		startsAt := monday.Add(time.Hour * 3)
+		booked, err := store.CreateAppointment(&models.Appointment{
+			UserID:    2,
+			TrainerID: 1,
+			StartsAt:  startsAt,
+			EndsAt:    startsAt.Add(time.Minute * 30),
+		})
+		assert.NoError(t, err)
+
+		entry, err := store.CreateWaitlistEntry(getTestWaitlistEntry(3, startsAt, true))
+		assert.NoError(t, err)
+
+		_, err = audited.CancelAppointment(booked.ID)
+		assert.NoError(t, err)
+
+		entry, err = store.GetWaitlistEntry(entry.ID)
+		assert.NoError(t, err)
+
+		changes, err := store.GetAppointmentHistory(*entry.AppointmentID)
+		assert.NoError(t, err)
+		if assert.Len(t, changes, 1) {
+			assert.Equal(t, models.AppointmentActionCreated, changes[0].Action)
+			assert.Equal(t, "front-desk", changes[0].Actor)
+			assert.Equal(t, "req-1", changes[0].RequestID)
+		}
+	})
+
+	t.Run("Cancelling a series records every occurrence", func(t *testing.T) {
+		series, err := audited.CreateAppointmentSeries(4, 2, monday, monday.Add(time.Hour), &models.Recurrence{Interval: 1, Count: 3})
+		assert.NoError(t, err)
+
+		_, err = audited.CancelFollowingAppointments(series.Booked[1].ID)
+		assert.NoError(t, err)
+
+		for i, occurrence := range series.Booked {
+			changes, err := store.GetAppointmentHistory(occurrence.ID)
+			assert.NoError(t, err)
+
+			// INFO: Occurrences before the cancelled one are left as they were
+			if i == 0 {
+				assert.Len(t, changes, 1)
+				continue
+			}
+			if assert.Len(t, changes, 2) {
+				assert.Equal(t, models.AppointmentActionCancelled, changes[1].Action)
+				assert.Equal(t, "front-desk", changes[1].Actor)
+			}
+		}
+	})
+
+	t.Run("Unknown appointment", func(t *testing.T) {
+		_, err := store.GetAppointmentHistory(999)
+		assert.ErrorIs(t, err, ErrAppointmentNotFound)
+	})
+
+	t.Run("History is append-only", func(t *testing.T) {
+		_, err := store.DB.Exec(`UPDATE appointment_history SET actor = 'someone else'`)
+		assert.ErrorContains(t, err, "Appointment history is append-only")
+
+		_, err = store.DB.Exec(`DELETE FROM appointment_history`)
+		assert.ErrorContains(t, err, "Appointment history is append-only")
+
+		changes, err := store.GetAppointmentHistory(appointment.ID)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 3)
+	})
+}
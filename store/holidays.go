@@ -0,0 +1,163 @@
+package store
+
+import (
+	"errors"
+	"future-app/models"
+)
+
+var ErrHolidayNotFound = errors.New("Holiday not found")
+var ErrHoliday = errors.New("Timeslot falls on a holiday")
+
+func (s *Store) CreateHoliday(data *models.Holiday) (*models.Holiday, error) {
+	return createHoliday(s.DB, data)
+}
+
+func createHoliday(q querier, data *models.Holiday) (*models.Holiday, error) {
+	query := `
+	INSERT INTO holidays (date, name, start_time, end_time)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	res, err := q.Exec(query, data.Date, data.Name, data.StartTime, data.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data.ID = int(id)
+	return data, nil
+}
+
+func (s *Store) ImportHolidays(holidays []*models.Holiday) ([]*models.Holiday, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, holiday := range holidays {
+		if _, err := createHoliday(tx, holiday); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return holidays, nil
+}
+
+func (s *Store) UpdateHoliday(data *models.Holiday) (*models.Holiday, error) {
+	query := `
+	UPDATE holidays
+	SET date = $1, name = $2, start_time = $3, end_time = $4
+	WHERE id = $5
+	`
+
+	res, err := s.DB.Exec(query, data.Date, data.Name, data.StartTime, data.EndTime, data.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrHolidayNotFound
+	}
+
+	return data, nil
+}
+
+func (s *Store) DeleteHoliday(id int) error {
+	res, err := s.DB.Exec(`DELETE FROM holidays WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrHolidayNotFound
+	}
+
+	return nil
+}
+
+// GetHolidays returns holidays between the from and to dates (inclusive).
+// Empty dates leave the range open.
+func (s *Store) GetHolidays(from, to string) ([]*models.Holiday, error) {
+	return getHolidays(s.DB, from, to)
+}
+
+func getHolidays(q querier, from, to string) ([]*models.Holiday, error) {
+	holidays := make([]*models.Holiday, 0)
+
+	query := `
+	SELECT id, date, name, start_time, end_time
+	FROM holidays
+	WHERE ($1 = '' OR date >= $1) AND ($2 = '' OR date <= $2)
+	ORDER BY date ASC, start_time ASC
+	`
+
+	rows, err := q.Query(query, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var holiday models.Holiday
+		if err := rows.Scan(
+			&holiday.ID,
+			&holiday.Date,
+			&holiday.Name,
+			&holiday.StartTime,
+			&holiday.EndTime,
+		); err != nil {
+			return nil, err
+		}
+
+		holidays = append(holidays, &holiday)
+	}
+
+	return holidays, rows.Err()
+}
+
+func validateHolidays(q querier, data *models.Appointment) error {
+	settings, err := getTrainerSettings(q, data.TrainerID)
+	if err != nil {
+		return err
+	}
+
+	loc := settings.Location()
+
+	holidays, err := getHolidays(
+		q,
+		data.StartsAt.In(loc).Format(models.HolidayDateFormat),
+		data.EndsAt.In(loc).Format(models.HolidayDateFormat),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, holiday := range holidays {
+		if holiday.On(loc).Overlaps(data.StartsAt, data.EndsAt) {
+			return ErrHoliday
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHolidays(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	var created *models.Holiday
+
+	t.Run("Create holiday", func(t *testing.T) {
+		holiday, err := models.NewHoliday("2030-07-08", "Company Retreat", "", "")
+		assert.NoError(t, err)
+
+		created, err = store.CreateHoliday(holiday)
+		assert.NoError(t, err)
+		assert.NotZero(t, created.ID)
+	})
+
+	t.Run("Import holidays", func(t *testing.T) {
+		partial, err := models.NewHoliday("2030-07-09", "Early Close", "12:00", "00:00")
+		assert.NoError(t, err)
+		other, err := models.NewHoliday("2030-12-25", "Christmas Day", "", "")
+		assert.NoError(t, err)
+
+		_, err = store.ImportHolidays([]*models.Holiday{partial, other})
+		assert.NoError(t, err)
+	})
+
+	t.Run("List holidays", func(t *testing.T) {
+		holidays, err := store.GetHolidays("", "")
+		assert.NoError(t, err)
+		assert.Len(t, holidays, 3)
+
+		holidays, err = store.GetHolidays("2030-07-01", "2030-07-31")
+		assert.NoError(t, err)
+		assert.Len(t, holidays, 2)
+		assert.Equal(t, created, holidays[0])
+	})
+
+	t.Run("List holidays with one bound", func(t *testing.T) {
+		holidays, err := store.GetHolidays("2030-07-09", "")
+		assert.NoError(t, err)
+		if assert.Len(t, holidays, 2) {
+			assert.Equal(t, "2030-07-09", holidays[0].Date)
+			assert.Equal(t, "2030-12-25", holidays[1].Date)
+		}
+
+		holidays, err = store.GetHolidays("", "2030-07-08")
+		assert.NoError(t, err)
+		if assert.Len(t, holidays, 1) {
+			assert.Equal(t, created, holidays[0])
+		}
+	})
+
+	t.Run("Booking on a holiday", func(t *testing.T) {
+		appointment := &models.Appointment{
+			UserID:    1,
+			TrainerID: 1,
+			StartsAt:  monday.Add(time.Hour * 9),
+			EndsAt:    monday.Add(time.Hour * 9).Add(time.Minute * 30),
+		}
+
+		_, err := store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrHoliday)
+	})
+
+	t.Run("Booking during a partial holiday", func(t *testing.T) {
+		appointment := &models.Appointment{
+			UserID:    1,
+			TrainerID: 1,
+			StartsAt:  tuesday.Add(time.Hour * 11).Add(time.Minute * 30),
+			EndsAt:    tuesday.Add(time.Hour * 12).Add(time.Minute * 30),
+		}
+
+		_, err := store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrHoliday)
+
+		appointment.EndsAt = tuesday.Add(time.Hour * 12)
+		_, err = store.CreateAppointment(appointment)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Availability excludes holidays", func(t *testing.T) {
+		timeslots, err := store.GetTrainerAvailability(1, monday, tuesday.Add(time.Hour*24), time.Minute*30, 0, nil)
+		assert.NoError(t, err)
+
+		// INFO: Monday is closed, Tuesday closes at noon and 11:30 is booked
+		assert.Len(t, *timeslots, 7)
+		for _, timeslot := range *timeslots {
+			assert.Equal(t, tuesday.Day(), timeslot.StartsAt.Day())
+			assert.False(t, timeslot.EndsAt.After(tuesday.Add(time.Hour*11).Add(time.Minute*30)))
+		}
+	})
+
+	t.Run("Holidays apply in the trainer's time zone", func(t *testing.T) {
+		settings, err := models.NewTrainerSettings(2, "Europe/London", nil, 0, 0, 0)
+		assert.NoError(t, err)
+		_, err = store.SetTrainerSettings(settings)
+		assert.NoError(t, err)
+
+		london, _ := models.LoadLocation("Europe/London")
+		londonMonday := time.Date(2030, 7, 8, 0, 0, 0, 0, london)
+
+		timeslots, err := store.GetTrainerAvailability(2, londonMonday, londonMonday.Add(time.Hour*24), time.Minute*30, 0, nil)
+		assert.NoError(t, err)
+		assert.Len(t, *timeslots, 0)
+	})
+
+	t.Run("Update holiday", func(t *testing.T) {
+		holiday, err := models.NewHoliday("2030-07-10", "Company Retreat", "", "")
+		assert.NoError(t, err)
+		holiday.ID = created.ID
+
+		_, err = store.UpdateHoliday(holiday)
+		assert.NoError(t, err)
+
+		holiday.ID = 999
+		_, err = store.UpdateHoliday(holiday)
+		assert.ErrorIs(t, err, ErrHolidayNotFound)
+	})
+
+	t.Run("Delete holiday", func(t *testing.T) {
+		err := store.DeleteHoliday(created.ID)
+		assert.NoError(t, err)
+
+		err = store.DeleteHoliday(created.ID)
+		assert.ErrorIs(t, err, ErrHolidayNotFound)
+	})
+}
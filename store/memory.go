@@ -0,0 +1,802 @@
+package store
+
+import (
+	"future-app/models"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps every record in process memory. It needs no database file,
+// which suits tests and local development, and everything is lost when the
+// process exits.
+type MemoryStore struct {
+	state *memoryState
+	audit models.Audit
+}
+
+// memoryState is shared between a store and the copies returned by WithAudit.
+type memoryState struct {
+	mu   sync.RWMutex
+	data *memoryData
+}
+
+// memoryData holds the records by ID. Records are stored by value and never
+// modified in place. Writes go through put and remove, which journal the value
+// they replace so update can undo a failed write.
+type memoryData struct {
+	users        map[int]models.User
+	trainers     map[int]models.Trainer
+	settings     map[int]models.TrainerSettings
+	workingHours map[int]models.WeeklySchedule
+	timeOff      map[int]models.TimeOff
+	holidays     map[int]models.Holiday
+	resources    map[int]models.Resource
+	appointments map[int]models.Appointment
+	series       map[int]models.AppointmentSeries
+	waitlist     map[int]models.WaitlistEntry
+	history      map[int]models.AppointmentChange
+	lastIDs      map[string]int
+	undo         []func()
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{state: &memoryState{data: &memoryData{
+		users:        make(map[int]models.User),
+		trainers:     make(map[int]models.Trainer),
+		settings:     make(map[int]models.TrainerSettings),
+		workingHours: make(map[int]models.WeeklySchedule),
+		timeOff:      make(map[int]models.TimeOff),
+		holidays:     make(map[int]models.Holiday),
+		resources:    make(map[int]models.Resource),
+		appointments: make(map[int]models.Appointment),
+		series:       make(map[int]models.AppointmentSeries),
+		waitlist:     make(map[int]models.WaitlistEntry),
+		history:      make(map[int]models.AppointmentChange),
+		lastIDs:      make(map[string]int),
+	}}}
+}
+
+// Init is a no-op since there is no schema to migrate.
+func (m *MemoryStore) Init() error {
+	return nil
+}
+
+func (m *MemoryStore) Close() {}
+
+// WithAudit returns a copy of the store that records its appointment changes
+// under the given actor and request ID. The copy shares the records.
+func (m *MemoryStore) WithAudit(audit models.Audit) Repository {
+	return &MemoryStore{state: m.state, audit: audit}
+}
+
+// read runs fn against the current records.
+func (m *MemoryStore) read(fn func(d *memoryData) error) error {
+	m.state.mu.RLock()
+	defer m.state.mu.RUnlock()
+
+	return fn(m.state.data)
+}
+
+// update runs fn against the records and undoes its writes unless it
+// succeeds, so a failed write leaves nothing behind like a rolled back
+// transaction. Writes are serialized so concurrent bookings cannot both
+// succeed, and readers never see a write in progress.
+func (m *MemoryStore) update(fn func(d *memoryData) error) error {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+
+	data := m.state.data
+	data.undo = data.undo[:0]
+
+	committed := false
+	defer func() {
+		if !committed {
+			data.rollback()
+		}
+	}()
+
+	if err := fn(data); err != nil {
+		return err
+	}
+
+	committed = true
+	return nil
+}
+
+// rollback undoes the journaled writes, newest first.
+func (d *memoryData) rollback() {
+	for i := len(d.undo) - 1; i >= 0; i-- {
+		d.undo[i]()
+	}
+	d.undo = d.undo[:0]
+}
+
+// put stores the record and journals the value it replaces.
+func put[V any](d *memoryData, records map[int]V, id int, value V) {
+	previous, existed := records[id]
+	d.undo = append(d.undo, func() {
+		if existed {
+			records[id] = previous
+		} else {
+			delete(records, id)
+		}
+	})
+	records[id] = value
+}
+
+// remove deletes the record and journals it.
+func remove[V any](d *memoryData, records map[int]V, id int) {
+	previous, existed := records[id]
+	if !existed {
+		return
+	}
+	d.undo = append(d.undo, func() {
+		records[id] = previous
+	})
+	delete(records, id)
+}
+
+// nextID hands out IDs per table the way AUTOINCREMENT does, never reusing one.
+func (d *memoryData) nextID(table string) int {
+	previous := d.lastIDs[table]
+	d.undo = append(d.undo, func() {
+		d.lastIDs[table] = previous
+	})
+
+	d.lastIDs[table] = previous + 1
+	return d.lastIDs[table]
+}
+
+// reserveID keeps nextID from handing out an ID the caller chose itself, as
+// SQLite does for explicit IDs.
+func (d *memoryData) reserveID(table string, id int) {
+	previous := d.lastIDs[table]
+	if id <= previous {
+		return
+	}
+
+	d.undo = append(d.undo, func() {
+		d.lastIDs[table] = previous
+	})
+	d.lastIDs[table] = id
+}
+
+// sortedValues returns the records in ID order.
+func sortedValues[V any](records map[int]V) []V {
+	ids := make([]int, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	values := make([]V, len(ids))
+	for i, id := range ids {
+		values[i] = records[id]
+	}
+	return values
+}
+
+func (m *MemoryStore) CreateUser(data *models.User) (*models.User, error) {
+	err := m.update(func(d *memoryData) error {
+		for _, user := range d.users {
+			if user.Email == data.Email {
+				return ErrEmailTaken
+			}
+		}
+
+		data.ID = d.nextID("users")
+		put(d, d.users, data.ID, *data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (m *MemoryStore) UpdateUser(data *models.User) (*models.User, error) {
+	err := m.update(func(d *memoryData) error {
+		if _, ok := d.users[data.ID]; !ok {
+			return ErrUserNotFound
+		}
+
+		for _, user := range d.users {
+			if user.Email == data.Email && user.ID != data.ID {
+				return ErrEmailTaken
+			}
+		}
+
+		put(d, d.users, data.ID, *data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeactivateUser keeps the user so existing appointments still reference it,
+// but blocks new bookings.
+func (m *MemoryStore) DeactivateUser(id int) (*models.User, error) {
+	var user *models.User
+
+	err := m.update(func(d *memoryData) error {
+		var err error
+		if user, err = d.getUser(id); err != nil {
+			return err
+		}
+
+		user.Active = false
+		put(d, d.users, id, *user)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (m *MemoryStore) GetUser(id int) (*models.User, error) {
+	var user *models.User
+
+	err := m.read(func(d *memoryData) error {
+		var err error
+		user, err = d.getUser(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (d *memoryData) getUser(id int) (*models.User, error) {
+	user, ok := d.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (m *MemoryStore) GetUsers() ([]*models.User, error) {
+	users := make([]*models.User, 0)
+
+	m.read(func(d *memoryData) error {
+		for _, user := range sortedValues(d.users) {
+			users = append(users, &user)
+		}
+		return nil
+	})
+
+	return users, nil
+}
+
+func (m *MemoryStore) CreateTrainer(data *models.Trainer) (*models.Trainer, error) {
+	err := m.update(func(d *memoryData) error {
+		for _, trainer := range d.trainers {
+			if trainer.Email == data.Email {
+				return ErrEmailTaken
+			}
+		}
+
+		data.ID = d.nextID("trainers")
+		put(d, d.trainers, data.ID, *data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (m *MemoryStore) UpdateTrainer(data *models.Trainer) (*models.Trainer, error) {
+	err := m.update(func(d *memoryData) error {
+		if _, ok := d.trainers[data.ID]; !ok {
+			return ErrTrainerNotFound
+		}
+
+		for _, trainer := range d.trainers {
+			if trainer.Email == data.Email && trainer.ID != data.ID {
+				return ErrEmailTaken
+			}
+		}
+
+		put(d, d.trainers, data.ID, *data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeactivateTrainer keeps the trainer so existing appointments still reference it,
+// but blocks new bookings.
+func (m *MemoryStore) DeactivateTrainer(id int) (*models.Trainer, error) {
+	var trainer *models.Trainer
+
+	err := m.update(func(d *memoryData) error {
+		var err error
+		if trainer, err = d.getTrainer(id); err != nil {
+			return err
+		}
+
+		trainer.Active = false
+		put(d, d.trainers, id, *trainer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trainer, nil
+}
+
+func (m *MemoryStore) GetTrainer(id int) (*models.Trainer, error) {
+	var trainer *models.Trainer
+
+	err := m.read(func(d *memoryData) error {
+		var err error
+		trainer, err = d.getTrainer(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trainer, nil
+}
+
+func (d *memoryData) getTrainer(id int) (*models.Trainer, error) {
+	trainer, ok := d.trainers[id]
+	if !ok {
+		return nil, ErrTrainerNotFound
+	}
+	return &trainer, nil
+}
+
+func (m *MemoryStore) GetTrainers() ([]*models.Trainer, error) {
+	trainers := make([]*models.Trainer, 0)
+
+	m.read(func(d *memoryData) error {
+		for _, trainer := range sortedValues(d.trainers) {
+			trainers = append(trainers, &trainer)
+		}
+		return nil
+	})
+
+	return trainers, nil
+}
+
+func (m *MemoryStore) getActiveTrainerIDs() ([]int, error) {
+	ids := make([]int, 0)
+
+	m.read(func(d *memoryData) error {
+		for _, trainer := range sortedValues(d.trainers) {
+			if trainer.Active {
+				ids = append(ids, trainer.ID)
+			}
+		}
+		return nil
+	})
+
+	return ids, nil
+}
+
+// validateParticipants checks that both the user and the trainer exist and
+// are active.
+func (d *memoryData) validateParticipants(userID, trainerID int) error {
+	user, err := d.getUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.Active {
+		return ErrUserInactive
+	}
+
+	trainer, err := d.getTrainer(trainerID)
+	if err != nil {
+		return err
+	}
+
+	if !trainer.Active {
+		return ErrTrainerInactive
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) GetTrainerSettings(trainerID int) (*models.TrainerSettings, error) {
+	var settings *models.TrainerSettings
+
+	m.read(func(d *memoryData) error {
+		settings = d.getTrainerSettings(trainerID)
+		return nil
+	})
+
+	return settings, nil
+}
+
+func (d *memoryData) getTrainerSettings(trainerID int) *models.TrainerSettings {
+	settings, ok := d.settings[trainerID]
+	if !ok {
+		return models.DefaultTrainerSettings(trainerID)
+	}
+
+	if len(settings.Durations) == 0 {
+		settings.Durations = models.DefaultDurations
+	} else {
+		settings.Durations = slices.Clone(settings.Durations)
+	}
+
+	return &settings
+}
+
+func (m *MemoryStore) SetTrainerSettings(settings *models.TrainerSettings) (*models.TrainerSettings, error) {
+	settings.Capacity = settings.Seats()
+
+	m.update(func(d *memoryData) error {
+		stored := *settings
+		stored.Durations = slices.Clone(settings.Durations)
+		put(d, d.settings, settings.TrainerID, stored)
+		return nil
+	})
+
+	return settings, nil
+}
+
+func (m *MemoryStore) GetTrainerWorkingHours(trainerID int) (models.WeeklySchedule, error) {
+	var schedule models.WeeklySchedule
+
+	m.read(func(d *memoryData) error {
+		schedule = d.getTrainerWorkingHours(trainerID)
+		return nil
+	})
+
+	return schedule, nil
+}
+
+func (d *memoryData) getTrainerWorkingHours(trainerID int) models.WeeklySchedule {
+	schedule := d.workingHours[trainerID]
+	if len(schedule) == 0 {
+		return models.DefaultWeeklySchedule()
+	}
+	return slices.Clone(schedule)
+}
+
+func (m *MemoryStore) SetTrainerWorkingHours(trainerID int, schedule models.WeeklySchedule) (models.WeeklySchedule, error) {
+	stored := slices.Clone(schedule)
+	sort.SliceStable(stored, func(i, j int) bool {
+		if stored[i].Weekday != stored[j].Weekday {
+			return stored[i].Weekday < stored[j].Weekday
+		}
+		return stored[i].StartTime < stored[j].StartTime
+	})
+
+	m.update(func(d *memoryData) error {
+		put(d, d.workingHours, trainerID, stored)
+		return nil
+	})
+
+	return schedule, nil
+}
+
+func (m *MemoryStore) CreateTimeOff(data *models.TimeOff) (*models.TimeOff, error) {
+	m.update(func(d *memoryData) error {
+		data.ID = d.nextID("trainer_time_off")
+		put(d, d.timeOff, data.ID, *data)
+		return nil
+	})
+
+	return data, nil
+}
+
+func (m *MemoryStore) UpdateTimeOff(data *models.TimeOff) (*models.TimeOff, error) {
+	err := m.update(func(d *memoryData) error {
+		if entry, ok := d.timeOff[data.ID]; !ok || entry.TrainerID != data.TrainerID {
+			return ErrTimeOffNotFound
+		}
+
+		put(d, d.timeOff, data.ID, *data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (m *MemoryStore) DeleteTimeOff(trainerID, id int) error {
+	return m.update(func(d *memoryData) error {
+		if entry, ok := d.timeOff[id]; !ok || entry.TrainerID != trainerID {
+			return ErrTimeOffNotFound
+		}
+
+		remove(d, d.timeOff, id)
+		return nil
+	})
+}
+
+func (m *MemoryStore) GetTimeOffByTrainerID(trainerID int, startsAt, endsAt time.Time) ([]*models.TimeOff, error) {
+	var timeOff []*models.TimeOff
+
+	m.read(func(d *memoryData) error {
+		timeOff = d.getTimeOffByTrainerID(trainerID, startsAt, endsAt)
+		return nil
+	})
+
+	return timeOff, nil
+}
+
+func (d *memoryData) getTimeOffByTrainerID(trainerID int, startsAt, endsAt time.Time) []*models.TimeOff {
+	timeOff := make([]*models.TimeOff, 0)
+	loc := d.getTrainerSettings(trainerID).Location()
+
+	for _, entry := range sortedValues(d.timeOff) {
+		if entry.TrainerID != trainerID {
+			continue
+		}
+
+		if !startsAt.IsZero() && !endsAt.IsZero() && !entry.Timeslot().Overlaps(startsAt, endsAt) {
+			continue
+		}
+
+		timeOff = append(timeOff, entry.In(loc))
+	}
+
+	sort.SliceStable(timeOff, func(i, j int) bool {
+		return timeOff[i].StartsAt.Before(timeOff[j].StartsAt)
+	})
+
+	return timeOff
+}
+
+func (d *memoryData) validateTrainerTimeOff(data *models.Appointment) error {
+	if len(d.getTimeOffByTrainerID(data.TrainerID, data.StartsAt, data.EndsAt)) != 0 {
+		return ErrTrainerTimeOff
+	}
+	return nil
+}
+
+func (m *MemoryStore) CreateHoliday(data *models.Holiday) (*models.Holiday, error) {
+	m.update(func(d *memoryData) error {
+		d.createHoliday(data)
+		return nil
+	})
+
+	return data, nil
+}
+
+func (d *memoryData) createHoliday(data *models.Holiday) {
+	data.ID = d.nextID("holidays")
+	put(d, d.holidays, data.ID, *data)
+}
+
+func (m *MemoryStore) ImportHolidays(holidays []*models.Holiday) ([]*models.Holiday, error) {
+	m.update(func(d *memoryData) error {
+		for _, holiday := range holidays {
+			d.createHoliday(holiday)
+		}
+		return nil
+	})
+
+	return holidays, nil
+}
+
+func (m *MemoryStore) UpdateHoliday(data *models.Holiday) (*models.Holiday, error) {
+	err := m.update(func(d *memoryData) error {
+		if _, ok := d.holidays[data.ID]; !ok {
+			return ErrHolidayNotFound
+		}
+
+		put(d, d.holidays, data.ID, *data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (m *MemoryStore) DeleteHoliday(id int) error {
+	return m.update(func(d *memoryData) error {
+		if _, ok := d.holidays[id]; !ok {
+			return ErrHolidayNotFound
+		}
+
+		remove(d, d.holidays, id)
+		return nil
+	})
+}
+
+// GetHolidays returns holidays between the from and to dates (inclusive).
+// Empty dates leave the range open.
+func (m *MemoryStore) GetHolidays(from, to string) ([]*models.Holiday, error) {
+	var holidays []*models.Holiday
+
+	m.read(func(d *memoryData) error {
+		holidays = d.getHolidays(from, to)
+		return nil
+	})
+
+	return holidays, nil
+}
+
+func (d *memoryData) getHolidays(from, to string) []*models.Holiday {
+	holidays := make([]*models.Holiday, 0)
+
+	for _, holiday := range sortedValues(d.holidays) {
+		if (from != "" && holiday.Date < from) || (to != "" && holiday.Date > to) {
+			continue
+		}
+
+		holidays = append(holidays, &holiday)
+	}
+
+	sort.SliceStable(holidays, func(i, j int) bool {
+		if holidays[i].Date != holidays[j].Date {
+			return holidays[i].Date < holidays[j].Date
+		}
+		return holidays[i].StartTime < holidays[j].StartTime
+	})
+
+	return holidays
+}
+
+func (d *memoryData) validateHolidays(data *models.Appointment) error {
+	loc := d.getTrainerSettings(data.TrainerID).Location()
+
+	holidays := d.getHolidays(
+		data.StartsAt.In(loc).Format(models.HolidayDateFormat),
+		data.EndsAt.In(loc).Format(models.HolidayDateFormat),
+	)
+
+	for _, holiday := range holidays {
+		if holiday.On(loc).Overlaps(data.StartsAt, data.EndsAt) {
+			return ErrHoliday
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) CreateResource(data *models.Resource) (*models.Resource, error) {
+	m.update(func(d *memoryData) error {
+		data.ID = d.nextID("resources")
+		put(d, d.resources, data.ID, *data)
+		return nil
+	})
+
+	return data, nil
+}
+
+func (m *MemoryStore) UpdateResource(data *models.Resource) (*models.Resource, error) {
+	err := m.update(func(d *memoryData) error {
+		if _, ok := d.resources[data.ID]; !ok {
+			return ErrResourceNotFound
+		}
+
+		put(d, d.resources, data.ID, *data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeactivateResource keeps the resource so existing appointments still
+// reference it, but blocks new bookings that require it.
+func (m *MemoryStore) DeactivateResource(id int) (*models.Resource, error) {
+	var resource *models.Resource
+
+	err := m.update(func(d *memoryData) error {
+		var err error
+		if resource, err = d.getResource(id); err != nil {
+			return err
+		}
+
+		resource.Active = false
+		put(d, d.resources, id, *resource)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (m *MemoryStore) GetResource(id int) (*models.Resource, error) {
+	var resource *models.Resource
+
+	err := m.read(func(d *memoryData) error {
+		var err error
+		resource, err = d.getResource(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (d *memoryData) getResource(id int) (*models.Resource, error) {
+	resource, ok := d.resources[id]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+	return &resource, nil
+}
+
+func (m *MemoryStore) GetResources() ([]*models.Resource, error) {
+	resources := make([]*models.Resource, 0)
+
+	m.read(func(d *memoryData) error {
+		for _, resource := range sortedValues(d.resources) {
+			resources = append(resources, &resource)
+		}
+		return nil
+	})
+
+	return resources, nil
+}
+
+// validateResources checks that every resource the appointment requires exists,
+// is active and is not held by another overlapping appointment. Attendees of
+// the same group session share its resources.
+func (d *memoryData) validateResources(appointment *models.Appointment, resourceIDs []int) error {
+	if len(resourceIDs) == 0 {
+		return nil
+	}
+
+	for _, resourceID := range resourceIDs {
+		resource, err := d.getResource(resourceID)
+		if err != nil {
+			return err
+		}
+
+		if !resource.Active {
+			return ErrResourceInactive
+		}
+	}
+
+	for _, other := range d.appointments {
+		if other.ID == appointment.ID || other.Status != models.AppointmentStatusScheduled {
+			continue
+		}
+
+		if !other.Timeslot().Overlaps(appointment.StartsAt, appointment.EndsAt) {
+			continue
+		}
+
+		if other.TrainerID == appointment.TrainerID && sameTimeslot(other.Timeslot(), appointment.Timeslot()) {
+			continue
+		}
+
+		for _, resourceID := range resourceIDs {
+			if slices.Contains(other.ResourceIDs, resourceID) {
+				return ErrResourceUnavailable
+			}
+		}
+	}
+
+	return nil
+}
+
+func sameTimeslot(a, b models.Timeslot) bool {
+	return a.StartsAt.Equal(b.StartsAt) && a.EndsAt.Equal(b.EndsAt)
+}
@@ -0,0 +1,1203 @@
+package store
+
+import (
+	"errors"
+	"future-app/models"
+	"slices"
+	"sort"
+	"time"
+)
+
+func (m *MemoryStore) CreateAppointment(data *models.Appointment) (*models.Appointment, error) {
+	var appointment *models.Appointment
+
+	err := m.update(func(d *memoryData) error {
+		var err error
+		appointment, err = d.bookAppointment(m.audit, data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+// ImportAppointments inserts the appointments as they are, without the
+// booking rules. The users and trainers must exist and the appointments keep
+// their IDs, failing with ErrAppointmentExists if one is taken.
+func (m *MemoryStore) ImportAppointments(appointments []*models.Appointment) ([]*models.Appointment, error) {
+	err := m.update(func(d *memoryData) error {
+		for _, appointment := range appointments {
+			if _, err := d.getUser(appointment.UserID); err != nil {
+				return err
+			}
+
+			if _, err := d.getTrainer(appointment.TrainerID); err != nil {
+				return err
+			}
+
+			if appointment.Status == "" {
+				appointment.Status = models.AppointmentStatusScheduled
+			}
+			appointment.SeriesID = nil
+			appointment.ResourceIDs = nil
+
+			if appointment.ID == 0 {
+				appointment.ID = d.nextID("appointments")
+			} else if _, ok := d.appointments[appointment.ID]; ok {
+				return ErrAppointmentExists
+			} else {
+				d.reserveID("appointments", appointment.ID)
+			}
+
+			d.putAppointment(appointment)
+			d.recordAppointmentChange(m.audit, nil, appointment.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// bookAppointment creates the appointment if the trainer and every resource it
+// requires are available.
+func (d *memoryData) bookAppointment(audit models.Audit, data *models.Appointment) (*models.Appointment, error) {
+	if err := d.validateParticipants(data.UserID, data.TrainerID); err != nil {
+		return nil, err
+	}
+
+	if err := d.validateTrainerTimeOff(data); err != nil {
+		return nil, err
+	}
+
+	if err := d.validateHolidays(data); err != nil {
+		return nil, err
+	}
+
+	if err := d.validateResources(data, data.ResourceIDs); err != nil {
+		return nil, err
+	}
+
+	if data.Status == "" {
+		data.Status = models.AppointmentStatusScheduled
+	}
+
+	if data.Status == models.AppointmentStatusScheduled && d.isTimeslotTaken(data) {
+		return nil, ErrTimeslotUnavailable
+	}
+
+	data.ID = d.nextID("appointments")
+	d.putAppointment(data)
+	d.recordAppointmentChange(audit, nil, data.ID)
+
+	return data, nil
+}
+
+// putAppointment stores a copy of the appointment with its resources in ID
+// order, as they are read back from SQLite.
+func (d *memoryData) putAppointment(appointment *models.Appointment) {
+	stored := *appointment
+	stored.ResourceIDs = nil
+	if len(appointment.ResourceIDs) != 0 {
+		stored.ResourceIDs = slices.Clone(appointment.ResourceIDs)
+		slices.Sort(stored.ResourceIDs)
+	}
+	put(d, d.appointments, stored.ID, stored)
+}
+
+// isTimeslotTaken reports whether the user already has an overlapping
+// scheduled appointment or the trainer has no seat open, ignoring the
+// appointment itself. Group sessions only share a timeslot when it matches
+// exactly, and the trainer's other sessions must leave room for their buffers.
+// Seats held by another user's open waitlist offer count as taken.
+func (d *memoryData) isTimeslotTaken(appointment *models.Appointment) bool {
+	settings := d.getTrainerSettings(appointment.TrainerID)
+	timeslot := appointment.Timeslot()
+	buffered := bufferedTimeslot(timeslot, settings)
+	taken := 0
+
+	for _, other := range d.appointments {
+		if other.ID == appointment.ID || other.Status != models.AppointmentStatusScheduled {
+			continue
+		}
+
+		if other.UserID == appointment.UserID && other.Timeslot().Overlaps(timeslot.StartsAt, timeslot.EndsAt) {
+			return true
+		}
+
+		if other.TrainerID != appointment.TrainerID || !other.Timeslot().Overlaps(buffered.StartsAt, buffered.EndsAt) {
+			continue
+		}
+
+		if !sameTimeslot(other.Timeslot(), timeslot) {
+			return true
+		}
+
+		taken++
+	}
+
+	cutoff := time.Now().Truncate(time.Second)
+	for _, entry := range d.waitlist {
+		if entry.TrainerID != appointment.TrainerID || entry.UserID == appointment.UserID ||
+			entry.Status != models.WaitlistStatusOffered || !entry.OfferExpiresAt.After(cutoff) {
+			continue
+		}
+
+		held := models.NewTimeslot(entry.StartsAt, entry.EndsAt)
+		if !held.Overlaps(buffered.StartsAt, buffered.EndsAt) {
+			continue
+		}
+
+		if !sameTimeslot(held, timeslot) {
+			return true
+		}
+
+		taken++
+	}
+
+	return taken >= settings.Seats()
+}
+
+func (m *MemoryStore) GetAppointmentByID(id int) (*models.Appointment, error) {
+	var appointment *models.Appointment
+
+	err := m.read(func(d *memoryData) error {
+		var err error
+		appointment, err = d.getAppointmentByID(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+func (d *memoryData) getAppointmentByID(id int) (*models.Appointment, error) {
+	appointment, ok := d.appointments[id]
+	if !ok {
+		return nil, ErrAppointmentNotFound
+	}
+
+	appointment.ResourceIDs = slices.Clone(appointment.ResourceIDs)
+	return appointment.In(d.getTrainerSettings(appointment.TrainerID).Location()), nil
+}
+
+// GetAppointmentDetails returns the appointment with a summary of its user and
+// trainer, in the trainer's time zone.
+func (m *MemoryStore) GetAppointmentDetails(id int) (*models.AppointmentDetails, error) {
+	var details *models.AppointmentDetails
+
+	err := m.read(func(d *memoryData) error {
+		appointment, err := d.getAppointmentByID(id)
+		if err != nil {
+			return err
+		}
+
+		user, userOK := d.users[appointment.UserID]
+		trainer, trainerOK := d.trainers[appointment.TrainerID]
+		if !userOK || !trainerOK {
+			return ErrAppointmentNotFound
+		}
+
+		details = &models.AppointmentDetails{
+			Appointment: *appointment,
+			User:        models.ProfileSummary{ID: user.ID, Name: user.Name, Active: user.Active},
+			Trainer:     models.ProfileSummary{ID: trainer.ID, Name: trainer.Name, Active: trainer.Active},
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// GetAppointmentsByTrainerID returns a page of the trainer's scheduled
+// appointments along with the cursor for the next page. A zero Page returns
+// every appointment.
+func (m *MemoryStore) GetAppointmentsByTrainerID(trainerID int, startsAt, endsAt time.Time, page models.Page) ([]*models.Appointment, *models.Cursor, error) {
+	var appointments []*models.Appointment
+	var next *models.Cursor
+
+	m.read(func(d *memoryData) error {
+		appointments, next = d.listAppointments(func(appointment models.Appointment) bool {
+			return appointment.TrainerID == trainerID &&
+				appointment.Status == models.AppointmentStatusScheduled &&
+				withinWindow(appointment, startsAt, endsAt)
+		}, page, false, false)
+		return nil
+	})
+
+	return appointments, next, nil
+}
+
+// GetAppointmentsByUserID returns a page of the user's appointments, optionally
+// limited to those overlapping the window, along with the cursor for the next
+// page. Each appointment is returned in its trainer's time zone.
+func (m *MemoryStore) GetAppointmentsByUserID(userID int, startsAt, endsAt time.Time, filter string, page models.Page) ([]*models.Appointment, *models.Cursor, error) {
+	var appointments []*models.Appointment
+	var next *models.Cursor
+
+	now := time.Now()
+	status := models.AppointmentStatusScheduled
+	if filter == AppointmentFilterCancelled {
+		status = models.AppointmentStatusCancelled
+	}
+
+	m.read(func(d *memoryData) error {
+		appointments, next = d.listAppointments(func(appointment models.Appointment) bool {
+			if appointment.UserID != userID || appointment.Status != status || !withinWindow(appointment, startsAt, endsAt) {
+				return false
+			}
+
+			switch filter {
+			case AppointmentFilterUpcoming:
+				return appointment.EndsAt.After(now)
+			case AppointmentFilterPast:
+				return !appointment.EndsAt.After(now)
+			}
+			return true
+		}, page, filter == AppointmentFilterPast, false)
+		return nil
+	})
+
+	return appointments, next, nil
+}
+
+// GetAppointmentsByResourceID returns a page of the scheduled appointments
+// that require the resource, along with the cursor for the next page. Each
+// appointment is returned in its trainer's time zone.
+func (m *MemoryStore) GetAppointmentsByResourceID(resourceID int, startsAt, endsAt time.Time, page models.Page) ([]*models.Appointment, *models.Cursor, error) {
+	var appointments []*models.Appointment
+	var next *models.Cursor
+
+	m.read(func(d *memoryData) error {
+		appointments, next = d.listAppointments(func(appointment models.Appointment) bool {
+			return slices.Contains(appointment.ResourceIDs, resourceID) &&
+				appointment.Status == models.AppointmentStatusScheduled &&
+				withinWindow(appointment, startsAt, endsAt)
+		}, page, false, true)
+		return nil
+	})
+
+	return appointments, next, nil
+}
+
+// ExportAppointments returns every appointment, cancelled ones included,
+// ordered by ID. ImportAppointments keeps the IDs, so an export can only be
+// imported into a database that holds none of them. A zero trainerID or userID
+// matches everyone and a zero bound leaves that end of the range open. Each
+// appointment is returned in its trainer's time zone.
+func (m *MemoryStore) ExportAppointments(trainerID, userID int, startsAt, endsAt time.Time) ([]*models.Appointment, error) {
+	var appointments []*models.Appointment
+
+	m.read(func(d *memoryData) error {
+		appointments, _ = d.listAppointments(func(appointment models.Appointment) bool {
+			return (trainerID == 0 || appointment.TrainerID == trainerID) &&
+				(userID == 0 || appointment.UserID == userID) &&
+				(startsAt.IsZero() || !appointment.EndsAt.Before(startsAt)) &&
+				(endsAt.IsZero() || !appointment.StartsAt.After(endsAt))
+		}, models.Page{}, false, false)
+		return nil
+	})
+
+	sort.Slice(appointments, func(i, j int) bool {
+		return appointments[i].ID < appointments[j].ID
+	})
+
+	return appointments, nil
+}
+
+// withinWindow reports whether the appointment touches the window. A zero
+// window matches every appointment.
+func withinWindow(appointment models.Appointment, startsAt, endsAt time.Time) bool {
+	if startsAt.IsZero() || endsAt.IsZero() {
+		return true
+	}
+	return !appointment.EndsAt.Before(startsAt) && !appointment.StartsAt.After(endsAt)
+}
+
+// listAppointments returns a page of the matching appointments ordered by
+// (starts_at, id) like appendPage, each in its trainer's time zone. Resources
+// are only included when asked for, matching the SQLite listings.
+func (d *memoryData) listAppointments(match func(appointment models.Appointment) bool, page models.Page, desc, withResources bool) ([]*models.Appointment, *models.Cursor) {
+	appointments := make([]*models.Appointment, 0)
+	for _, appointment := range d.appointments {
+		if !match(appointment) {
+			continue
+		}
+
+		if withResources {
+			appointment.ResourceIDs = slices.Clone(appointment.ResourceIDs)
+		} else {
+			appointment.ResourceIDs = nil
+		}
+
+		appointments = append(appointments, &appointment)
+	}
+
+	sort.Slice(appointments, func(i, j int) bool {
+		a, b := appointments[i], appointments[j]
+		if desc {
+			a, b = b, a
+		}
+		return appointmentBefore(a, b.StartsAt, b.ID)
+	})
+
+	if page.Cursor != nil {
+		after := make([]*models.Appointment, 0, len(appointments))
+		for _, appointment := range appointments {
+			isBefore := appointmentBefore(appointment, page.Cursor.StartsAt, page.Cursor.ID)
+			isCursor := appointment.StartsAt.Equal(page.Cursor.StartsAt) && appointment.ID == page.Cursor.ID
+			if !isCursor && isBefore == desc {
+				after = append(after, appointment)
+			}
+		}
+		appointments = after
+	}
+
+	appointments, next := nextPage(appointments, page)
+
+	for i, appointment := range appointments {
+		appointments[i] = appointment.In(d.getTrainerSettings(appointment.TrainerID).Location())
+	}
+
+	return appointments, next
+}
+
+func appointmentBefore(appointment *models.Appointment, startsAt time.Time, id int) bool {
+	if !appointment.StartsAt.Equal(startsAt) {
+		return appointment.StartsAt.Before(startsAt)
+	}
+	return appointment.ID < id
+}
+
+func (m *MemoryStore) CancelAppointment(id int) (*models.Appointment, error) {
+	var appointment *models.Appointment
+
+	err := m.update(func(d *memoryData) error {
+		var err error
+		if appointment, err = d.getAppointmentByID(id); err != nil {
+			return err
+		}
+
+		if appointment.Status == models.AppointmentStatusCancelled {
+			return ErrAppointmentCancelled
+		}
+
+		d.setAppointmentStatus(id, models.AppointmentStatusCancelled)
+		d.recordAppointmentChange(m.audit, appointment, id)
+		appointment.Status = models.AppointmentStatusCancelled
+
+		return d.releaseTimeslot(m.audit, appointment.TrainerID, appointment.StartsAt, appointment.EndsAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+func (d *memoryData) setAppointmentStatus(id int, status string) {
+	appointment := d.appointments[id]
+	appointment.Status = status
+	put(d, d.appointments, id, appointment)
+}
+
+func (m *MemoryStore) RescheduleAppointment(id int, startsAt, endsAt time.Time) (*models.Appointment, error) {
+	var appointment *models.Appointment
+
+	err := m.update(func(d *memoryData) error {
+		existing, err := d.getAppointmentByID(id)
+		if err != nil {
+			return err
+		}
+
+		if existing.Status == models.AppointmentStatusCancelled {
+			return ErrAppointmentCancelled
+		}
+
+		settings := d.getTrainerSettings(existing.TrainerID)
+		schedule := d.getTrainerWorkingHours(existing.TrainerID)
+
+		appointment, err = models.NewAppointment(existing.UserID, existing.TrainerID, startsAt, endsAt, settings, schedule)
+		if err != nil {
+			return err
+		}
+		appointment.ID = existing.ID
+		appointment.SeriesID = existing.SeriesID
+		appointment.ResourceIDs = existing.ResourceIDs
+
+		if err := d.rescheduleAppointment(appointment); err != nil {
+			return err
+		}
+
+		d.recordAppointmentChange(m.audit, existing, existing.ID)
+
+		return d.releaseTimeslot(m.audit, existing.TrainerID, existing.StartsAt, existing.EndsAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+// rescheduleAppointment moves an existing appointment to the appointment's
+// timeslot if the trainer has a seat open with room for their buffers, its
+// resources are free and the user is not double booked.
+func (d *memoryData) rescheduleAppointment(appointment *models.Appointment) error {
+	if err := d.validateParticipants(appointment.UserID, appointment.TrainerID); err != nil {
+		return err
+	}
+
+	if err := d.validateTrainerTimeOff(appointment); err != nil {
+		return err
+	}
+
+	if err := d.validateHolidays(appointment); err != nil {
+		return err
+	}
+
+	stored, ok := d.appointments[appointment.ID]
+	if !ok {
+		return ErrTimeslotUnavailable
+	}
+
+	if err := d.validateResources(appointment, stored.ResourceIDs); err != nil {
+		return err
+	}
+
+	if d.isTimeslotTaken(appointment) {
+		return ErrTimeslotUnavailable
+	}
+
+	stored.StartsAt = appointment.StartsAt
+	stored.EndsAt = appointment.EndsAt
+	put(d, d.appointments, stored.ID, stored)
+
+	return nil
+}
+
+// CreateAppointmentSeries books every occurrence of the recurrence that passes
+// validation. Occurrences that are invalid or already taken are reported as
+// conflicts instead of failing the whole series. If no occurrence can be
+// booked the series is not created and ErrTimeslotUnavailable is returned.
+func (m *MemoryStore) CreateAppointmentSeries(userID, trainerID int, startsAt, endsAt time.Time, recurrence *models.Recurrence) (*models.SeriesResult, error) {
+	var result *models.SeriesResult
+
+	err := m.update(func(d *memoryData) error {
+		settings := d.getTrainerSettings(trainerID)
+		schedule := d.getTrainerWorkingHours(trainerID)
+		loc := settings.Location()
+
+		occurrences, err := recurrence.Occurrences(startsAt.In(loc), endsAt.In(loc))
+		if err != nil {
+			return err
+		}
+
+		series := &models.AppointmentSeries{
+			ID:        d.nextID("appointment_series"),
+			UserID:    userID,
+			TrainerID: trainerID,
+			RRule:     recurrence.String(),
+			StartsAt:  occurrences[0].StartsAt,
+			EndsAt:    occurrences[0].EndsAt,
+		}
+		put(d, d.series, series.ID, *series)
+
+		result = &models.SeriesResult{
+			Series:    series,
+			Booked:    make([]*models.Appointment, 0, len(occurrences)),
+			Conflicts: make([]models.SeriesConflict, 0),
+		}
+
+		for _, occurrence := range occurrences {
+			appointment, err := models.NewAppointment(userID, trainerID, occurrence.StartsAt, occurrence.EndsAt, settings, schedule)
+			if err == nil {
+				appointment.SeriesID = &series.ID
+				_, err = d.bookAppointment(m.audit, appointment)
+
+				if err != nil && !isTimeslotConflict(err) {
+					return err
+				}
+			}
+
+			if err != nil {
+				result.Conflicts = append(result.Conflicts, models.SeriesConflict{
+					StartsAt: occurrence.StartsAt,
+					EndsAt:   occurrence.EndsAt,
+					Reason:   err.Error(),
+				})
+				continue
+			}
+
+			result.Booked = append(result.Booked, appointment)
+		}
+
+		if len(result.Booked) == 0 {
+			return ErrTimeslotUnavailable
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getFollowingAppointments returns the scheduled occurrences of a series that
+// start at or after the given time.
+func (d *memoryData) getFollowingAppointments(seriesID int, startsAt time.Time) []*models.Appointment {
+	appointments := make([]*models.Appointment, 0)
+
+	for _, appointment := range sortedValues(d.appointments) {
+		if appointment.SeriesID == nil || *appointment.SeriesID != seriesID {
+			continue
+		}
+
+		if appointment.Status != models.AppointmentStatusScheduled || appointment.StartsAt.Before(startsAt) {
+			continue
+		}
+
+		appointment.ResourceIDs = nil
+		appointments = append(appointments, &appointment)
+	}
+
+	sort.SliceStable(appointments, func(i, j int) bool {
+		return appointments[i].StartsAt.Before(appointments[j].StartsAt)
+	})
+
+	return appointments
+}
+
+func (d *memoryData) getSeriesAppointment(id int) (*models.Appointment, error) {
+	appointment, err := d.getAppointmentByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.Status == models.AppointmentStatusCancelled {
+		return nil, ErrAppointmentCancelled
+	}
+
+	if appointment.SeriesID == nil {
+		return nil, ErrAppointmentNotInSeries
+	}
+
+	return appointment, nil
+}
+
+// CancelFollowingAppointments cancels the appointment and every later
+// scheduled occurrence in its series.
+func (m *MemoryStore) CancelFollowingAppointments(id int) ([]*models.Appointment, error) {
+	var appointments []*models.Appointment
+
+	err := m.update(func(d *memoryData) error {
+		existing, err := d.getSeriesAppointment(id)
+		if err != nil {
+			return err
+		}
+
+		appointments = d.getFollowingAppointments(*existing.SeriesID, existing.StartsAt)
+
+		before := make([]*models.Appointment, len(appointments))
+		for i, appointment := range appointments {
+			before[i], _ = d.getAppointmentByID(appointment.ID)
+			d.setAppointmentStatus(appointment.ID, models.AppointmentStatusCancelled)
+		}
+
+		for i, appointment := range appointments {
+			d.recordAppointmentChange(m.audit, before[i], appointment.ID)
+
+			if err := d.releaseTimeslot(m.audit, appointment.TrainerID, appointment.StartsAt, appointment.EndsAt); err != nil {
+				return err
+			}
+		}
+
+		loc := existing.StartsAt.Location()
+		for i, appointment := range appointments {
+			appointment.Status = models.AppointmentStatusCancelled
+			appointments[i] = appointment.In(loc)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// RescheduleFollowingAppointments moves the appointment and every later
+// scheduled occurrence in its series by the same number of days, to the new
+// time of day and length. Occurrences that cannot be moved keep their current
+// timeslot and are reported as conflicts.
+func (m *MemoryStore) RescheduleFollowingAppointments(id int, startsAt, endsAt time.Time) (*models.SeriesResult, error) {
+	var result *models.SeriesResult
+
+	err := m.update(func(d *memoryData) error {
+		existing, err := d.getSeriesAppointment(id)
+		if err != nil {
+			return err
+		}
+
+		settings := d.getTrainerSettings(existing.TrainerID)
+		schedule := d.getTrainerWorkingHours(existing.TrainerID)
+		appointments := d.getFollowingAppointments(*existing.SeriesID, existing.StartsAt)
+
+		loc := settings.Location()
+		startsAt := startsAt.In(loc)
+		duration := endsAt.Sub(startsAt)
+		days := daysBetween(existing.StartsAt.In(loc), startsAt)
+
+		// INFO: Move the occurrences furthest in the shift direction first so the
+		// series never conflicts with its own occurrences that have yet to move
+		if startsAt.After(existing.StartsAt) {
+			sort.SliceStable(appointments, func(i, j int) bool {
+				return appointments[i].StartsAt.After(appointments[j].StartsAt)
+			})
+		}
+
+		result = &models.SeriesResult{
+			Booked:    make([]*models.Appointment, 0, len(appointments)),
+			Conflicts: make([]models.SeriesConflict, 0),
+		}
+		released := make([]models.Timeslot, 0, len(appointments))
+
+		for _, current := range appointments {
+			date := current.StartsAt.In(loc)
+			occurrenceStartsAt := time.Date(date.Year(), date.Month(), date.Day()+days, startsAt.Hour(), startsAt.Minute(), 0, 0, loc)
+			occurrenceEndsAt := occurrenceStartsAt.Add(duration)
+
+			before, err := d.getAppointmentByID(current.ID)
+			if err != nil {
+				return err
+			}
+
+			appointment, err := models.NewAppointment(current.UserID, current.TrainerID, occurrenceStartsAt, occurrenceEndsAt, settings, schedule)
+			if err == nil {
+				appointment.ID = current.ID
+				appointment.SeriesID = current.SeriesID
+				err = d.rescheduleAppointment(appointment)
+
+				if err != nil && !isTimeslotConflict(err) {
+					return err
+				}
+			}
+
+			if err == nil {
+				d.recordAppointmentChange(m.audit, before, current.ID)
+				released = append(released, current.Timeslot())
+			}
+
+			if err != nil {
+				result.Conflicts = append(result.Conflicts, models.SeriesConflict{
+					StartsAt: occurrenceStartsAt,
+					EndsAt:   occurrenceEndsAt,
+					Reason:   err.Error(),
+				})
+				continue
+			}
+
+			result.Booked = append(result.Booked, appointment)
+		}
+
+		// INFO: Freed slots are released after every occurrence has moved so the
+		// waitlist cannot claim a slot the series is about to move into
+		for _, timeslot := range released {
+			if err := d.releaseTimeslot(m.audit, existing.TrainerID, timeslot.StartsAt, timeslot.EndsAt); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result.Booked, func(i, j int) bool {
+		return result.Booked[i].StartsAt.Before(result.Booked[j].StartsAt)
+	})
+	sort.SliceStable(result.Conflicts, func(i, j int) bool {
+		return result.Conflicts[i].StartsAt.Before(result.Conflicts[j].StartsAt)
+	})
+
+	return result, nil
+}
+
+// CreateWaitlistEntry adds the user to the back of the queue for the slot. If
+// the slot is already free the entry is offered or booked straight away.
+func (m *MemoryStore) CreateWaitlistEntry(data *models.WaitlistEntry) (*models.WaitlistEntry, error) {
+	var entry *models.WaitlistEntry
+
+	err := m.update(func(d *memoryData) error {
+		for _, other := range d.waitlist {
+			if other.UserID == data.UserID && other.TrainerID == data.TrainerID &&
+				sameTimeslot(models.NewTimeslot(other.StartsAt, other.EndsAt), models.NewTimeslot(data.StartsAt, data.EndsAt)) &&
+				isWaitlistOpen(other.Status) {
+				return ErrAlreadyWaitlisted
+			}
+		}
+
+		now := time.Now()
+
+		if err := d.expireWaitlistOffers(m.audit, now); err != nil {
+			return err
+		}
+
+		id := d.nextID("waitlist")
+		put(d, d.waitlist, id, models.WaitlistEntry{
+			ID:        id,
+			UserID:    data.UserID,
+			TrainerID: data.TrainerID,
+			StartsAt:  data.StartsAt,
+			EndsAt:    data.EndsAt,
+			AutoBook:  data.AutoBook,
+			Status:    models.WaitlistStatusWaiting,
+			CreatedAt: now.UTC().Truncate(time.Second),
+		})
+
+		if err := d.offerTimeslot(m.audit, data.TrainerID, data.StartsAt, data.EndsAt, now); err != nil {
+			return err
+		}
+
+		var err error
+		entry, err = d.getWaitlistEntry(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func isWaitlistOpen(status string) bool {
+	return status == models.WaitlistStatusWaiting || status == models.WaitlistStatusOffered
+}
+
+func (m *MemoryStore) GetWaitlistEntry(id int) (*models.WaitlistEntry, error) {
+	var entry *models.WaitlistEntry
+
+	err := m.read(func(d *memoryData) error {
+		var err error
+		entry, err = d.getWaitlistEntry(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (d *memoryData) getWaitlistEntry(id int) (*models.WaitlistEntry, error) {
+	entry, ok := d.waitlist[id]
+	if !ok {
+		return nil, ErrWaitlistEntryNotFound
+	}
+
+	return entry.In(d.getTrainerSettings(entry.TrainerID).Location()), nil
+}
+
+// GetWaitlistByTrainerID returns the trainer's waiting and offered entries in
+// queue order.
+func (m *MemoryStore) GetWaitlistByTrainerID(trainerID int) ([]*models.WaitlistEntry, error) {
+	entries := make([]*models.WaitlistEntry, 0)
+
+	err := m.read(func(d *memoryData) error {
+		loc := d.getTrainerSettings(trainerID).Location()
+
+		for _, entry := range sortedValues(d.waitlist) {
+			if entry.TrainerID == trainerID && isWaitlistOpen(entry.Status) {
+				entries = append(entries, entry.In(loc))
+			}
+		}
+
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].StartsAt.Before(entries[j].StartsAt)
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ExpireWaitlistOffers moves lapsed offers on to the next users in the queue.
+func (m *MemoryStore) ExpireWaitlistOffers() error {
+	return m.update(func(d *memoryData) error {
+		return d.expireWaitlistOffers(m.audit, time.Now())
+	})
+}
+
+// AcceptWaitlistOffer books the offered slot for the waitlisted user.
+func (m *MemoryStore) AcceptWaitlistOffer(id int) (*models.Appointment, error) {
+	var appointment *models.Appointment
+	expired := false
+
+	err := m.update(func(d *memoryData) error {
+		now := time.Now()
+
+		entry, err := d.getWaitlistEntry(id)
+		if err != nil {
+			return err
+		}
+
+		if entry.Status == models.WaitlistStatusOffered && !entry.OfferExpiresAt.After(now) {
+			// INFO: The lapsed offer moves on to the next user even though the accept fails
+			expired = true
+			return d.expireWaitlistOffers(m.audit, now)
+		}
+
+		if entry.Status != models.WaitlistStatusOffered {
+			return ErrWaitlistOfferUnavailable
+		}
+
+		if appointment, err = d.bookAppointment(m.audit, entry.Appointment()); err != nil {
+			return err
+		}
+
+		d.setWaitlistStatus(entry.ID, models.WaitlistStatusBooked, nil, &appointment.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if expired {
+		return nil, ErrWaitlistOfferExpired
+	}
+
+	return appointment, nil
+}
+
+// CancelWaitlistEntry removes the user from the queue. An open offer is passed
+// on to the next user.
+func (m *MemoryStore) CancelWaitlistEntry(id int) (*models.WaitlistEntry, error) {
+	var entry *models.WaitlistEntry
+
+	err := m.update(func(d *memoryData) error {
+		now := time.Now()
+
+		if err := d.expireWaitlistOffers(m.audit, now); err != nil {
+			return err
+		}
+
+		var err error
+		if entry, err = d.getWaitlistEntry(id); err != nil {
+			return err
+		}
+
+		if !isWaitlistOpen(entry.Status) {
+			return ErrWaitlistEntryNotFound
+		}
+
+		d.setWaitlistStatus(entry.ID, models.WaitlistStatusCancelled, nil, nil)
+
+		if entry.Status == models.WaitlistStatusOffered {
+			return d.offerTimeslot(m.audit, entry.TrainerID, entry.StartsAt, entry.EndsAt, now)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Status = models.WaitlistStatusCancelled
+	entry.OfferExpiresAt = nil
+	return entry, nil
+}
+
+// setWaitlistStatus keeps offer expiries to the second, as SQLite does.
+func (d *memoryData) setWaitlistStatus(id int, status string, offerExpiresAt *time.Time, appointmentID *int) {
+	entry := d.waitlist[id]
+	entry.Status = status
+	entry.OfferExpiresAt = nil
+	entry.AppointmentID = appointmentID
+
+	if offerExpiresAt != nil {
+		expiresAt := offerExpiresAt.UTC().Truncate(time.Second)
+		entry.OfferExpiresAt = &expiresAt
+	}
+
+	put(d, d.waitlist, id, entry)
+}
+
+// releaseTimeslot hands a timeslot freed by a cancellation or reschedule to
+// the trainer's waitlist.
+func (d *memoryData) releaseTimeslot(audit models.Audit, trainerID int, startsAt, endsAt time.Time) error {
+	now := time.Now()
+
+	if err := d.expireWaitlistOffers(audit, now); err != nil {
+		return err
+	}
+
+	return d.offerTimeslot(audit, trainerID, startsAt, endsAt, now)
+}
+
+// expireWaitlistOffers marks lapsed offers as expired and passes each freed
+// slot on to the next user in the queue.
+func (d *memoryData) expireWaitlistOffers(audit models.Audit, now time.Time) error {
+	cutoff := now.Truncate(time.Second)
+
+	expired := make([]models.WaitlistEntry, 0)
+	for _, entry := range sortedValues(d.waitlist) {
+		if entry.Status == models.WaitlistStatusOffered && !entry.OfferExpiresAt.After(cutoff) {
+			expired = append(expired, entry)
+		}
+	}
+
+	for _, entry := range expired {
+		d.setWaitlistStatus(entry.ID, models.WaitlistStatusExpired, entry.OfferExpiresAt, nil)
+
+		if err := d.offerTimeslot(audit, entry.TrainerID, entry.StartsAt, entry.EndsAt, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// offerTimeslot walks the trainer's queue for entries overlapping a freed
+// timeslot. The first entry whose slot is now bookable is booked outright if it
+// opted into auto booking, otherwise it is offered the slot until the offer
+// expires. Slots held by an open offer are skipped.
+func (d *memoryData) offerTimeslot(audit models.Audit, trainerID int, startsAt, endsAt time.Time, now time.Time) error {
+	waiting := make([]models.WaitlistEntry, 0)
+	for _, entry := range sortedValues(d.waitlist) {
+		if entry.TrainerID == trainerID && entry.Status == models.WaitlistStatusWaiting &&
+			models.NewTimeslot(entry.StartsAt, entry.EndsAt).Overlaps(startsAt, endsAt) {
+			waiting = append(waiting, entry)
+		}
+	}
+
+	for _, entry := range waiting {
+		if entry.StartsAt.Before(now.Add(time.Hour)) {
+			continue
+		}
+
+		if d.hasOpenOffer(&entry, now) {
+			continue
+		}
+
+		if err := d.validateParticipants(entry.UserID, entry.TrainerID); err != nil {
+			if isParticipantError(err) {
+				continue
+			}
+			return err
+		}
+
+		appointment := entry.Appointment()
+
+		if d.isTimeslotTaken(appointment) {
+			continue
+		}
+
+		if entry.AutoBook {
+			_, err := d.bookAppointment(audit, appointment)
+			if isTimeslotConflict(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			d.setWaitlistStatus(entry.ID, models.WaitlistStatusBooked, nil, &appointment.ID)
+			continue
+		}
+
+		if err := d.validateTrainerTimeOff(appointment); err != nil {
+			if errors.Is(err, ErrTrainerTimeOff) {
+				continue
+			}
+			return err
+		}
+
+		if err := d.validateHolidays(appointment); err != nil {
+			if errors.Is(err, ErrHoliday) {
+				continue
+			}
+			return err
+		}
+
+		offerExpiresAt := entry.OfferExpiry(now)
+		d.setWaitlistStatus(entry.ID, models.WaitlistStatusOffered, &offerExpiresAt, nil)
+	}
+
+	return nil
+}
+
+// hasOpenOffer reports whether another entry currently holds an offer that
+// overlaps the entry's slot with the same trainer.
+func (d *memoryData) hasOpenOffer(entry *models.WaitlistEntry, now time.Time) bool {
+	cutoff := now.Truncate(time.Second)
+
+	for _, other := range d.waitlist {
+		if other.TrainerID == entry.TrainerID && other.Status == models.WaitlistStatusOffered &&
+			models.NewTimeslot(other.StartsAt, other.EndsAt).Overlaps(entry.StartsAt, entry.EndsAt) &&
+			other.OfferExpiresAt.After(cutoff) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetTrainerAvailability returns the trainer's timeslots in the window that
+// still have a seat open, counting seats held by another user's open waitlist
+// offer as taken. With a user ID, timeslots that overlap the user's own
+// appointments are left out as well, and with resource IDs so are timeslots in
+// which another trainer holds any of the resources.
+func (m *MemoryStore) GetTrainerAvailability(trainerID int, startsAt, endsAt time.Time, duration time.Duration, userID int, resourceIDs []int) (*[]models.OpenTimeslot, error) {
+	return trainerAvailability(m, trainerID, startsAt, endsAt, duration, userID, resourceIDs)
+}
+
+// GetAvailability returns the timeslots in which at least one of the trainers
+// has a seat open. It follows the same rules as Store.GetAvailability.
+func (m *MemoryStore) GetAvailability(trainerIDs []int, startsAt, endsAt time.Time, duration time.Duration, userID int, resourceIDs []int, first int) ([]*models.AvailableTimeslot, error) {
+	return availability(m, trainerIDs, startsAt, endsAt, duration, userID, resourceIDs, first)
+}
+
+// getBusyTimeslots returns the scheduled appointments and the time off of every
+// trainer overlapping the window, each keyed by trainer ID.
+func (m *MemoryStore) getBusyTimeslots(trainerIDs []int, startsAt, endsAt time.Time) (map[int][]models.Timeslot, map[int][]models.Timeslot, error) {
+	booked := make(map[int][]models.Timeslot, len(trainerIDs))
+	busy := make(map[int][]models.Timeslot, len(trainerIDs))
+
+	m.read(func(d *memoryData) error {
+		for _, appointment := range d.appointments {
+			if appointment.Status == models.AppointmentStatusScheduled && slices.Contains(trainerIDs, appointment.TrainerID) &&
+				appointment.Timeslot().Overlaps(startsAt, endsAt) {
+				booked[appointment.TrainerID] = append(booked[appointment.TrainerID], appointment.Timeslot())
+			}
+		}
+
+		for _, entry := range d.timeOff {
+			if slices.Contains(trainerIDs, entry.TrainerID) && entry.Timeslot().Overlaps(startsAt, endsAt) {
+				busy[entry.TrainerID] = append(busy[entry.TrainerID], entry.Timeslot())
+			}
+		}
+
+		return nil
+	})
+
+	return booked, busy, nil
+}
+
+// getHeldTimeslots returns the slots of the open offers overlapping the window
+// that were made to anyone but the user, keyed by trainer ID.
+func (m *MemoryStore) getHeldTimeslots(trainerIDs []int, userID int, startsAt, endsAt time.Time) (map[int][]models.Timeslot, error) {
+	held := make(map[int][]models.Timeslot, len(trainerIDs))
+	cutoff := time.Now().Truncate(time.Second)
+
+	m.read(func(d *memoryData) error {
+		for _, entry := range d.waitlist {
+			if entry.Status != models.WaitlistStatusOffered || !entry.OfferExpiresAt.After(cutoff) ||
+				entry.UserID == userID || !slices.Contains(trainerIDs, entry.TrainerID) {
+				continue
+			}
+
+			timeslot := models.NewTimeslot(entry.StartsAt, entry.EndsAt)
+			if timeslot.Overlaps(startsAt, endsAt) {
+				held[entry.TrainerID] = append(held[entry.TrainerID], timeslot)
+			}
+		}
+		return nil
+	})
+
+	return held, nil
+}
+
+// getTrainerSettingsByIDs returns the settings of every trainer, keyed by
+// trainer ID.
+func (m *MemoryStore) getTrainerSettingsByIDs(trainerIDs []int) (map[int]*models.TrainerSettings, error) {
+	settingsByID := make(map[int]*models.TrainerSettings, len(trainerIDs))
+
+	m.read(func(d *memoryData) error {
+		for _, trainerID := range trainerIDs {
+			settingsByID[trainerID] = d.getTrainerSettings(trainerID)
+		}
+		return nil
+	})
+
+	return settingsByID, nil
+}
+
+// getTrainerWorkingHoursByIDs returns the weekly schedule of every trainer,
+// keyed by trainer ID.
+func (m *MemoryStore) getTrainerWorkingHoursByIDs(trainerIDs []int) (map[int]models.WeeklySchedule, error) {
+	schedules := make(map[int]models.WeeklySchedule, len(trainerIDs))
+
+	m.read(func(d *memoryData) error {
+		for _, trainerID := range trainerIDs {
+			schedules[trainerID] = d.getTrainerWorkingHours(trainerID)
+		}
+		return nil
+	})
+
+	return schedules, nil
+}
+
+// getUserBusyTimeslots returns the user's scheduled appointments overlapping
+// the window. A zero user ID returns none.
+func (m *MemoryStore) getUserBusyTimeslots(userID int, startsAt, endsAt time.Time) ([]models.Timeslot, error) {
+	busy := make([]models.Timeslot, 0)
+	if userID == 0 {
+		return busy, nil
+	}
+
+	m.read(func(d *memoryData) error {
+		for _, appointment := range d.appointments {
+			if appointment.UserID == userID && appointment.Status == models.AppointmentStatusScheduled &&
+				appointment.Timeslot().Overlaps(startsAt, endsAt) {
+				busy = append(busy, appointment.Timeslot())
+			}
+		}
+		return nil
+	})
+
+	return busy, nil
+}
+
+// getResourceBusyTimeslots returns the scheduled appointments overlapping the
+// window that require any of the resources, keyed by the trainer holding them.
+func (m *MemoryStore) getResourceBusyTimeslots(resourceIDs []int, startsAt, endsAt time.Time) (map[int][]models.Timeslot, error) {
+	busy := make(map[int][]models.Timeslot)
+	if len(resourceIDs) == 0 {
+		return busy, nil
+	}
+
+	m.read(func(d *memoryData) error {
+		for _, appointment := range d.appointments {
+			if appointment.Status != models.AppointmentStatusScheduled || !appointment.Timeslot().Overlaps(startsAt, endsAt) {
+				continue
+			}
+
+			for _, resourceID := range resourceIDs {
+				if slices.Contains(appointment.ResourceIDs, resourceID) {
+					busy[appointment.TrainerID] = append(busy[appointment.TrainerID], appointment.Timeslot())
+					break
+				}
+			}
+		}
+		return nil
+	})
+
+	return busy, nil
+}
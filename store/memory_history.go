@@ -0,0 +1,72 @@
+package store
+
+import (
+	"future-app/models"
+	"slices"
+	"time"
+)
+
+// recordAppointmentChange appends the appointment's current state to its
+// history, next to the state it had before the change. Snapshots are kept in
+// UTC at second precision, as SQLite stores them.
+func (d *memoryData) recordAppointmentChange(audit models.Audit, before *models.Appointment, id int) {
+	after, _ := d.getAppointmentByID(id)
+
+	actor := audit.Actor
+	if actor == "" {
+		actor = models.ActorSystem
+	}
+
+	change := models.AppointmentChange{
+		ID:            d.nextID("appointment_history"),
+		AppointmentID: id,
+		Action:        models.AppointmentAction(before, after),
+		Actor:         actor,
+		RequestID:     audit.RequestID,
+		ChangedAt:     time.Now().UTC().Truncate(time.Second),
+		Before:        appointmentSnapshot(before),
+		After:         appointmentSnapshot(after),
+	}
+	put(d, d.history, change.ID, change)
+}
+
+func appointmentSnapshot(appointment *models.Appointment) *models.Appointment {
+	if appointment == nil {
+		return nil
+	}
+
+	snapshot := appointment.In(time.UTC)
+	snapshot.ResourceIDs = slices.Clone(appointment.ResourceIDs)
+	return snapshot
+}
+
+// GetAppointmentHistory returns every recorded change to the appointment,
+// oldest first, in the trainer's time zone.
+func (m *MemoryStore) GetAppointmentHistory(id int) ([]*models.AppointmentChange, error) {
+	changes := make([]*models.AppointmentChange, 0)
+
+	err := m.read(func(d *memoryData) error {
+		appointment, err := d.getAppointmentByID(id)
+		if err != nil {
+			return err
+		}
+
+		loc := appointment.StartsAt.Location()
+		for _, change := range sortedValues(d.history) {
+			if change.AppointmentID != id {
+				continue
+			}
+
+			change.Before = appointmentSnapshot(change.Before)
+			change.After = appointmentSnapshot(change.After)
+			changes = append(changes, change.In(loc))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
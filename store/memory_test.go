@@ -0,0 +1,369 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"future-app/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMemoryStore() (*MemoryStore, error) {
+	db := NewMemoryStore()
+
+	if err := seedParticipants(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func TestMemoryStore(t *testing.T) {
+	store, err := setupMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	t.Run("Concurrent bookings for the same timeslot", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(userID int) {
+				defer wg.Done()
+				appointment := getTestAppointment()
+				appointment.UserID = userID
+				_, err := store.CreateAppointment(appointment)
+				errs <- err
+			}(i + 1)
+		}
+
+		wg.Wait()
+		close(errs)
+
+		succeeded := 0
+		for err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+
+	t.Run("Failed writes leave nothing behind", func(t *testing.T) {
+		recurrence, err := models.NewRecurrence(models.SeriesFrequencyWeekly, 3, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// INFO: The user is already booked with trainer 1, so the only occurrence conflicts
+		existing, _, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{})
+		assert.NoError(t, err)
+
+		_, err = store.CreateAppointmentSeries(existing[0].UserID, 2, existing[0].StartsAt, existing[0].EndsAt, &models.Recurrence{Interval: 1, Count: 1})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+
+		result, err := store.CreateAppointmentSeries(existing[0].UserID, 2, existing[0].StartsAt.AddDate(0, 0, 3), existing[0].EndsAt.AddDate(0, 0, 3), recurrence)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Series.ID)
+	})
+
+	t.Run("Failed updates are undone", func(t *testing.T) {
+		holiday, err := store.CreateHoliday(&models.Holiday{Date: "2030-08-01", Name: "Closed"})
+		assert.NoError(t, err)
+
+		err = store.update(func(d *memoryData) error {
+			d.createHoliday(&models.Holiday{Date: "2030-08-02", Name: "Added"})
+			put(d, d.holidays, holiday.ID, models.Holiday{ID: holiday.ID, Date: "2030-08-01", Name: "Renamed"})
+			remove(d, d.holidays, holiday.ID)
+			return ErrHolidayNotFound
+		})
+		assert.ErrorIs(t, err, ErrHolidayNotFound)
+
+		holidays, err := store.GetHolidays("2030-08-01", "2030-08-31")
+		assert.NoError(t, err)
+		assert.Equal(t, []*models.Holiday{holiday}, holidays)
+
+		// INFO: IDs handed out by the failed update are reused like a rolled back AUTOINCREMENT
+		next, err := store.CreateHoliday(&models.Holiday{Date: "2030-08-03", Name: "Next"})
+		assert.NoError(t, err)
+		assert.Equal(t, holiday.ID+1, next.ID)
+	})
+
+	t.Run("Returned records are copies", func(t *testing.T) {
+		user, err := store.GetUser(1)
+		assert.NoError(t, err)
+		user.Name = "Changed"
+
+		user, err = store.GetUser(1)
+		assert.NoError(t, err)
+		assert.Equal(t, "User 1", user.Name)
+	})
+
+	t.Run("Expired offer moves to the next user", func(t *testing.T) {
+		tz := models.DefaultLocation()
+		monday := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+
+		booked, err := store.CreateAppointment(&models.Appointment{UserID: 5, TrainerID: 1, StartsAt: monday, EndsAt: monday.Add(time.Minute * 30)})
+		assert.NoError(t, err)
+
+		first, err := store.CreateWaitlistEntry(getTestWaitlistEntry(6, monday, false))
+		assert.NoError(t, err)
+
+		second, err := store.CreateWaitlistEntry(getTestWaitlistEntry(7, monday, false))
+		assert.NoError(t, err)
+
+		_, err = store.CancelAppointment(booked.ID)
+		assert.NoError(t, err)
+
+		store.update(func(d *memoryData) error {
+			expiredAt := time.Now().Add(-time.Minute)
+			d.setWaitlistStatus(first.ID, models.WaitlistStatusOffered, &expiredAt, nil)
+			return nil
+		})
+
+		_, err = store.AcceptWaitlistOffer(first.ID)
+		assert.ErrorIs(t, err, ErrWaitlistOfferExpired)
+
+		entry, err := store.GetWaitlistEntry(second.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusOffered, entry.Status)
+	})
+}
+
+// TestMemoryStoreMatchesSQLite runs the same bookings against both backends
+// and expects every result and error to match.
+func TestMemoryStoreMatchesSQLite(t *testing.T) {
+	sqlite, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlite.Close()
+
+	memory, err := setupMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memory.Close()
+
+	assert.Equal(t, runParityScenario(t, sqlite), runParityScenario(t, memory))
+}
+
+func runParityScenario(t *testing.T, store Repository) []string {
+	steps := make([]string, 0)
+	record := func(step string, result any, err error) {
+		if err != nil {
+			steps = append(steps, fmt.Sprintf("%s: error %s", step, err))
+			return
+		}
+
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		steps = append(steps, fmt.Sprintf("%s: %s", step, encoded))
+	}
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+	at := func(days, hour, minute int) time.Time {
+		return time.Date(monday.Year(), monday.Month(), monday.Day()+days, hour, minute, 0, 0, tz)
+	}
+	book := func(step string, userID, trainerID int, startsAt time.Time, minutes int, resourceIDs []int) *models.Appointment {
+		appointment, err := store.CreateAppointment(&models.Appointment{
+			UserID:      userID,
+			TrainerID:   trainerID,
+			StartsAt:    startsAt,
+			EndsAt:      startsAt.Add(time.Duration(minutes) * time.Minute),
+			ResourceIDs: resourceIDs,
+		})
+		record(step, appointment, err)
+		return appointment
+	}
+
+	settings, err := store.SetTrainerSettings(&models.TrainerSettings{TrainerID: 1, TimeZone: models.DEFAULT_TZ, Durations: models.DefaultDurations, Capacity: 2})
+	record("group settings", settings, err)
+
+	settings, err = store.SetTrainerSettings(&models.TrainerSettings{TrainerID: 2, TimeZone: "America/New_York", Durations: []int{30, 60}, BufferBefore: 15, BufferAfter: 15})
+	record("buffer settings", settings, err)
+
+	schedule, err := store.SetTrainerWorkingHours(3, models.WeeklySchedule{{Weekday: time.Monday, StartTime: "10:00", EndTime: "14:00"}})
+	record("working hours", schedule, err)
+
+	timeOff, err := store.CreateTimeOff(&models.TimeOff{TrainerID: 2, StartsAt: at(0, 13, 0), EndsAt: at(0, 14, 0), Reason: "Dentist"})
+	record("time off", timeOff, err)
+
+	holiday, err := store.CreateHoliday(&models.Holiday{Date: "2030-07-09", Name: "Closed"})
+	record("holiday", holiday, err)
+
+	holidays, err := store.GetHolidays("2030-07-09", "")
+	record("holidays from", holidays, err)
+
+	holidays, err = store.GetHolidays("", "2030-07-08")
+	record("holidays to", holidays, err)
+
+	room, err := store.CreateResource(&models.Resource{Name: "Studio A", Kind: models.ResourceKindRoom, Active: true})
+	record("resource", room, err)
+
+	first := book("group session", 1, 1, at(0, 9, 0), 30, []int{room.ID})
+	book("group session second seat", 2, 1, at(0, 9, 0), 30, []int{room.ID})
+	book("group session full", 3, 1, at(0, 9, 0), 30, nil)
+	book("resource held by another trainer", 4, 2, at(0, 9, 0), 30, []int{room.ID})
+	offered := book("buffered session", 4, 2, at(0, 9, 30), 30, nil)
+	book("inside buffer", 5, 2, at(0, 10, 0), 30, nil)
+	moved := book("after buffer", 5, 2, at(0, 10, 30), 30, nil)
+	book("user double booked", 1, 3, at(0, 9, 0), 30, nil)
+	book("time off", 6, 2, at(0, 13, 0), 30, nil)
+	book("holiday", 6, 1, at(1, 9, 0), 30, nil)
+	book("blocks series", 7, 1, at(7, 11, 0), 60, nil)
+
+	recurrence, err := models.NewRecurrence(models.SeriesFrequencyWeekly, 4, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	series, err := store.CreateAppointmentSeries(6, 1, at(0, 11, 0), at(0, 11, 30), recurrence)
+	record("series", series, err)
+
+	if series != nil && len(series.Booked) == 3 {
+		result, err := store.RescheduleFollowingAppointments(series.Booked[1].ID, at(14, 12, 0), at(14, 12, 30))
+		record("reschedule following", result, err)
+
+		cancelled, err := store.CancelFollowingAppointments(series.Booked[2].ID)
+		record("cancel following", cancelled, err)
+	}
+
+	// INFO: Only the status of waitlist entries is compared since their timestamps come from the clock
+	status := func(entry *models.WaitlistEntry) string {
+		if entry == nil {
+			return ""
+		}
+		return entry.Status
+	}
+
+	entry, err := store.CreateWaitlistEntry(&models.WaitlistEntry{UserID: 8, TrainerID: 1, StartsAt: at(0, 9, 0), EndsAt: at(0, 9, 30), AutoBook: true})
+	record("auto book waitlist", status(entry), err)
+
+	waiting, err := store.CreateWaitlistEntry(&models.WaitlistEntry{UserID: 9, TrainerID: 2, StartsAt: at(0, 9, 30), EndsAt: at(0, 10, 0)})
+	record("offer waitlist", status(waiting), err)
+
+	if first != nil && offered != nil {
+		appointment, err := store.WithAudit(models.Audit{Actor: "front-desk", RequestID: "req-1"}).CancelAppointment(first.ID)
+		record("cancel", appointment, err)
+
+		appointment, err = store.CancelAppointment(first.ID)
+		record("cancel again", appointment, err)
+
+		appointment, err = store.CancelAppointment(offered.ID)
+		record("cancel buffered", appointment, err)
+	}
+
+	if entry != nil && waiting != nil {
+		booked, err := store.GetWaitlistEntry(entry.ID)
+		record("auto booked", status(booked), err)
+
+		offer, err := store.GetWaitlistEntry(waiting.ID)
+		record("offered", status(offer), err)
+
+		book("held by offer", 10, 2, at(0, 9, 30), 30, nil)
+
+		timeslots, err := store.GetTrainerAvailability(2, at(0, 0, 0), at(1, 0, 0), time.Minute*30, 0, nil)
+		record("availability while held", timeslots, err)
+
+		available, err := store.GetAvailability([]int{2}, at(0, 0, 0), at(1, 0, 0), time.Minute*30, 0, nil, 0)
+		record("search while held", available, err)
+
+		appointment, err := store.AcceptWaitlistOffer(waiting.ID)
+		record("accept offer", appointment, err)
+	}
+
+	if moved != nil {
+		appointment, err := store.RescheduleAppointment(moved.ID, at(0, 11, 0), at(0, 11, 30))
+		record("reschedule", appointment, err)
+
+		details, err := store.GetAppointmentDetails(moved.ID)
+		record("details", details, err)
+	}
+
+	// INFO: Change times come from the clock so they are left out
+	history := func(step string, id int) {
+		changes, err := store.GetAppointmentHistory(id)
+		for _, change := range changes {
+			change.ChangedAt = time.Time{}
+		}
+		record(step, changes, err)
+	}
+
+	for id := 1; id <= 20; id++ {
+		history(fmt.Sprintf("appointment %d history", id), id)
+	}
+
+	appointments, next, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{Limit: 2})
+	record("trainer page", appointments, err)
+
+	appointments, _, err = store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{Limit: 2, Cursor: next})
+	record("trainer next page", appointments, err)
+
+	appointments, _, err = store.GetAppointmentsByTrainerID(2, at(0, 0, 0), at(1, 0, 0), models.Page{})
+	record("trainer window", appointments, err)
+
+	for _, filter := range []string{"", AppointmentFilterUpcoming, AppointmentFilterPast, AppointmentFilterCancelled} {
+		appointments, _, err = store.GetAppointmentsByUserID(6, time.Time{}, time.Time{}, filter, models.Page{})
+		record("user "+filter, appointments, err)
+	}
+
+	appointments, _, err = store.GetAppointmentsByResourceID(room.ID, time.Time{}, time.Time{}, models.Page{})
+	record("resource calendar", appointments, err)
+
+	appointments, err = store.ExportAppointments(0, 0, time.Time{}, time.Time{})
+	record("export", appointments, err)
+
+	appointments, err = store.ExportAppointments(2, 5, at(0, 0, 0), time.Time{})
+	record("export filtered", appointments, err)
+
+	for trainerID := 1; trainerID <= 3; trainerID++ {
+		timeslots, err := store.GetTrainerAvailability(trainerID, at(0, 0, 0), at(2, 0, 0), time.Minute*30, 2, []int{room.ID})
+		record(fmt.Sprintf("trainer %d availability", trainerID), timeslots, err)
+	}
+
+	available, err := store.GetAvailability(nil, at(0, 0, 0), at(1, 0, 0), time.Minute*30, 5, []int{room.ID}, 20)
+	record("availability", available, err)
+
+	exported, err := store.ExportAppointments(2, 0, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := store.ImportAppointments(exported)
+	record("import taken IDs", imported, err)
+
+	for _, appointment := range exported {
+		appointment.ID = 0
+	}
+	imported, err = store.ImportAppointments(exported)
+	record("import", imported, err)
+
+	imported, err = store.ImportAppointments([]*models.Appointment{
+		{ID: 100, UserID: 1, TrainerID: 3, StartsAt: at(2, 9, 0), EndsAt: at(2, 9, 30)},
+	})
+	record("import with ID", imported, err)
+
+	book("after import with ID", 1, 3, at(2, 10, 0), 30, nil)
+
+	imported, err = store.ImportAppointments([]*models.Appointment{
+		{UserID: 1, TrainerID: 3, StartsAt: at(0, 9, 0), EndsAt: at(0, 9, 30)},
+		{UserID: 99, TrainerID: 3, StartsAt: at(0, 10, 0), EndsAt: at(0, 10, 30)},
+	})
+	record("import unknown user", imported, err)
+
+	appointments, err = store.ExportAppointments(0, 0, time.Time{}, time.Time{})
+	record("export after import", appointments, err)
+
+	return steps
+}
@@ -0,0 +1,508 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Migration is a versioned change to the schema. Up applies it and Down
+// reverts it, each inside the same transaction that records the version in
+// schema_migrations.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx) error
+	Down    func(tx *sql.Tx) error
+}
+
+// MigrationStatus reports whether a migration has been applied and when.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// INFO: Append new migrations with the next version. Never edit one that has shipped.
+// The early migrations use IF NOT EXISTS and addColumn so databases created before versioning are adopted as they are.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_initial_schema",
+		Up: func(tx *sql.Tx) error {
+			if err := execMigration(`
+            CREATE TABLE IF NOT EXISTS users (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                name TEXT NOT NULL,
+                email TEXT NOT NULL UNIQUE,
+                active BOOLEAN NOT NULL DEFAULT TRUE
+            );
+
+            CREATE TABLE IF NOT EXISTS trainers (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                name TEXT NOT NULL,
+                email TEXT NOT NULL UNIQUE,
+                active BOOLEAN NOT NULL DEFAULT TRUE
+            );
+
+            CREATE TABLE IF NOT EXISTS appointment_series (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                user_id INTEGER NOT NULL,
+                trainer_id INTEGER NOT NULL,
+                rrule TEXT NOT NULL,
+                starts_at DATETIME NOT NULL,
+                ends_at DATETIME NOT NULL
+            );
+
+            CREATE TABLE IF NOT EXISTS appointments (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                user_id INTEGER NOT NULL REFERENCES users(id),
+                trainer_id INTEGER NOT NULL REFERENCES trainers(id),
+                starts_at DATETIME NOT NULL,
+                ends_at DATETIME NOT NULL,
+                status TEXT NOT NULL DEFAULT 'scheduled',
+                series_id INTEGER REFERENCES appointment_series(id)
+            );
+
+            CREATE INDEX IF NOT EXISTS idx_appointments_user_id ON appointments (user_id);
+
+            CREATE TABLE IF NOT EXISTS trainer_working_hours (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                trainer_id INTEGER NOT NULL,
+                weekday INTEGER NOT NULL,
+                start_time TEXT NOT NULL,
+                end_time TEXT NOT NULL
+            );
+
+            CREATE TABLE IF NOT EXISTS trainer_settings (
+                trainer_id INTEGER PRIMARY KEY,
+                time_zone TEXT NOT NULL,
+                durations TEXT NOT NULL DEFAULT '30,45,60,90'
+            );
+
+            CREATE TABLE IF NOT EXISTS trainer_time_off (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                trainer_id INTEGER NOT NULL,
+                starts_at DATETIME NOT NULL,
+                ends_at DATETIME NOT NULL,
+                all_day BOOLEAN NOT NULL DEFAULT FALSE,
+                reason TEXT NOT NULL DEFAULT ''
+            );
+
+            CREATE TABLE IF NOT EXISTS holidays (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                date TEXT NOT NULL,
+                name TEXT NOT NULL,
+                start_time TEXT NOT NULL DEFAULT '',
+                end_time TEXT NOT NULL DEFAULT ''
+            );
+
+            CREATE TABLE IF NOT EXISTS waitlist (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                user_id INTEGER NOT NULL,
+                trainer_id INTEGER NOT NULL,
+                starts_at DATETIME NOT NULL,
+                ends_at DATETIME NOT NULL,
+                auto_book BOOLEAN NOT NULL DEFAULT FALSE,
+                status TEXT NOT NULL DEFAULT 'waiting',
+                offer_expires_at DATETIME,
+                appointment_id INTEGER REFERENCES appointments(id),
+                created_at DATETIME NOT NULL
+            );
+            `)(tx); err != nil {
+				return err
+			}
+
+			// INFO: The original appointments table predates cancellation and series
+			if err := addColumn("appointments", "status", "TEXT NOT NULL DEFAULT 'scheduled'")(tx); err != nil {
+				return err
+			}
+			if err := addColumn("appointments", "series_id", "INTEGER REFERENCES appointment_series(id)")(tx); err != nil {
+				return err
+			}
+
+			// INFO: Databases from before users and trainers only know them by ID
+			if err := backfillParticipants(tx); err != nil {
+				return err
+			}
+			return addAppointmentForeignKeys(tx)
+		},
+		Down: execMigration(`
+        DROP TABLE IF EXISTS waitlist;
+        DROP TABLE IF EXISTS holidays;
+        DROP TABLE IF EXISTS trainer_time_off;
+        DROP TABLE IF EXISTS trainer_settings;
+        DROP TABLE IF EXISTS trainer_working_hours;
+        DROP TABLE IF EXISTS appointments;
+        DROP TABLE IF EXISTS appointment_series;
+        DROP TABLE IF EXISTS trainers;
+        DROP TABLE IF EXISTS users;
+        `),
+	},
+	{
+		Version: 2,
+		Name:    "add_trainer_capacity",
+		Up:      addColumn("trainer_settings", "capacity", "INTEGER NOT NULL DEFAULT 1"),
+		Down:    dropColumn("trainer_settings", "capacity"),
+	},
+	{
+		Version: 3,
+		Name:    "create_resources",
+		Up: execMigration(`
+        CREATE TABLE IF NOT EXISTS resources (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            name TEXT NOT NULL,
+            kind TEXT NOT NULL,
+            active BOOLEAN NOT NULL DEFAULT TRUE
+        );
+
+        CREATE TABLE IF NOT EXISTS appointment_resources (
+            appointment_id INTEGER NOT NULL REFERENCES appointments(id),
+            resource_id INTEGER NOT NULL REFERENCES resources(id),
+            PRIMARY KEY (appointment_id, resource_id)
+        );
+
+        CREATE INDEX IF NOT EXISTS idx_appointment_resources_resource_id ON appointment_resources (resource_id);
+        `),
+		Down: execMigration(`
+        DROP TABLE IF EXISTS appointment_resources;
+        DROP TABLE IF EXISTS resources;
+        `),
+	},
+	{
+		Version: 4,
+		Name:    "add_trainer_buffers",
+		Up: func(tx *sql.Tx) error {
+			if err := addColumn("trainer_settings", "buffer_before", "INTEGER NOT NULL DEFAULT 0")(tx); err != nil {
+				return err
+			}
+			return addColumn("trainer_settings", "buffer_after", "INTEGER NOT NULL DEFAULT 0")(tx)
+		},
+		Down: func(tx *sql.Tx) error {
+			if err := dropColumn("trainer_settings", "buffer_after")(tx); err != nil {
+				return err
+			}
+			return dropColumn("trainer_settings", "buffer_before")(tx)
+		},
+	},
+	{
+		Version: 5,
+		Name:    "normalize_timestamps",
+		// INFO: Normalized values are still RFC3339, so Down only has to restore the old index
+		Up: func(tx *sql.Tx) error {
+			if err := normalizeTimestamps(map[string][]string{
+				"appointment_series": {"starts_at", "ends_at"},
+				"appointments":       {"starts_at", "ends_at"},
+				"trainer_time_off":   {"starts_at", "ends_at"},
+				"waitlist":           {"starts_at", "ends_at", "offer_expires_at", "created_at"},
+			})(tx); err != nil {
+				return err
+			}
+
+			return execMigration(`
+            DROP INDEX IF EXISTS idx_appointments_user_id;
+            CREATE INDEX IF NOT EXISTS idx_appointments_user_id_starts_at ON appointments (user_id, starts_at);
+            CREATE INDEX IF NOT EXISTS idx_appointments_trainer_id_starts_at ON appointments (trainer_id, starts_at);
+            `)(tx)
+		},
+		Down: execMigration(`
+        DROP INDEX IF EXISTS idx_appointments_trainer_id_starts_at;
+        DROP INDEX IF EXISTS idx_appointments_user_id_starts_at;
+        CREATE INDEX IF NOT EXISTS idx_appointments_user_id ON appointments (user_id);
+        `),
+	},
+	{
+		Version: 6,
+		Name:    "create_appointment_history",
+		Up: execMigration(`
+        CREATE TABLE IF NOT EXISTS appointment_history (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            appointment_id INTEGER NOT NULL REFERENCES appointments(id),
+            action TEXT NOT NULL,
+            actor TEXT NOT NULL,
+            request_id TEXT NOT NULL DEFAULT '',
+            changed_at DATETIME NOT NULL,
+            before_state TEXT,
+            after_state TEXT NOT NULL
+        );
+
+        CREATE INDEX IF NOT EXISTS idx_appointment_history_appointment_id ON appointment_history (appointment_id);
+
+        CREATE TRIGGER IF NOT EXISTS appointment_history_no_update BEFORE UPDATE ON appointment_history
+        BEGIN
+            SELECT RAISE(ABORT, 'Appointment history is append-only');
+        END;
+
+        CREATE TRIGGER IF NOT EXISTS appointment_history_no_delete BEFORE DELETE ON appointment_history
+        BEGIN
+            SELECT RAISE(ABORT, 'Appointment history is append-only');
+        END;
+        `),
+		Down: execMigration(`
+        DROP TABLE IF EXISTS appointment_history;
+        `),
+	},
+}
+
+func execMigration(query string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(query)
+		return err
+	}
+}
+
+// addColumn adds the column unless the table already has it, which is the
+// case for databases created before the column had a migration.
+func addColumn(table, column, definition string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		exists, err := hasColumn(tx, table, column)
+		if err != nil || exists {
+			return err
+		}
+
+		_, err = tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column + ` ` + definition)
+		return err
+	}
+}
+
+// backfillParticipants adds a placeholder user or trainer for every ID the
+// other tables refer to but that has no row yet, so joins keep finding them.
+func backfillParticipants(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+    INSERT INTO users (id, name, email)
+    SELECT user_id, 'User ' || user_id, 'user' || user_id || '@example.invalid'
+    FROM (
+        SELECT user_id FROM appointments
+        UNION SELECT user_id FROM appointment_series
+        UNION SELECT user_id FROM waitlist
+    )
+    WHERE user_id NOT IN (SELECT id FROM users);
+
+    INSERT INTO trainers (id, name, email)
+    SELECT trainer_id, 'Trainer ' || trainer_id, 'trainer' || trainer_id || '@example.invalid'
+    FROM (
+        SELECT trainer_id FROM appointments
+        UNION SELECT trainer_id FROM appointment_series
+        UNION SELECT trainer_id FROM trainer_working_hours
+        UNION SELECT trainer_id FROM trainer_settings
+        UNION SELECT trainer_id FROM trainer_time_off
+        UNION SELECT trainer_id FROM waitlist
+    )
+    WHERE trainer_id NOT IN (SELECT id FROM trainers);
+    `)
+	return err
+}
+
+// addAppointmentForeignKeys rebuilds an appointments table created before it
+// referenced users and trainers, since SQLite cannot add a constraint to an
+// existing table. The waitlist links are set aside while the old table is
+// dropped, so the rebuild works with foreign keys on, and the ID sequence is
+// carried over so deleted appointments' IDs are not handed out again.
+func addAppointmentForeignKeys(tx *sql.Tx) error {
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_foreign_key_list('appointments') WHERE "table" = 'users'`).Scan(&count); err != nil || count > 0 {
+		return err
+	}
+
+	return execMigration(`
+    CREATE TEMP TABLE waitlist_appointments AS
+    SELECT id, appointment_id FROM waitlist WHERE appointment_id IS NOT NULL;
+
+    UPDATE waitlist SET appointment_id = NULL WHERE appointment_id IS NOT NULL;
+
+    CREATE TABLE appointments_new (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL REFERENCES users(id),
+        trainer_id INTEGER NOT NULL REFERENCES trainers(id),
+        starts_at DATETIME NOT NULL,
+        ends_at DATETIME NOT NULL,
+        status TEXT NOT NULL DEFAULT 'scheduled',
+        series_id INTEGER REFERENCES appointment_series(id)
+    );
+
+    INSERT INTO sqlite_sequence (name, seq)
+    SELECT 'appointments_new', seq FROM sqlite_sequence WHERE name = 'appointments';
+
+    INSERT INTO appointments_new (id, user_id, trainer_id, starts_at, ends_at, status, series_id)
+    SELECT id, user_id, trainer_id, starts_at, ends_at, status, series_id FROM appointments;
+
+    DROP TABLE appointments;
+    ALTER TABLE appointments_new RENAME TO appointments;
+    CREATE INDEX IF NOT EXISTS idx_appointments_user_id ON appointments (user_id);
+
+    UPDATE waitlist
+    SET appointment_id = (SELECT appointment_id FROM waitlist_appointments WHERE waitlist_appointments.id = waitlist.id)
+    WHERE id IN (SELECT id FROM waitlist_appointments);
+
+    DROP TABLE waitlist_appointments;
+    `)(tx)
+}
+
+func dropColumn(table, column string) func(tx *sql.Tx) error {
+	return execMigration(`ALTER TABLE ` + table + ` DROP COLUMN ` + column)
+}
+
+// normalizeTimestamps rewrites the given columns in the format of formatTime.
+// SQLite applies any offset while parsing, and values it cannot parse are
+// left untouched.
+func normalizeTimestamps(columns map[string][]string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for table, names := range columns {
+			for _, column := range names {
+				normalized := `strftime('%Y-%m-%dT%H:%M:%SZ', ` + column + `)`
+				if _, err := tx.Exec(`UPDATE ` + table + ` SET ` + column + ` = ` + normalized + ` WHERE ` + normalized + ` IS NOT NULL`); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func hasColumn(q querier, table, column string) (bool, error) {
+	var count int
+	err := q.QueryRow(`SELECT COUNT(*) FROM pragma_table_info($1) WHERE name = $2`, table, column).Scan(&count)
+	return count > 0, err
+}
+
+func (s *Store) createSchemaMigrationsTable() error {
+	query := `
+    CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        applied_at DATETIME NOT NULL
+    );
+    `
+
+	if _, err := s.DB.Exec(query); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMigrationStatus lists every known migration in version order along with
+// when it was applied, if at all.
+func (s *Store) GetMigrationStatus() ([]*MigrationStatus, error) {
+	if err := s.createSchemaMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]time.Time)
+
+	rows, err := s.DB.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := &MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// MigrateUp applies up to steps pending migrations in version order, or every
+// pending migration when steps is 0. It returns the migrations it applied.
+func (s *Store) MigrateUp(steps int) ([]*MigrationStatus, error) {
+	statuses, err := s.GetMigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]*MigrationStatus, 0)
+	for i, status := range statuses {
+		if status.AppliedAt != nil {
+			continue
+		}
+
+		if steps > 0 && len(applied) == steps {
+			break
+		}
+
+		appliedAt := time.Now().UTC()
+		if err := s.runMigration(migrations[i].Up, `
+		INSERT INTO schema_migrations (version, name, applied_at)
+		VALUES ($1, $2, $3)
+		`, status.Version, status.Name, formatTime(appliedAt)); err != nil {
+			return applied, err
+		}
+
+		status.AppliedAt = &appliedAt
+		applied = append(applied, status)
+	}
+
+	return applied, nil
+}
+
+// MigrateDown reverts up to steps applied migrations, newest first, or every
+// applied migration when steps is 0. It returns the migrations it reverted.
+func (s *Store) MigrateDown(steps int) ([]*MigrationStatus, error) {
+	statuses, err := s.GetMigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	reverted := make([]*MigrationStatus, 0)
+	for i := len(statuses) - 1; i >= 0; i-- {
+		status := statuses[i]
+		if status.AppliedAt == nil {
+			continue
+		}
+
+		if steps > 0 && len(reverted) == steps {
+			break
+		}
+
+		if err := s.runMigration(migrations[i].Down, `
+		DELETE FROM schema_migrations
+		WHERE version = $1
+		`, status.Version); err != nil {
+			return reverted, err
+		}
+
+		status.AppliedAt = nil
+		reverted = append(reverted, status)
+	}
+
+	return reverted, nil
+}
+
+// runMigration applies a schema change and records it in schema_migrations
+// within a single transaction, so a failed migration leaves no trace.
+func (s *Store) runMigration(change func(tx *sql.Tx) error, record string, args ...any) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := change(tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
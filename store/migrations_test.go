@@ -0,0 +1,267 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrations(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	t.Run("Init applies every migration", func(t *testing.T) {
+		statuses, err := store.GetMigrationStatus()
+		assert.NoError(t, err)
+		assert.Len(t, statuses, len(migrations))
+
+		for i, status := range statuses {
+			assert.Equal(t, i+1, status.Version)
+			assert.NotNil(t, status.AppliedAt)
+		}
+
+		applied, err := store.MigrateUp(0)
+		assert.NoError(t, err)
+		assert.Empty(t, applied)
+	})
+
+	// INFO: Reverts every migration back to and including add_trainer_buffers
+	t.Run("Down reverts the newest migrations", func(t *testing.T) {
+		reverted, err := store.MigrateDown(len(migrations) - 3)
+		assert.NoError(t, err)
+		if assert.Len(t, reverted, len(migrations)-3) {
+			assert.Equal(t, "add_trainer_buffers", reverted[len(reverted)-1].Name)
+		}
+
+		exists, err := hasColumn(store.DB, "trainer_settings", "buffer_before")
+		assert.NoError(t, err)
+		assert.False(t, exists)
+
+		statuses, err := store.GetMigrationStatus()
+		assert.NoError(t, err)
+		assert.Nil(t, statuses[len(statuses)-1].AppliedAt)
+	})
+
+	t.Run("Up reapplies them and keeps data", func(t *testing.T) {
+		applied, err := store.MigrateUp(0)
+		assert.NoError(t, err)
+		assert.Len(t, applied, len(migrations)-3)
+
+		users, err := store.GetUsers()
+		assert.NoError(t, err)
+		assert.Len(t, users, 10)
+
+		settings, err := store.GetTrainerSettings(1)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, settings.BufferBefore)
+	})
+
+	t.Run("Down and up by steps", func(t *testing.T) {
+		reverted, err := store.MigrateDown(0)
+		assert.NoError(t, err)
+		assert.Len(t, reverted, len(migrations))
+
+		_, err = store.GetUsers()
+		assert.Error(t, err)
+
+		applied, err := store.MigrateUp(2)
+		assert.NoError(t, err)
+		assert.Len(t, applied, 2)
+
+		exists, err := hasColumn(store.DB, "trainer_settings", "capacity")
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		applied, err = store.MigrateUp(0)
+		assert.NoError(t, err)
+		assert.Len(t, applied, len(migrations)-2)
+	})
+}
+
+func TestMigrationsNormalizeTimestamps(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// INFO: Reverts every migration after add_trainer_buffers
+	if _, err := store.MigrateDown(len(migrations) - 4); err != nil {
+		t.Fatal(err)
+	}
+
+	// INFO: Rows written before normalization kept the offset they were booked in
+	if _, err := store.DB.Exec(`
+	INSERT INTO appointments (user_id, trainer_id, starts_at, ends_at) VALUES
+		(1, 1, '2030-07-05T07:30:00Z', '2030-07-05T08:00:00Z'),
+		(2, 1, '2030-07-05T08:00:00+02:00', '2030-07-05T08:30:00+02:00');
+	INSERT INTO waitlist (user_id, trainer_id, starts_at, ends_at, created_at) VALUES
+		(3, 1, '2030-07-05T08:00:00-05:00', '2030-07-05T08:30:00-05:00', '2030-07-01T12:00:00+01:00');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := store.MigrateUp(0)
+	assert.NoError(t, err)
+	assert.Len(t, applied, len(migrations)-4)
+
+	var startsAt, endsAt string
+	assert.NoError(t, store.DB.QueryRow(`SELECT starts_at, ends_at FROM appointments WHERE id = 2`).Scan(&startsAt, &endsAt))
+	assert.Equal(t, "2030-07-05T06:00:00Z", startsAt)
+	assert.Equal(t, "2030-07-05T06:30:00Z", endsAt)
+
+	var createdAt string
+	assert.NoError(t, store.DB.QueryRow(`SELECT starts_at, created_at FROM waitlist WHERE id = 1`).Scan(&startsAt, &createdAt))
+	assert.Equal(t, "2030-07-05T13:00:00Z", startsAt)
+	assert.Equal(t, "2030-07-01T11:00:00Z", createdAt)
+
+	appointments, _, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, appointments, 2) {
+		assert.Equal(t, 2, appointments[0].ID)
+		assert.Equal(t, 1, appointments[1].ID)
+	}
+}
+
+func TestMigrationsAdoptExistingDatabase(t *testing.T) {
+	store, err := NewTestStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// INFO: Databases created before versioning have tables but no schema_migrations
+	if _, err := store.DB.Exec(`
+	CREATE TABLE trainers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL UNIQUE,
+		active BOOLEAN NOT NULL DEFAULT TRUE
+	);
+
+	CREATE TABLE trainer_settings (
+		trainer_id INTEGER PRIMARY KEY,
+		time_zone TEXT NOT NULL,
+		durations TEXT NOT NULL DEFAULT '30,45,60,90',
+		capacity INTEGER NOT NULL DEFAULT 1
+	);
+
+	CREATE TABLE appointments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		trainer_id INTEGER NOT NULL,
+		starts_at DATETIME NOT NULL,
+		ends_at DATETIME NOT NULL
+	);
+
+	INSERT INTO trainers (name, email) VALUES ('Trainer 1', 'trainer1@example.com');
+	INSERT INTO trainer_settings (trainer_id, time_zone, capacity) VALUES (1, 'Europe/London', 4);
+	INSERT INTO appointments (user_id, trainer_id, starts_at, ends_at) VALUES
+		(1, 1, '2030-07-05T09:00:00+01:00', '2030-07-05T09:30:00+01:00'),
+		(2, 1, '2030-07-05T08:00:00+01:00', '2030-07-05T08:30:00+01:00');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, store.Init())
+
+	// INFO: The original appointments table is missing the status and series_id columns
+	appointments, _, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, appointments, 2) {
+		assert.Equal(t, 2, appointments[0].ID)
+		assert.Equal(t, models.AppointmentStatusScheduled, appointments[0].Status)
+		assert.Nil(t, appointments[0].SeriesID)
+		assert.Equal(t, "2030-07-05T08:00:00+01:00", appointments[0].StartsAt.Format(time.RFC3339))
+	}
+
+	cancelled, err := store.CancelAppointment(1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.AppointmentStatusCancelled, cancelled.Status)
+
+	trainer, err := store.GetTrainer(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Trainer 1", trainer.Name)
+
+	settings, err := store.GetTrainerSettings(1)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.TrainerSettings{TrainerID: 1, TimeZone: "Europe/London", Durations: models.DefaultDurations, Capacity: 4}, settings)
+}
+
+func TestMigrationsAdoptDatabaseWithoutParticipants(t *testing.T) {
+	store, err := NewTestStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// INFO: Databases from before users and trainers only have their IDs on appointments and the waitlist
+	if _, err := store.DB.Exec(`
+	CREATE TABLE appointments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		trainer_id INTEGER NOT NULL,
+		starts_at DATETIME NOT NULL,
+		ends_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE waitlist (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		trainer_id INTEGER NOT NULL,
+		starts_at DATETIME NOT NULL,
+		ends_at DATETIME NOT NULL,
+		auto_book BOOLEAN NOT NULL DEFAULT FALSE,
+		status TEXT NOT NULL DEFAULT 'waiting',
+		offer_expires_at DATETIME,
+		appointment_id INTEGER REFERENCES appointments(id),
+		created_at DATETIME NOT NULL
+	);
+
+	INSERT INTO appointments (user_id, trainer_id, starts_at, ends_at) VALUES
+		(1, 1, '2030-07-05T09:00:00Z', '2030-07-05T09:30:00Z'),
+		(2, 1, '2030-07-05T10:00:00Z', '2030-07-05T10:30:00Z'),
+		(2, 1, '2030-07-05T11:00:00Z', '2030-07-05T11:30:00Z');
+	DELETE FROM appointments WHERE id = 3;
+	INSERT INTO waitlist (user_id, trainer_id, starts_at, ends_at, status, appointment_id, created_at) VALUES
+		(3, 1, '2030-07-05T10:00:00Z', '2030-07-05T10:30:00Z', 'booked', 2, '2030-07-01T12:00:00Z');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, store.Init())
+
+	details, err := store.GetAppointmentDetails(1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "User 1", details.User.Name)
+		assert.Equal(t, "Trainer 1", details.Trainer.Name)
+		assert.True(t, details.Trainer.Active)
+	}
+
+	appointments, _, err := store.GetAppointmentsByUserID(2, time.Time{}, time.Time{}, "", models.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, appointments, 1)
+
+	users, err := store.GetUsers()
+	assert.NoError(t, err)
+	assert.Len(t, users, 3)
+
+	// INFO: The rebuilt table references users and trainers like a new one
+	var references int
+	assert.NoError(t, store.DB.QueryRow(`SELECT COUNT(*) FROM pragma_foreign_key_list('appointments') WHERE "table" IN ('users', 'trainers')`).Scan(&references))
+	assert.Equal(t, 2, references)
+
+	entry, err := store.GetWaitlistEntry(1)
+	if assert.NoError(t, err) && assert.NotNil(t, entry.AppointmentID) {
+		assert.Equal(t, 2, *entry.AppointmentID)
+	}
+
+	var seq int
+	assert.NoError(t, store.DB.QueryRow(`SELECT seq FROM sqlite_sequence WHERE name = 'appointments'`).Scan(&seq))
+	assert.Equal(t, 3, seq)
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var journalModes = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+
+// Options controls where the SQLite database lives and how its connections
+// are configured.
+type Options struct {
+	// DSN is the database file, optionally followed by go-sqlite3 query
+	// parameters. Parameters given here take precedence over the fields below,
+	// except _txlock, which must stay immediate.
+	DSN             string
+	JournalMode     string
+	BusyTimeout     time.Duration
+	ForeignKeys     bool
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		DSN:         "./store.db",
+		JournalMode: "WAL",
+		BusyTimeout: time.Second * 5,
+		ForeignKeys: true,
+	}
+}
+
+// OptionsFromEnv starts from DefaultOptions and overrides every field whose
+// DB_* variable is set.
+func OptionsFromEnv() (Options, error) {
+	opts := DefaultOptions()
+
+	if dsn := os.Getenv("DB_PATH"); dsn != "" {
+		opts.DSN = dsn
+	}
+	if mode := os.Getenv("DB_JOURNAL_MODE"); mode != "" {
+		opts.JournalMode = mode
+	}
+
+	durations := map[string]*time.Duration{
+		"DB_BUSY_TIMEOUT":      &opts.BusyTimeout,
+		"DB_CONN_MAX_LIFETIME": &opts.ConnMaxLifetime,
+	}
+	for key, field := range durations {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return opts, fmt.Errorf("Invalid %s: %s", key, value)
+		}
+		*field = duration
+	}
+
+	ints := map[string]*int{
+		"DB_MAX_OPEN_CONNS": &opts.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS": &opts.MaxIdleConns,
+	}
+	for key, field := range ints {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return opts, fmt.Errorf("Invalid %s: %s", key, value)
+		}
+		*field = n
+	}
+
+	if value := os.Getenv("DB_FOREIGN_KEYS"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("Invalid DB_FOREIGN_KEYS: %s", value)
+		}
+		opts.ForeignKeys = enabled
+	}
+
+	return opts, opts.Validate()
+}
+
+func (o Options) Validate() error {
+	if o.DSN == "" {
+		return fmt.Errorf("Database path is required")
+	}
+	if o.JournalMode != "" && !slices.Contains(journalModes, strings.ToUpper(o.JournalMode)) {
+		return fmt.Errorf("Invalid journal mode: %s", o.JournalMode)
+	}
+	if o.BusyTimeout < 0 || o.ConnMaxLifetime < 0 {
+		return fmt.Errorf("Timeouts must not be negative")
+	}
+	if o.MaxOpenConns < 0 || o.MaxIdleConns < 0 {
+		return fmt.Errorf("Connection limits must not be negative")
+	}
+	// INFO: Bookings check and insert in one transaction, which is only safe while it takes the write lock up front
+	if _, query, found := strings.Cut(o.DSN, "?"); found {
+		params, err := url.ParseQuery(query)
+		if err != nil {
+			return fmt.Errorf("Invalid database path: %s", o.DSN)
+		}
+		if params.Has("_txlock") && !strings.EqualFold(params.Get("_txlock"), "immediate") {
+			return fmt.Errorf("Transaction lock must be immediate: %s", params.Get("_txlock"))
+		}
+	}
+	return nil
+}
+
+// dataSourceName appends the options to the DSN as go-sqlite3 parameters.
+// go-sqlite3 reads the first value of each parameter, so any already present
+// in the DSN win.
+func (o Options) dataSourceName() string {
+	params := url.Values{}
+	// INFO: Immediate transactions take the write lock up front so concurrent bookings queue instead of failing
+	params.Set("_txlock", "immediate")
+	if o.JournalMode != "" {
+		params.Set("_journal_mode", strings.ToUpper(o.JournalMode))
+	}
+	params.Set("_busy_timeout", strconv.FormatInt(o.BusyTimeout.Milliseconds(), 10))
+	if o.ForeignKeys {
+		params.Set("_foreign_keys", "1")
+	} else {
+		params.Set("_foreign_keys", "0")
+	}
+
+	separator := "?"
+	if strings.Contains(o.DSN, "?") {
+		separator = "&"
+	}
+	return o.DSN + separator + params.Encode()
+}
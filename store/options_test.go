@@ -0,0 +1,166 @@
+package store
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		opts, err := OptionsFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultOptions(), opts)
+	})
+
+	t.Run("Read from the environment", func(t *testing.T) {
+		t.Setenv("DB_PATH", "/var/lib/future/staging.db")
+		t.Setenv("DB_JOURNAL_MODE", "delete")
+		t.Setenv("DB_BUSY_TIMEOUT", "250ms")
+		t.Setenv("DB_FOREIGN_KEYS", "false")
+		t.Setenv("DB_MAX_OPEN_CONNS", "4")
+		t.Setenv("DB_MAX_IDLE_CONNS", "2")
+		t.Setenv("DB_CONN_MAX_LIFETIME", "1h")
+
+		opts, err := OptionsFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, Options{
+			DSN:             "/var/lib/future/staging.db",
+			JournalMode:     "delete",
+			BusyTimeout:     time.Millisecond * 250,
+			ForeignKeys:     false,
+			MaxOpenConns:    4,
+			MaxIdleConns:    2,
+			ConnMaxLifetime: time.Hour,
+		}, opts)
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		for key, value := range map[string]string{
+			"DB_PATH":           "./store.db?_txlock=deferred",
+			"DB_JOURNAL_MODE":   "fast",
+			"DB_BUSY_TIMEOUT":   "5",
+			"DB_FOREIGN_KEYS":   "maybe",
+			"DB_MAX_OPEN_CONNS": "-1",
+		} {
+			t.Run(key, func(t *testing.T) {
+				t.Setenv(key, value)
+				_, err := OptionsFromEnv()
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestNewStore(t *testing.T) {
+	t.Run("Applies options at open time", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.DSN = filepath.Join(t.TempDir(), "store.db")
+		opts.BusyTimeout = time.Second * 2
+		opts.MaxOpenConns = 3
+
+		store, err := NewStore(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+
+		var journalMode string
+		var busyTimeout, foreignKeys int
+		assert.NoError(t, store.DB.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+		assert.NoError(t, store.DB.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout))
+		assert.NoError(t, store.DB.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys))
+
+		assert.Equal(t, "wal", journalMode)
+		assert.Equal(t, 2000, busyTimeout)
+		assert.Equal(t, 1, foreignKeys)
+		assert.Equal(t, 3, store.DB.Stats().MaxOpenConnections)
+	})
+
+	t.Run("Parameters in the DSN take precedence", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.DSN = filepath.Join(t.TempDir(), "store.db") + "?_busy_timeout=100&_journal_mode=DELETE"
+
+		store, err := NewStore(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+
+		var journalMode string
+		var busyTimeout int
+		assert.NoError(t, store.DB.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+		assert.NoError(t, store.DB.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout))
+
+		assert.Equal(t, "delete", journalMode)
+		assert.Equal(t, 100, busyTimeout)
+	})
+
+	t.Run("The transaction lock cannot be overridden", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.DSN = filepath.Join(t.TempDir(), "store.db") + "?_txlock=deferred"
+
+		_, err := NewStore(opts)
+		assert.Error(t, err)
+
+		opts.DSN = filepath.Join(t.TempDir(), "store.db") + "?_txlock=immediate"
+		store, err := NewStore(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		store.Close()
+	})
+
+	t.Run("Unreachable path", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.DSN = filepath.Join(t.TempDir(), "missing", "store.db")
+
+		_, err := NewStore(opts)
+		assert.Error(t, err)
+	})
+
+	t.Run("Concurrent writes to a file database", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.DSN = filepath.Join(t.TempDir(), "store.db")
+
+		store, err := NewStore(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+
+		if err := store.Init(); err != nil {
+			t.Fatal(err)
+		}
+		if err := seedParticipants(store); err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+
+		// INFO: Every user books a different slot, so each write has to succeed
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(userID int) {
+				defer wg.Done()
+				appointment := getTestAppointment()
+				appointment.UserID = userID
+				appointment.StartsAt = appointment.StartsAt.Add(time.Hour * time.Duration(userID))
+				appointment.EndsAt = appointment.EndsAt.Add(time.Hour * time.Duration(userID))
+				_, err := store.CreateAppointment(appointment)
+				errs <- err
+			}(i + 1)
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"fmt"
+	"future-app/models"
+	"time"
+)
+
+// Backends accepted by Open.
+const (
+	BackendSQLite = "sqlite"
+	BackendMemory = "memory"
+)
+
+// Repository is everything the API needs from storage. Store keeps the data in
+// SQLite and MemoryStore keeps it in process memory; both enforce the same
+// booking rules and return the same errors.
+type Repository interface {
+	Init() error
+	Close()
+
+	// WithAudit returns a repository that records appointment changes under
+	// the given actor and request ID.
+	WithAudit(audit models.Audit) Repository
+
+	CreateUser(data *models.User) (*models.User, error)
+	UpdateUser(data *models.User) (*models.User, error)
+	DeactivateUser(id int) (*models.User, error)
+	GetUser(id int) (*models.User, error)
+	GetUsers() ([]*models.User, error)
+
+	CreateTrainer(data *models.Trainer) (*models.Trainer, error)
+	UpdateTrainer(data *models.Trainer) (*models.Trainer, error)
+	DeactivateTrainer(id int) (*models.Trainer, error)
+	GetTrainer(id int) (*models.Trainer, error)
+	GetTrainers() ([]*models.Trainer, error)
+
+	GetTrainerSettings(trainerID int) (*models.TrainerSettings, error)
+	SetTrainerSettings(settings *models.TrainerSettings) (*models.TrainerSettings, error)
+	GetTrainerWorkingHours(trainerID int) (models.WeeklySchedule, error)
+	SetTrainerWorkingHours(trainerID int, schedule models.WeeklySchedule) (models.WeeklySchedule, error)
+
+	CreateTimeOff(data *models.TimeOff) (*models.TimeOff, error)
+	UpdateTimeOff(data *models.TimeOff) (*models.TimeOff, error)
+	DeleteTimeOff(trainerID, id int) error
+	GetTimeOffByTrainerID(trainerID int, startsAt, endsAt time.Time) ([]*models.TimeOff, error)
+
+	CreateHoliday(data *models.Holiday) (*models.Holiday, error)
+	ImportHolidays(holidays []*models.Holiday) ([]*models.Holiday, error)
+	UpdateHoliday(data *models.Holiday) (*models.Holiday, error)
+	DeleteHoliday(id int) error
+	GetHolidays(from, to string) ([]*models.Holiday, error)
+
+	CreateResource(data *models.Resource) (*models.Resource, error)
+	UpdateResource(data *models.Resource) (*models.Resource, error)
+	DeactivateResource(id int) (*models.Resource, error)
+	GetResource(id int) (*models.Resource, error)
+	GetResources() ([]*models.Resource, error)
+
+	CreateAppointment(data *models.Appointment) (*models.Appointment, error)
+	GetAppointmentByID(id int) (*models.Appointment, error)
+	GetAppointmentDetails(id int) (*models.AppointmentDetails, error)
+	GetAppointmentsByTrainerID(trainerID int, startsAt, endsAt time.Time, page models.Page) ([]*models.Appointment, *models.Cursor, error)
+	GetAppointmentsByUserID(userID int, startsAt, endsAt time.Time, filter string, page models.Page) ([]*models.Appointment, *models.Cursor, error)
+	GetAppointmentsByResourceID(resourceID int, startsAt, endsAt time.Time, page models.Page) ([]*models.Appointment, *models.Cursor, error)
+	ExportAppointments(trainerID, userID int, startsAt, endsAt time.Time) ([]*models.Appointment, error)
+	ImportAppointments(appointments []*models.Appointment) ([]*models.Appointment, error)
+	CancelAppointment(id int) (*models.Appointment, error)
+	RescheduleAppointment(id int, startsAt, endsAt time.Time) (*models.Appointment, error)
+	GetAppointmentHistory(id int) ([]*models.AppointmentChange, error)
+
+	CreateAppointmentSeries(userID, trainerID int, startsAt, endsAt time.Time, recurrence *models.Recurrence) (*models.SeriesResult, error)
+	CancelFollowingAppointments(id int) ([]*models.Appointment, error)
+	RescheduleFollowingAppointments(id int, startsAt, endsAt time.Time) (*models.SeriesResult, error)
+
+	CreateWaitlistEntry(data *models.WaitlistEntry) (*models.WaitlistEntry, error)
+	GetWaitlistEntry(id int) (*models.WaitlistEntry, error)
+	GetWaitlistByTrainerID(trainerID int) ([]*models.WaitlistEntry, error)
+	AcceptWaitlistOffer(id int) (*models.Appointment, error)
+	CancelWaitlistEntry(id int) (*models.WaitlistEntry, error)
+	ExpireWaitlistOffers() error
+
+	GetTrainerAvailability(trainerID int, startsAt, endsAt time.Time, duration time.Duration, userID int, resourceIDs []int) (*[]models.OpenTimeslot, error)
+	GetAvailability(trainerIDs []int, startsAt, endsAt time.Time, duration time.Duration, userID int, resourceIDs []int, first int) ([]*models.AvailableTimeslot, error)
+}
+
+var _ Repository = (*Store)(nil)
+var _ Repository = (*MemoryStore)(nil)
+
+// Open returns the repository for the named backend. An empty name selects
+// SQLite, which is opened with opts.
+func Open(backend string, opts Options) (Repository, error) {
+	switch backend {
+	case "", BackendSQLite:
+		return NewStore(opts)
+	case BackendMemory:
+		return NewMemoryStore(), nil
+	}
+	return nil, fmt.Errorf("Unknown store backend: %s", backend)
+}
@@ -0,0 +1,371 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"future-app/models"
+	"strings"
+	"time"
+)
+
+var ErrResourceNotFound = errors.New("Resource not found")
+var ErrResourceInactive = errors.New("Resource is not active")
+var ErrResourceUnavailable = errors.New("Resource is not available")
+
+func (s *Store) CreateResource(data *models.Resource) (*models.Resource, error) {
+	query := `
+	INSERT INTO resources (name, kind, active)
+	VALUES ($1, $2, $3)
+	`
+
+	res, err := s.DB.Exec(query, data.Name, data.Kind, data.Active)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data.ID = int(id)
+	return data, nil
+}
+
+func (s *Store) UpdateResource(data *models.Resource) (*models.Resource, error) {
+	query := `
+	UPDATE resources
+	SET name = $1, kind = $2, active = $3
+	WHERE id = $4
+	`
+
+	res, err := s.DB.Exec(query, data.Name, data.Kind, data.Active, data.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrResourceNotFound
+	}
+
+	return data, nil
+}
+
+// DeactivateResource keeps the resource so existing appointments still
+// reference it, but blocks new bookings that require it.
+func (s *Store) DeactivateResource(id int) (*models.Resource, error) {
+	resource, err := s.GetResource(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.DB.Exec(`UPDATE resources SET active = FALSE WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	resource.Active = false
+	return resource, nil
+}
+
+func (s *Store) GetResource(id int) (*models.Resource, error) {
+	return getResource(s.DB, id)
+}
+
+func getResource(q querier, id int) (*models.Resource, error) {
+	var resource models.Resource
+
+	query := `
+	SELECT id, name, kind, active
+	FROM resources
+	WHERE id = $1
+	`
+
+	err := q.QueryRow(query, id).Scan(
+		&resource.ID,
+		&resource.Name,
+		&resource.Kind,
+		&resource.Active,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrResourceNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &resource, nil
+}
+
+func (s *Store) GetResources() ([]*models.Resource, error) {
+	resources := make([]*models.Resource, 0)
+
+	query := `
+	SELECT id, name, kind, active
+	FROM resources
+	ORDER BY id ASC
+	`
+
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var resource models.Resource
+		if err := rows.Scan(
+			&resource.ID,
+			&resource.Name,
+			&resource.Kind,
+			&resource.Active,
+		); err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, &resource)
+	}
+
+	return resources, rows.Err()
+}
+
+// resourcePlaceholders returns the IN list for the resource IDs, numbered from
+// start, along with the matching arguments.
+func resourcePlaceholders(resourceIDs []int, start int) (string, []any) {
+	placeholders := make([]string, len(resourceIDs))
+	args := make([]any, len(resourceIDs))
+	for i, resourceID := range resourceIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+start)
+		args[i] = resourceID
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// validateResources checks that every resource the appointment requires exists,
+// is active and is not held by another overlapping appointment. Attendees of
+// the same group session share its resources.
+func validateResources(q querier, appointment *models.Appointment, resourceIDs []int) error {
+	if len(resourceIDs) == 0 {
+		return nil
+	}
+
+	for _, resourceID := range resourceIDs {
+		resource, err := getResource(q, resourceID)
+		if err != nil {
+			return err
+		}
+
+		if !resource.Active {
+			return ErrResourceInactive
+		}
+	}
+
+	// INFO: Placeholders are numbered in order of appearance, so the IN list comes last
+	in, inArgs := resourcePlaceholders(resourceIDs, 5)
+
+	query := `
+	SELECT COUNT(*)
+	FROM appointment_resources r
+	JOIN appointments a ON a.id = r.appointment_id
+	WHERE a.ends_at > $1 AND a.starts_at < $2
+	AND a.status = 'scheduled' AND a.id != $3
+	AND NOT (
+		a.trainer_id = $4
+		AND a.starts_at = $1 AND a.ends_at = $2
+	)
+	AND r.resource_id IN (` + in + `)
+	`
+
+	args := []any{
+		formatTime(appointment.StartsAt),
+		formatTime(appointment.EndsAt),
+		appointment.ID,
+		appointment.TrainerID,
+	}
+
+	var count int
+	if err := q.QueryRow(query, append(args, inArgs...)...).Scan(&count); err != nil {
+		return err
+	}
+
+	if count != 0 {
+		return ErrResourceUnavailable
+	}
+
+	return nil
+}
+
+func setAppointmentResources(q querier, appointmentID int, resourceIDs []int) error {
+	for _, resourceID := range resourceIDs {
+		if _, err := q.Exec(
+			`INSERT INTO appointment_resources (appointment_id, resource_id) VALUES ($1, $2)`,
+			appointmentID,
+			resourceID,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getAppointmentResourceIDs(q querier, appointmentID int) ([]int, error) {
+	resourceIDs := make([]int, 0)
+
+	rows, err := q.Query(
+		`SELECT resource_id FROM appointment_resources WHERE appointment_id = $1 ORDER BY resource_id ASC`,
+		appointmentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var resourceID int
+		if err := rows.Scan(&resourceID); err != nil {
+			return nil, err
+		}
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(resourceIDs) == 0 {
+		return nil, nil
+	}
+
+	return resourceIDs, nil
+}
+
+// GetAppointmentsByResourceID returns a page of the scheduled appointments
+// that require the resource, along with the cursor for the next page. Each
+// appointment is returned in its trainer's time zone.
+func (s *Store) GetAppointmentsByResourceID(resourceID int, startsAt, endsAt time.Time, page models.Page) ([]*models.Appointment, *models.Cursor, error) {
+	appointments := make([]*models.Appointment, 0)
+
+	query := `
+	SELECT id, user_id, trainer_id, starts_at, ends_at, status, series_id
+	FROM appointments
+	WHERE id IN (SELECT appointment_id FROM appointment_resources WHERE resource_id = $1)
+	AND status = 'scheduled'
+	`
+	args := []any{resourceID}
+
+	if !startsAt.IsZero() && !endsAt.IsZero() {
+		query += `AND ends_at >= $2 AND starts_at <= $3
+	`
+		args = append(args, formatTime(startsAt), formatTime(endsAt))
+	}
+
+	query, args = appendPage(query, args, page, false)
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var appointment models.Appointment
+		if err := rows.Scan(
+			&appointment.ID,
+			&appointment.UserID,
+			&appointment.TrainerID,
+			&appointment.StartsAt,
+			&appointment.EndsAt,
+			&appointment.Status,
+			&appointment.SeriesID,
+		); err != nil {
+			return nil, nil, err
+		}
+
+		appointments = append(appointments, &appointment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	appointments, next := nextPage(appointments, page)
+
+	for _, appointment := range appointments {
+		appointment.ResourceIDs, err = getAppointmentResourceIDs(s.DB, appointment.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := inTrainerTimeZones(s.DB, appointments); err != nil {
+		return nil, nil, err
+	}
+
+	return appointments, next, nil
+}
+
+// getResourceBusyTimeslots returns the scheduled appointments overlapping the
+// window that require any of the resources, keyed by the trainer holding them.
+func (s *Store) getResourceBusyTimeslots(resourceIDs []int, startsAt, endsAt time.Time) (map[int][]models.Timeslot, error) {
+	busy := make(map[int][]models.Timeslot)
+	if len(resourceIDs) == 0 {
+		return busy, nil
+	}
+
+	// INFO: Placeholders are numbered in order of appearance, so the window must come first
+	in, inArgs := resourcePlaceholders(resourceIDs, 3)
+
+	query := `
+	SELECT DISTINCT a.id, a.trainer_id, a.starts_at, a.ends_at
+	FROM appointments a
+	JOIN appointment_resources r ON r.appointment_id = a.id
+	WHERE a.ends_at > $1 AND a.starts_at < $2
+	AND a.status = 'scheduled' AND r.resource_id IN (` + in + `)
+	`
+
+	args := []any{formatTime(startsAt), formatTime(endsAt)}
+
+	rows, err := s.DB.Query(query, append(args, inArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, trainerID int
+		var timeslot models.Timeslot
+		if err := rows.Scan(&id, &trainerID, &timeslot.StartsAt, &timeslot.EndsAt); err != nil {
+			return nil, err
+		}
+
+		busy[trainerID] = append(busy[trainerID], timeslot)
+	}
+
+	return busy, rows.Err()
+}
+
+// resourceBusyFor returns the resource bookings that block the trainer. The
+// trainer's own bookings are left out since they are already counted as booked,
+// which lets attendees join a group session that holds the resources.
+func resourceBusyFor(resourceBusy map[int][]models.Timeslot, trainerID int) []models.Timeslot {
+	busy := make([]models.Timeslot, 0)
+	for holderID, timeslots := range resourceBusy {
+		if holderID != trainerID {
+			busy = append(busy, timeslots...)
+		}
+	}
+	return busy
+}
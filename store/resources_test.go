@@ -0,0 +1,198 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResources(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	room, err := store.CreateResource(&models.Resource{Name: "Studio A", Kind: models.ResourceKindRoom, Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rower, err := store.CreateResource(&models.Resource{Name: "Rowing machine", Kind: models.ResourceKindEquipment, Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Create and update resources", func(t *testing.T) {
+		resources, err := store.GetResources()
+		assert.NoError(t, err)
+		assert.Len(t, resources, 2)
+
+		rower.Name = "Rower"
+		updated, err := store.UpdateResource(rower)
+		assert.NoError(t, err)
+		assert.Equal(t, "Rower", updated.Name)
+
+		_, err = store.UpdateResource(&models.Resource{ID: 999999, Name: "Studio B", Kind: models.ResourceKindRoom})
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+
+	var booked *models.Appointment
+
+	t.Run("Book an appointment with resources", func(t *testing.T) {
+		appointment := getTestAppointment()
+		appointment.ResourceIDs = []int{room.ID, rower.ID}
+
+		booked, err = store.CreateAppointment(appointment)
+		assert.NoError(t, err)
+
+		saved, err := store.GetAppointmentByID(booked.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{room.ID, rower.ID}, saved.ResourceIDs)
+	})
+
+	t.Run("Taken resources cannot be booked by another trainer", func(t *testing.T) {
+		appointment := getTestAppointment()
+		appointment.UserID = 2
+		appointment.TrainerID = 2
+		appointment.StartsAt = appointment.StartsAt.Add(time.Minute * 15)
+		appointment.EndsAt = appointment.EndsAt.Add(time.Minute * 15)
+		appointment.ResourceIDs = []int{rower.ID}
+
+		_, err := store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrResourceUnavailable)
+
+		// INFO: The failed booking is rolled back
+		appointments, _, err := store.GetAppointmentsByTrainerID(2, time.Time{}, time.Time{}, models.Page{})
+		assert.NoError(t, err)
+		assert.Empty(t, appointments)
+
+		appointment.ResourceIDs = nil
+		_, err = store.CreateAppointment(appointment)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Group sessions share their resources", func(t *testing.T) {
+		_, err := store.SetTrainerSettings(&models.TrainerSettings{TrainerID: 1, TimeZone: models.DEFAULT_TZ, Durations: models.DefaultDurations, Capacity: 2})
+		assert.NoError(t, err)
+
+		appointment := getTestAppointment()
+		appointment.UserID = 3
+		appointment.ResourceIDs = []int{room.ID}
+
+		_, err = store.CreateAppointment(appointment)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rescheduling checks resources", func(t *testing.T) {
+		other := getTestAppointment()
+		other.UserID = 4
+		other.TrainerID = 3
+		other.StartsAt = other.StartsAt.AddDate(0, 0, 3)
+		other.EndsAt = other.EndsAt.AddDate(0, 0, 3)
+		other.ResourceIDs = []int{room.ID}
+
+		_, err := store.CreateAppointment(other)
+		assert.NoError(t, err)
+
+		_, err = store.RescheduleAppointment(booked.ID, other.StartsAt, other.EndsAt)
+		assert.ErrorIs(t, err, ErrResourceUnavailable)
+	})
+
+	t.Run("Resource calendar", func(t *testing.T) {
+		appointments, next, err := store.GetAppointmentsByResourceID(room.ID, time.Time{}, time.Time{}, models.Page{Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 2)
+		assert.NotNil(t, next)
+
+		appointments, next, err = store.GetAppointmentsByResourceID(room.ID, time.Time{}, time.Time{}, models.Page{Limit: 2, Cursor: next})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 1)
+		assert.Nil(t, next)
+		assert.Equal(t, 3, appointments[0].TrainerID)
+
+		appointments, _, err = store.GetAppointmentsByResourceID(rower.ID, time.Time{}, time.Time{}, models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 1)
+		assert.Equal(t, booked.ID, appointments[0].ID)
+	})
+
+	t.Run("Deactivated and unknown resources cannot be booked", func(t *testing.T) {
+		_, err := store.DeactivateResource(rower.ID)
+		assert.NoError(t, err)
+
+		appointment := getTestAppointment()
+		appointment.UserID = 5
+		appointment.StartsAt = appointment.StartsAt.AddDate(0, 0, 7)
+		appointment.EndsAt = appointment.EndsAt.AddDate(0, 0, 7)
+		appointment.ResourceIDs = []int{rower.ID}
+
+		_, err = store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrResourceInactive)
+
+		appointment.ResourceIDs = []int{999999}
+		_, err = store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+}
+
+func TestGetAvailabilityWithResources(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	room, err := store.CreateResource(&models.Resource{Name: "Studio A", Kind: models.ResourceKindRoom, Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	// INFO: Trainer 1 holds the room from 9:00 to 9:30
+	if _, err := store.CreateAppointment(&models.Appointment{
+		UserID:      1,
+		TrainerID:   1,
+		StartsAt:    monday.Add(time.Hour * 9),
+		EndsAt:      monday.Add(time.Hour * 9).Add(time.Minute * 30),
+		ResourceIDs: []int{room.ID},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	hasStart := func(timeslots []models.OpenTimeslot, startsAt time.Time) bool {
+		for _, timeslot := range timeslots {
+			if timeslot.StartsAt.Equal(startsAt) {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("Other trainers cannot use the room", func(t *testing.T) {
+		timeslots, err := store.GetTrainerAvailability(2, monday, tuesday, time.Minute*30, 0, []int{room.ID})
+		assert.NoError(t, err)
+		assert.False(t, hasStart(*timeslots, monday.Add(time.Hour*9)))
+		assert.True(t, hasStart(*timeslots, monday.Add(time.Hour*9).Add(time.Minute*30)))
+
+		timeslots, err = store.GetTrainerAvailability(2, monday, tuesday, time.Minute*30, 0, nil)
+		assert.NoError(t, err)
+		assert.True(t, hasStart(*timeslots, monday.Add(time.Hour*9)))
+	})
+
+	t.Run("Multi-trainer search", func(t *testing.T) {
+		timeslots, err := store.GetAvailability([]int{1, 2, 3}, monday, tuesday, time.Minute*30, 0, []int{room.ID}, 0)
+		assert.NoError(t, err)
+
+		for _, timeslot := range timeslots {
+			if timeslot.StartsAt.Equal(monday.Add(time.Hour * 9)) {
+				t.Fatalf("expected 9:00 to be unavailable, got trainers %v", timeslot.TrainerIDs)
+			}
+		}
+	})
+}
@@ -0,0 +1,351 @@
+package store
+
+import (
+	"errors"
+	"future-app/models"
+	"sort"
+	"time"
+)
+
+var ErrAppointmentNotInSeries = errors.New("Appointment is not part of a series")
+
+// CreateAppointmentSeries books every occurrence of the recurrence that passes
+// validation. Occurrences that are invalid or already taken are reported as
+// conflicts instead of failing the whole series. If no occurrence can be
+// booked the series is not created and ErrTimeslotUnavailable is returned.
+func (s *Store) CreateAppointmentSeries(userID, trainerID int, startsAt, endsAt time.Time, recurrence *models.Recurrence) (*models.SeriesResult, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	settings, err := getTrainerSettings(tx, trainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := getTrainerWorkingHours(tx, trainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := settings.Location()
+
+	occurrences, err := recurrence.Occurrences(startsAt.In(loc), endsAt.In(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	series := &models.AppointmentSeries{
+		UserID:    userID,
+		TrainerID: trainerID,
+		RRule:     recurrence.String(),
+		StartsAt:  occurrences[0].StartsAt,
+		EndsAt:    occurrences[0].EndsAt,
+	}
+
+	query := `
+	INSERT INTO appointment_series (user_id, trainer_id, rrule, starts_at, ends_at)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+
+	res, err := tx.Exec(
+		query,
+		series.UserID,
+		series.TrainerID,
+		series.RRule,
+		formatTime(series.StartsAt),
+		formatTime(series.EndsAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	series.ID = int(id)
+
+	result := &models.SeriesResult{
+		Series:    series,
+		Booked:    make([]*models.Appointment, 0, len(occurrences)),
+		Conflicts: make([]models.SeriesConflict, 0),
+	}
+
+	for _, occurrence := range occurrences {
+		appointment, err := models.NewAppointment(userID, trainerID, occurrence.StartsAt, occurrence.EndsAt, settings, schedule)
+		if err == nil {
+			appointment.SeriesID = &series.ID
+			_, err = bookAppointment(tx, s.audit, appointment)
+
+			if err != nil && !isTimeslotConflict(err) {
+				return nil, err
+			}
+		}
+
+		if err != nil {
+			result.Conflicts = append(result.Conflicts, models.SeriesConflict{
+				StartsAt: occurrence.StartsAt,
+				EndsAt:   occurrence.EndsAt,
+				Reason:   err.Error(),
+			})
+			continue
+		}
+
+		result.Booked = append(result.Booked, appointment)
+	}
+
+	if len(result.Booked) == 0 {
+		return nil, ErrTimeslotUnavailable
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getFollowingAppointments returns the scheduled occurrences of a series that
+// start at or after the given time.
+func getFollowingAppointments(q querier, seriesID int, startsAt time.Time) ([]*models.Appointment, error) {
+	appointments := make([]*models.Appointment, 0)
+
+	query := `
+	SELECT id, user_id, trainer_id, starts_at, ends_at, status, series_id
+	FROM appointments
+	WHERE series_id = $1 AND status = 'scheduled'
+	AND starts_at >= $2
+	ORDER BY starts_at ASC
+	`
+
+	rows, err := q.Query(query, seriesID, formatTime(startsAt))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var appointment models.Appointment
+		if err := rows.Scan(
+			&appointment.ID,
+			&appointment.UserID,
+			&appointment.TrainerID,
+			&appointment.StartsAt,
+			&appointment.EndsAt,
+			&appointment.Status,
+			&appointment.SeriesID,
+		); err != nil {
+			return nil, err
+		}
+
+		appointments = append(appointments, &appointment)
+	}
+
+	return appointments, rows.Err()
+}
+
+func getSeriesAppointment(q querier, id int) (*models.Appointment, error) {
+	appointment, err := getAppointmentByID(q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.Status == models.AppointmentStatusCancelled {
+		return nil, ErrAppointmentCancelled
+	}
+
+	if appointment.SeriesID == nil {
+		return nil, ErrAppointmentNotInSeries
+	}
+
+	return appointment, nil
+}
+
+// CancelFollowingAppointments cancels the appointment and every later
+// scheduled occurrence in its series.
+func (s *Store) CancelFollowingAppointments(id int) ([]*models.Appointment, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err := getSeriesAppointment(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	appointments, err := getFollowingAppointments(tx, *existing.SeriesID, existing.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	before := make([]*models.Appointment, len(appointments))
+	for i, appointment := range appointments {
+		if before[i], err = getAppointmentByID(tx, appointment.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+	UPDATE appointments
+	SET status = $1
+	WHERE series_id = $2 AND status = 'scheduled'
+	AND starts_at >= $3
+	`
+
+	if _, err := tx.Exec(
+		query,
+		models.AppointmentStatusCancelled,
+		*existing.SeriesID,
+		formatTime(existing.StartsAt),
+	); err != nil {
+		return nil, err
+	}
+
+	for i, appointment := range appointments {
+		if err := recordAppointmentChange(tx, s.audit, before[i], appointment.ID); err != nil {
+			return nil, err
+		}
+
+		if err := releaseTimeslot(tx, s.audit, appointment.TrainerID, appointment.StartsAt, appointment.EndsAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	loc := existing.StartsAt.Location()
+	for i, appointment := range appointments {
+		appointment.Status = models.AppointmentStatusCancelled
+		appointments[i] = appointment.In(loc)
+	}
+
+	return appointments, nil
+}
+
+// RescheduleFollowingAppointments moves the appointment and every later
+// scheduled occurrence in its series by the same number of days, to the new
+// time of day and length. Occurrences that cannot be moved keep their current
+// timeslot and are reported as conflicts.
+func (s *Store) RescheduleFollowingAppointments(id int, startsAt, endsAt time.Time) (*models.SeriesResult, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err := getSeriesAppointment(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := getTrainerSettings(tx, existing.TrainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := getTrainerWorkingHours(tx, existing.TrainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	appointments, err := getFollowingAppointments(tx, *existing.SeriesID, existing.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := settings.Location()
+	startsAt = startsAt.In(loc)
+	duration := endsAt.Sub(startsAt)
+	days := daysBetween(existing.StartsAt.In(loc), startsAt)
+
+	// INFO: Move the occurrences furthest in the shift direction first so the
+	// series never conflicts with its own occurrences that have yet to move
+	if startsAt.After(existing.StartsAt) {
+		sort.SliceStable(appointments, func(i, j int) bool {
+			return appointments[i].StartsAt.After(appointments[j].StartsAt)
+		})
+	}
+
+	result := &models.SeriesResult{
+		Booked:    make([]*models.Appointment, 0, len(appointments)),
+		Conflicts: make([]models.SeriesConflict, 0),
+	}
+	released := make([]models.Timeslot, 0, len(appointments))
+
+	for _, current := range appointments {
+		date := current.StartsAt.In(loc)
+		occurrenceStartsAt := time.Date(date.Year(), date.Month(), date.Day()+days, startsAt.Hour(), startsAt.Minute(), 0, 0, loc)
+		occurrenceEndsAt := occurrenceStartsAt.Add(duration)
+
+		before, err := getAppointmentByID(tx, current.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		appointment, err := models.NewAppointment(current.UserID, current.TrainerID, occurrenceStartsAt, occurrenceEndsAt, settings, schedule)
+		if err == nil {
+			appointment.ID = current.ID
+			appointment.SeriesID = current.SeriesID
+			err = rescheduleAppointment(tx, appointment)
+
+			if err != nil && !isTimeslotConflict(err) {
+				return nil, err
+			}
+		}
+
+		if err == nil {
+			if err := recordAppointmentChange(tx, s.audit, before, current.ID); err != nil {
+				return nil, err
+			}
+			released = append(released, current.Timeslot())
+		}
+
+		if err != nil {
+			result.Conflicts = append(result.Conflicts, models.SeriesConflict{
+				StartsAt: occurrenceStartsAt,
+				EndsAt:   occurrenceEndsAt,
+				Reason:   err.Error(),
+			})
+			continue
+		}
+
+		result.Booked = append(result.Booked, appointment)
+	}
+
+	// INFO: Freed slots are released after every occurrence has moved so the
+	// waitlist cannot claim a slot the series is about to move into
+	for _, timeslot := range released {
+		if err := releaseTimeslot(tx, s.audit, existing.TrainerID, timeslot.StartsAt, timeslot.EndsAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result.Booked, func(i, j int) bool {
+		return result.Booked[i].StartsAt.Before(result.Booked[j].StartsAt)
+	})
+	sort.SliceStable(result.Conflicts, func(i, j int) bool {
+		return result.Conflicts[i].StartsAt.Before(result.Conflicts[j].StartsAt)
+	})
+
+	return result, nil
+}
+
+// daysBetween returns the number of calendar days from one date to another,
+// ignoring the time of day.
+func daysBetween(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
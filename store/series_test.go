@@ -0,0 +1,103 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppointmentSeries(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+	weekly := &models.Recurrence{Interval: 1, Count: 4}
+
+	single, err := store.CreateAppointment(&models.Appointment{
+		UserID:    2,
+		TrainerID: 1,
+		StartsAt:  monday.AddDate(0, 0, 7),
+		EndsAt:    monday.AddDate(0, 0, 7).Add(time.Minute * 30),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var series *models.SeriesResult
+
+	t.Run("Create series", func(t *testing.T) {
+		series, err = store.CreateAppointmentSeries(1, 1, monday, monday.Add(time.Hour), weekly)
+		assert.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;INTERVAL=1;COUNT=4", series.Series.RRule)
+
+		assert.Len(t, series.Booked, 3)
+		for _, appointment := range series.Booked {
+			assert.Equal(t, series.Series.ID, *appointment.SeriesID)
+		}
+
+		assert.Len(t, series.Conflicts, 1)
+		assert.Equal(t, monday.AddDate(0, 0, 7), series.Conflicts[0].StartsAt)
+		assert.Equal(t, ErrTimeslotUnavailable.Error(), series.Conflicts[0].Reason)
+	})
+
+	t.Run("Series with no available occurrences", func(t *testing.T) {
+		_, err := store.CreateAppointmentSeries(3, 1, monday, monday.Add(time.Hour), &models.Recurrence{Interval: 1, Count: 1})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+	})
+
+	t.Run("Reschedule this and following", func(t *testing.T) {
+		startsAt := monday.AddDate(0, 0, 15).Add(time.Hour) // Tuesday 10:00
+
+		res, err := store.RescheduleFollowingAppointments(series.Booked[1].ID, startsAt, startsAt.Add(time.Minute*30))
+		assert.NoError(t, err)
+		assert.Len(t, res.Conflicts, 0)
+		assert.Len(t, res.Booked, 2)
+		assert.Equal(t, startsAt, res.Booked[0].StartsAt)
+		assert.Equal(t, startsAt.AddDate(0, 0, 7), res.Booked[1].StartsAt)
+		assert.Equal(t, startsAt.AddDate(0, 0, 7).Add(time.Minute*30), res.Booked[1].EndsAt)
+
+		// INFO: Earlier occurrences are untouched
+		first, err := store.GetAppointmentByID(series.Booked[0].ID)
+		assert.NoError(t, err)
+		assert.Equal(t, monday, first.StartsAt)
+	})
+
+	t.Run("Reschedule onto the series' own occurrences", func(t *testing.T) {
+		res, err := store.CreateAppointmentSeries(3, 2, monday, monday.Add(time.Hour), &models.Recurrence{Interval: 1, Count: 3})
+		assert.NoError(t, err)
+
+		startsAt := monday.AddDate(0, 0, 7)
+		moved, err := store.RescheduleFollowingAppointments(res.Booked[0].ID, startsAt, startsAt.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, moved.Conflicts, 0)
+		assert.Len(t, moved.Booked, 3)
+		assert.Equal(t, startsAt, moved.Booked[0].StartsAt)
+	})
+
+	t.Run("Cancel this and following", func(t *testing.T) {
+		cancelled, err := store.CancelFollowingAppointments(series.Booked[1].ID)
+		assert.NoError(t, err)
+		assert.Len(t, cancelled, 2)
+		for _, appointment := range cancelled {
+			assert.Equal(t, models.AppointmentStatusCancelled, appointment.Status)
+		}
+
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 2)
+	})
+
+	t.Run("Appointment not in a series", func(t *testing.T) {
+		_, err := store.CancelFollowingAppointments(single.ID)
+		assert.ErrorIs(t, err, ErrAppointmentNotInSeries)
+
+		_, err = store.RescheduleFollowingAppointments(single.ID, monday, monday.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrAppointmentNotInSeries)
+	})
+}
@@ -3,76 +3,337 @@ package store
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"future-app/models"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
+)
+
+var ErrAppointmentNotFound = errors.New("Appointment not found")
+var ErrAppointmentCancelled = errors.New("Appointment is already cancelled")
+var ErrAppointmentExists = errors.New("Appointment already exists")
+var ErrTimeslotUnavailable = errors.New("Timeslot is not available")
+var ErrEmailTaken = errors.New("Email is already in use")
+
+// Filters for GetAppointmentsByUserID. Without a filter every scheduled
+// appointment is returned.
+const (
+	AppointmentFilterUpcoming  = "upcoming"
+	AppointmentFilterPast      = "past"
+	AppointmentFilterCancelled = "cancelled"
 )
 
 type Store struct {
-	DB *sql.DB
+	DB    *sql.DB
+	audit models.Audit
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx so lookups can be shared
+// between standalone calls and transactions.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
 }
 
-func NewStore() (*Store, error) {
-	db, err := sql.Open("sqlite3", "./store.db")
+// NewStore opens the SQLite database described by opts and checks that it can
+// be reached with those settings.
+func NewStore(opts Options) (*Store, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", opts.dataSourceName())
 	if err != nil {
 		return nil, err
 	}
+
+	if opts.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	return &Store{DB: db}, nil
 }
 
 func NewTestStore() (*Store, error) {
-	db, err := sql.Open("sqlite3", ":memory:")
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=1")
 	if err != nil {
 		return nil, err
 	}
+	// INFO: Every connection to :memory: opens a separate database
+	db.SetMaxOpenConns(1)
 	return &Store{DB: db}, nil
 }
 
+// Init brings the schema up to date by applying every pending migration.
 func (s *Store) Init() error {
-	return s.createAppointmentTable()
+	_, err := s.MigrateUp(0)
+	return err
+}
+
+func (s *Store) Close() {
+	s.DB.Close()
+}
+
+// formatTime is the storage format for every timestamp column. Times are kept
+// in UTC at second precision so they sort and compare as plain text, which
+// lets range queries use the indexes on starts_at.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
 }
 
-func (s *Store) createAppointmentTable() error {
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+func (s *Store) CreateAppointment(data *models.Appointment) (*models.Appointment, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	appointment, err := bookAppointment(tx, s.audit, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+// ImportAppointments inserts the appointments as they are, in one
+// transaction, so that an export can be seeded back into another database.
+// The booking rules are not applied, but the users and trainers must exist.
+// Appointments keep their IDs and the import fails with ErrAppointmentExists
+// if one is taken; an appointment without an ID gets the next one. Each
+// creation is recorded in the appointment's history. Series and resources are
+// not imported.
+func (s *Store) ImportAppointments(appointments []*models.Appointment) ([]*models.Appointment, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `
-    CREATE TABLE IF NOT EXISTS appointments (
-        id INTEGER PRIMARY KEY AUTOINCREMENT,
-        user_id INTEGER NOT NULL,
-        trainer_id INTEGER NOT NULL,
-        starts_at DATETIME NOT NULL,
-        ends_at DATETIME NOT NULL
-    );
-    `
-
-	if _, err := s.DB.Exec(query); err != nil {
+	INSERT INTO appointments (id, user_id, trainer_id, starts_at, ends_at, status)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for _, appointment := range appointments {
+		if _, err := getUser(tx, appointment.UserID); err != nil {
+			return nil, err
+		}
+
+		if _, err := getTrainer(tx, appointment.TrainerID); err != nil {
+			return nil, err
+		}
+
+		if appointment.Status == "" {
+			appointment.Status = models.AppointmentStatusScheduled
+		}
+		appointment.SeriesID = nil
+		appointment.ResourceIDs = nil
+
+		// INFO: A NULL ID lets SQLite assign the next one
+		var id any
+		if appointment.ID != 0 {
+			_, err := getAppointmentByID(tx, appointment.ID)
+			if err == nil {
+				return nil, ErrAppointmentExists
+			}
+			if !errors.Is(err, ErrAppointmentNotFound) {
+				return nil, err
+			}
+			id = appointment.ID
+		}
+
+		res, err := tx.Exec(
+			query,
+			id,
+			appointment.UserID,
+			appointment.TrainerID,
+			formatTime(appointment.StartsAt),
+			formatTime(appointment.EndsAt),
+			appointment.Status,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		inserted, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		appointment.ID = int(inserted)
+
+		if err := recordAppointmentChange(tx, s.audit, nil, appointment.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// validateParticipants checks that both the user and the trainer exist and
+// are active.
+func validateParticipants(q querier, userID, trainerID int) error {
+	if err := validateActiveUser(q, userID); err != nil {
 		return err
 	}
 
-	return nil
+	return validateActiveTrainer(q, trainerID)
 }
 
-func (s *Store) Close() {
-	s.DB.Close()
+// isParticipantError reports whether an error came from validateParticipants.
+func isParticipantError(err error) bool {
+	return errors.Is(err, ErrUserNotFound) ||
+		errors.Is(err, ErrUserInactive) ||
+		errors.Is(err, ErrTrainerNotFound) ||
+		errors.Is(err, ErrTrainerInactive)
 }
 
-func (s *Store) CreateAppointment(data *models.Appointment) (*models.Appointment, error) {
+// bookAppointment creates the appointment if the trainer and every resource it
+// requires are available, and records it in the appointment's history.
+func bookAppointment(q querier, audit models.Audit, data *models.Appointment) (*models.Appointment, error) {
+	if err := validateParticipants(q, data.UserID, data.TrainerID); err != nil {
+		return nil, err
+	}
+
+	if err := validateTrainerTimeOff(q, data); err != nil {
+		return nil, err
+	}
+
+	if err := validateHolidays(q, data); err != nil {
+		return nil, err
+	}
+
+	if err := validateResources(q, data, data.ResourceIDs); err != nil {
+		return nil, err
+	}
+
+	if data.Status != models.AppointmentStatusCancelled {
+		if err := validateWaitlistHold(q, data); err != nil {
+			return nil, err
+		}
+	}
+
+	appointment, err := createAppointment(q, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setAppointmentResources(q, appointment.ID, appointment.ResourceIDs); err != nil {
+		return nil, err
+	}
+
+	if err := recordAppointmentChange(q, audit, nil, appointment.ID); err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+// isTimeslotConflict reports whether a booking failed because its timeslot is
+// taken, as opposed to a storage error that should abort the transaction.
+func isTimeslotConflict(err error) bool {
+	return errors.Is(err, ErrTimeslotUnavailable) ||
+		errors.Is(err, ErrTrainerTimeOff) ||
+		errors.Is(err, ErrHoliday) ||
+		errors.Is(err, ErrResourceUnavailable)
+}
+
+// createAppointment inserts the appointment only if the user has no
+// overlapping scheduled appointment and the trainer has a seat open. Group
+// sessions only share a timeslot when it matches exactly, and the trainer's
+// other sessions must leave room for their buffers. The check and the insert
+// run as a single statement so concurrent bookings cannot both succeed.
+func createAppointment(q querier, data *models.Appointment) (*models.Appointment, error) {
+	settings, err := getTrainerSettings(q, data.TrainerID)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
-	INSERT INTO appointments (user_id, trainer_id, starts_at, ends_at)
-	VALUES ($1, $2, $3, $4)
+	INSERT INTO appointments (user_id, trainer_id, starts_at, ends_at, status, series_id)
+	SELECT $1, $2, $3, $4, $5, $6
+	WHERE $5 != 'scheduled' OR NOT EXISTS (
+		SELECT 1
+		FROM appointments
+		WHERE (user_id = $1 OR trainer_id = $2)
+		AND status = 'scheduled'
+		AND (
+			(user_id = $1 AND ends_at > $3 AND starts_at < $4)
+			OR (
+				trainer_id = $2
+				AND ends_at > $7 AND starts_at < $8
+				AND (
+					starts_at != $3 OR ends_at != $4
+					OR (
+						SELECT COUNT(*)
+						FROM appointments
+						WHERE trainer_id = $2 AND status = 'scheduled'
+						AND starts_at = $3 AND ends_at = $4
+					) >= $9
+				)
+			)
+		)
+	)
 	`
 
-	res, err := s.DB.Exec(
+	if data.Status == "" {
+		data.Status = models.AppointmentStatusScheduled
+	}
+
+	buffered := bufferedTimeslot(data.Timeslot(), settings)
+
+	res, err := q.Exec(
 		query,
 		data.UserID,
 		data.TrainerID,
-		data.StartsAt.Format(time.RFC3339),
-		data.EndsAt.Format(time.RFC3339),
+		formatTime(data.StartsAt),
+		formatTime(data.EndsAt),
+		data.Status,
+		data.SeriesID,
+		formatTime(buffered.StartsAt),
+		formatTime(buffered.EndsAt),
+		settings.Seats(),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	count, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrTimeslotUnavailable
+	}
+
 	id, err := res.LastInsertId()
 
 	if err != nil {
@@ -83,69 +344,237 @@ func (s *Store) CreateAppointment(data *models.Appointment) (*models.Appointment
 	return data, nil
 }
 
+// bufferedTimeslot widens the timeslot by the trainer's buffer gap on both
+// sides. Any other session of the trainer overlapping it is too close.
+func bufferedTimeslot(timeslot models.Timeslot, settings *models.TrainerSettings) models.Timeslot {
+	gap := settings.BufferGap()
+	return models.NewTimeslot(timeslot.StartsAt.Add(-gap), timeslot.EndsAt.Add(gap))
+}
+
 func (s *Store) ValidateAvailableTimeslot(data *models.Appointment) error {
+	return validateAvailableTimeslot(s.DB, data)
+}
+
+func validateAvailableTimeslot(q querier, data *models.Appointment) error {
 	var count int
 
+	settings, err := getTrainerSettings(q, data.TrainerID)
+	if err != nil {
+		return err
+	}
+
 	query := `
 	SELECT COUNT(*)
 	FROM appointments
-	WHERE (user_id = $1 OR trainer_id = $2) AND starts_at = $3 AND ends_at = $4
+	WHERE (user_id = $1 OR trainer_id = $2)
+	AND status = 'scheduled'
+	AND (
+		(user_id = $1 AND ends_at > $3 AND starts_at < $4)
+		OR (
+			trainer_id = $2
+			AND ends_at > $5 AND starts_at < $6
+			AND (
+				starts_at != $3 OR ends_at != $4
+				OR (
+					SELECT COUNT(*)
+					FROM appointments
+					WHERE trainer_id = $2 AND status = 'scheduled' AND id != $7
+					AND starts_at = $3 AND ends_at = $4
+				) >= $8
+			)
+		)
+	)
+	AND id != $7
 	`
 
-	if err := s.DB.QueryRow(
+	buffered := bufferedTimeslot(data.Timeslot(), settings)
+
+	if err := q.QueryRow(
 		query,
 		data.UserID,
 		data.TrainerID,
-		data.StartsAt.Format(time.RFC3339),
-		data.EndsAt.Format(time.RFC3339),
+		formatTime(data.StartsAt),
+		formatTime(data.EndsAt),
+		formatTime(buffered.StartsAt),
+		formatTime(buffered.EndsAt),
+		data.ID,
+		settings.Seats(),
 	).Scan(&count); err != nil {
 		return err
 	}
 
 	if count != 0 {
-		return errors.New("Timeslot is not available")
+		return ErrTimeslotUnavailable
 	}
 
-	return nil
+	return validateWaitlistHold(q, data)
 }
 
-func (s *Store) GetAppointmentsByTrainerID(trainerID int, startsAt, endsAt time.Time) ([]*models.Appointment, error) {
+// GetAppointmentsByTrainerID returns a page of the trainer's scheduled
+// appointments along with the cursor for the next page. A zero Page returns
+// every appointment.
+func (s *Store) GetAppointmentsByTrainerID(trainerID int, startsAt, endsAt time.Time, page models.Page) ([]*models.Appointment, *models.Cursor, error) {
 	appointments := make([]*models.Appointment, 0)
 
-	var rows *sql.Rows
-	var err error
+	settings, err := s.GetTrainerSettings(trainerID)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if startsAt.IsZero() || endsAt.IsZero() {
-		query := `
-		SELECT id, user_id, trainer_id, starts_at, ends_at
-		FROM appointments
-		WHERE trainer_id = $1
-		ORDER BY starts_at ASC
-		`
-		rows, err = s.DB.Query(
-			query,
-			trainerID,
-		)
-	} else {
-		query := `
-		SELECT id, user_id, trainer_id, starts_at, ends_at
-		FROM appointments
-		WHERE trainer_id = $1
-		AND (
-			(starts_at >= $2 AND starts_at <= $3)
-			OR (ends_at >= $2 AND ends_at <= $3)
-			OR (starts_at >= $2 AND ends_at <= $3)
-		)
-		ORDER BY starts_at ASC
-		`
-		rows, err = s.DB.Query(
-			query,
-			trainerID,
-			startsAt.Format(time.RFC3339),
-			endsAt.Format(time.RFC3339),
-		)
+	query := `
+	SELECT id, user_id, trainer_id, starts_at, ends_at, status, series_id
+	FROM appointments
+	WHERE trainer_id = $1 AND status = 'scheduled'
+	`
+	args := []any{trainerID}
+
+	if !startsAt.IsZero() && !endsAt.IsZero() {
+		query += `AND ends_at >= $2 AND starts_at <= $3
+	`
+		args = append(args, formatTime(startsAt), formatTime(endsAt))
+	}
+
+	query, args = appendPage(query, args, page, false)
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var appointment models.Appointment
+		if err := rows.Scan(
+			&appointment.ID,
+			&appointment.UserID,
+			&appointment.TrainerID,
+			&appointment.StartsAt,
+			&appointment.EndsAt,
+			&appointment.Status,
+			&appointment.SeriesID,
+		); err != nil {
+			return nil, nil, err
+		}
+
+		appointments = append(appointments, appointment.In(settings.Location()))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	appointments, next := nextPage(appointments, page)
+	return appointments, next, nil
+}
+
+// GetAppointmentsByUserID returns a page of the user's appointments, optionally
+// limited to those overlapping the window, along with the cursor for the next
+// page. Each appointment is returned in its trainer's time zone.
+func (s *Store) GetAppointmentsByUserID(userID int, startsAt, endsAt time.Time, filter string, page models.Page) ([]*models.Appointment, *models.Cursor, error) {
+	appointments := make([]*models.Appointment, 0)
+
+	query := `
+	SELECT id, user_id, trainer_id, starts_at, ends_at, status, series_id
+	FROM appointments
+	WHERE user_id = $1
+	`
+	args := []any{userID}
+
+	if !startsAt.IsZero() && !endsAt.IsZero() {
+		query += `AND ends_at >= $2 AND starts_at <= $3
+	`
+		args = append(args, formatTime(startsAt), formatTime(endsAt))
+	}
+
+	// INFO: Placeholders are numbered in order of appearance, so now is always the last one
+	now := fmt.Sprintf("$%d", len(args)+1)
+	desc := false
+
+	switch filter {
+	case AppointmentFilterUpcoming:
+		query += `AND status = 'scheduled' AND ends_at > ` + now + `
+	`
+		args = append(args, formatTime(time.Now()))
+	case AppointmentFilterPast:
+		query += `AND status = 'scheduled' AND ends_at <= ` + now + `
+	`
+		args = append(args, formatTime(time.Now()))
+		desc = true
+	case AppointmentFilterCancelled:
+		query += `AND status = 'cancelled'
+	`
+	default:
+		query += `AND status = 'scheduled'
+	`
+	}
+
+	query, args = appendPage(query, args, page, desc)
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var appointment models.Appointment
+		if err := rows.Scan(
+			&appointment.ID,
+			&appointment.UserID,
+			&appointment.TrainerID,
+			&appointment.StartsAt,
+			&appointment.EndsAt,
+			&appointment.Status,
+			&appointment.SeriesID,
+		); err != nil {
+			return nil, nil, err
+		}
+
+		appointments = append(appointments, &appointment)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	appointments, next := nextPage(appointments, page)
+
+	if err := inTrainerTimeZones(s.DB, appointments); err != nil {
+		return nil, nil, err
+	}
+
+	return appointments, next, nil
+}
+
+// ExportAppointments returns every appointment, cancelled ones included,
+// ordered by ID. ImportAppointments keeps the IDs, so an export can only be
+// imported into a database that holds none of them. A zero trainerID or userID
+// matches everyone and a zero bound leaves that end of the range open. Each
+// appointment is returned in its trainer's time zone.
+func (s *Store) ExportAppointments(trainerID, userID int, startsAt, endsAt time.Time) ([]*models.Appointment, error) {
+	appointments := make([]*models.Appointment, 0)
+
+	var from, to string
+	if !startsAt.IsZero() {
+		from = formatTime(startsAt)
+	}
+	if !endsAt.IsZero() {
+		to = formatTime(endsAt)
+	}
+
+	query := `
+	SELECT id, user_id, trainer_id, starts_at, ends_at, status, series_id
+	FROM appointments
+	WHERE ($1 = 0 OR trainer_id = $1)
+	AND ($2 = 0 OR user_id = $2)
+	AND ($3 = '' OR ends_at >= $3)
+	AND ($4 = '' OR starts_at <= $4)
+	ORDER BY id ASC
+	`
+
+	rows, err := s.DB.Query(query, trainerID, userID, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -160,48 +589,502 @@ func (s *Store) GetAppointmentsByTrainerID(trainerID int, startsAt, endsAt time.
 			&appointment.TrainerID,
 			&appointment.StartsAt,
 			&appointment.EndsAt,
+			&appointment.Status,
+			&appointment.SeriesID,
 		); err != nil {
 			return nil, err
 		}
 
-		appointment.StartsAt = models.ConvertToFixedTZ(appointment.StartsAt)
-		appointment.EndsAt = models.ConvertToFixedTZ(appointment.EndsAt)
-
 		appointments = append(appointments, &appointment)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := inTrainerTimeZones(s.DB, appointments); err != nil {
+		return nil, err
+	}
+
 	return appointments, nil
 }
 
-func (s *Store) GetTrainerAvailability(trainerID int, startsAt, endsAt time.Time) (*[]models.Timeslot, error) {
-	appointments, err := s.GetAppointmentsByTrainerID(trainerID, startsAt, endsAt)
+// inTrainerTimeZones converts each appointment to its trainer's time zone,
+// looking up the settings of every trainer once.
+func inTrainerTimeZones(q querier, appointments []*models.Appointment) error {
+	locations := make(map[int]*time.Location)
+	for i, appointment := range appointments {
+		loc, ok := locations[appointment.TrainerID]
+		if !ok {
+			settings, err := getTrainerSettings(q, appointment.TrainerID)
+			if err != nil {
+				return err
+			}
+			loc = settings.Location()
+			locations[appointment.TrainerID] = loc
+		}
+
+		appointments[i] = appointment.In(loc)
+	}
+
+	return nil
+}
+
+// appendPage adds the keyset condition and ordering for page to a listing
+// query. One row past the limit is fetched so nextPage can tell whether
+// another page follows.
+func appendPage(query string, args []any, page models.Page, desc bool) (string, []any) {
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+
+	if page.Cursor != nil {
+		n := len(args) + 1
+		query += fmt.Sprintf(`AND (starts_at %[1]s $%[2]d OR (starts_at = $%[2]d AND id %[1]s $%[3]d))
+	`, op, n, n+1)
+		args = append(args, formatTime(page.Cursor.StartsAt), page.Cursor.ID)
+	}
+
+	query += fmt.Sprintf(`ORDER BY starts_at %[1]s, id %[1]s
+	`, dir)
+
+	if page.Limit > 0 {
+		query += fmt.Sprintf(`LIMIT %d
+	`, page.Limit+1)
+	}
+
+	return query, args
+}
+
+// nextPage trims the extra row fetched by appendPage and returns the cursor
+// for the following page, or nil on the last one.
+func nextPage(appointments []*models.Appointment, page models.Page) ([]*models.Appointment, *models.Cursor) {
+	if page.Limit == 0 || len(appointments) <= page.Limit {
+		return appointments, nil
+	}
+
+	appointments = appointments[:page.Limit]
+	return appointments, models.CursorFor(appointments[len(appointments)-1])
+}
+
+func (s *Store) GetAppointmentByID(id int) (*models.Appointment, error) {
+	return getAppointmentByID(s.DB, id)
+}
+
+func getAppointmentByID(q querier, id int) (*models.Appointment, error) {
+	var appointment models.Appointment
+
+	query := `
+	SELECT id, user_id, trainer_id, starts_at, ends_at, status, series_id
+	FROM appointments
+	WHERE id = $1
+	`
+
+	err := q.QueryRow(query, id).Scan(
+		&appointment.ID,
+		&appointment.UserID,
+		&appointment.TrainerID,
+		&appointment.StartsAt,
+		&appointment.EndsAt,
+		&appointment.Status,
+		&appointment.SeriesID,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAppointmentNotFound
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	timeslots := make([]models.Timeslot, 0)
-	currAppIdx := 0
+	appointment.ResourceIDs, err = getAppointmentResourceIDs(q, appointment.ID)
+	if err != nil {
+		return nil, err
+	}
 
-	for date := startsAt; date.Before(endsAt); date = date.Add(24 * time.Hour) {
-		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
-			continue
-		}
+	settings, err := getTrainerSettings(q, appointment.TrainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment.In(settings.Location()), nil
+}
+
+// GetAppointmentDetails returns the appointment with a summary of its user and
+// trainer, in the trainer's time zone.
+func (s *Store) GetAppointmentDetails(id int) (*models.AppointmentDetails, error) {
+	var details models.AppointmentDetails
+
+	query := `
+	SELECT a.id, a.user_id, a.trainer_id, a.starts_at, a.ends_at, a.status, a.series_id,
+	u.id, u.name, u.active, t.id, t.name, t.active
+	FROM appointments a
+	JOIN users u ON u.id = a.user_id
+	JOIN trainers t ON t.id = a.trainer_id
+	WHERE a.id = $1
+	`
+
+	err := s.DB.QueryRow(query, id).Scan(
+		&details.ID,
+		&details.UserID,
+		&details.TrainerID,
+		&details.StartsAt,
+		&details.EndsAt,
+		&details.Status,
+		&details.SeriesID,
+		&details.User.ID,
+		&details.User.Name,
+		&details.User.Active,
+		&details.Trainer.ID,
+		&details.Trainer.Name,
+		&details.Trainer.Active,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAppointmentNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	details.ResourceIDs, err = getAppointmentResourceIDs(s.DB, details.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.GetTrainerSettings(details.TrainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return details.In(settings.Location()), nil
+}
+
+func (s *Store) CancelAppointment(id int) (*models.Appointment, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	appointment, err := getAppointmentByID(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.Status == models.AppointmentStatusCancelled {
+		return nil, ErrAppointmentCancelled
+	}
+
+	query := `
+	UPDATE appointments
+	SET status = $1
+	WHERE id = $2
+	`
+
+	if _, err := tx.Exec(query, models.AppointmentStatusCancelled, id); err != nil {
+		return nil, err
+	}
+
+	if err := recordAppointmentChange(tx, s.audit, appointment, id); err != nil {
+		return nil, err
+	}
+
+	if err := releaseTimeslot(tx, s.audit, appointment.TrainerID, appointment.StartsAt, appointment.EndsAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	appointment.Status = models.AppointmentStatusCancelled
+	return appointment, nil
+}
+
+func (s *Store) RescheduleAppointment(id int, startsAt, endsAt time.Time) (*models.Appointment, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err := getAppointmentByID(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.Status == models.AppointmentStatusCancelled {
+		return nil, ErrAppointmentCancelled
+	}
+
+	settings, err := getTrainerSettings(tx, existing.TrainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := getTrainerWorkingHours(tx, existing.TrainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment, err := models.NewAppointment(existing.UserID, existing.TrainerID, startsAt, endsAt, settings, schedule)
+	if err != nil {
+		return nil, err
+	}
+	appointment.ID = existing.ID
+	appointment.SeriesID = existing.SeriesID
+	appointment.ResourceIDs = existing.ResourceIDs
+
+	if err := rescheduleAppointment(tx, appointment); err != nil {
+		return nil, err
+	}
+
+	if err := recordAppointmentChange(tx, s.audit, existing, existing.ID); err != nil {
+		return nil, err
+	}
+
+	if err := releaseTimeslot(tx, s.audit, existing.TrainerID, existing.StartsAt, existing.EndsAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+// rescheduleAppointment moves an existing appointment to the appointment's
+// timeslot if the trainer has a seat open with room for their buffers, its
+// resources are free and the user is not double booked.
+func rescheduleAppointment(q querier, appointment *models.Appointment) error {
+	if err := validateParticipants(q, appointment.UserID, appointment.TrainerID); err != nil {
+		return err
+	}
+
+	if err := validateTrainerTimeOff(q, appointment); err != nil {
+		return err
+	}
+
+	if err := validateHolidays(q, appointment); err != nil {
+		return err
+	}
+
+	resourceIDs, err := getAppointmentResourceIDs(q, appointment.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := validateResources(q, appointment, resourceIDs); err != nil {
+		return err
+	}
+
+	if err := validateWaitlistHold(q, appointment); err != nil {
+		return err
+	}
+
+	settings, err := getTrainerSettings(q, appointment.TrainerID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	UPDATE appointments
+	SET starts_at = $1, ends_at = $2
+	WHERE id = $3 AND NOT EXISTS (
+		SELECT 1
+		FROM appointments
+		WHERE id != $3 AND (user_id = $4 OR trainer_id = $5)
+		AND status = 'scheduled'
+		AND (
+			(user_id = $4 AND ends_at > $1 AND starts_at < $2)
+			OR (
+				trainer_id = $5
+				AND ends_at > $6 AND starts_at < $7
+				AND (
+					starts_at != $1 OR ends_at != $2
+					OR (
+						SELECT COUNT(*)
+						FROM appointments
+						WHERE id != $3 AND trainer_id = $5 AND status = 'scheduled'
+						AND starts_at = $1 AND ends_at = $2
+					) >= $8
+				)
+			)
+		)
+	)
+	`
+
+	buffered := bufferedTimeslot(appointment.Timeslot(), settings)
+
+	res, err := q.Exec(
+		query,
+		formatTime(appointment.StartsAt),
+		formatTime(appointment.EndsAt),
+		appointment.ID,
+		appointment.UserID,
+		appointment.TrainerID,
+		formatTime(buffered.StartsAt),
+		formatTime(buffered.EndsAt),
+		settings.Seats(),
+	)
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrTimeslotUnavailable
+	}
+
+	return nil
+}
 
-		currentDate := time.Date(date.Year(), date.Month(), date.Day(), 8, 0, 0, 0, date.Location())
+// GetTrainerAvailability returns the trainer's timeslots in the window that
+// still have a seat open, counting seats held by another user's open waitlist
+// offer as taken. With a user ID, timeslots that overlap the user's own
+// appointments are left out as well, and with resource IDs so are timeslots in
+// which another trainer holds any of the resources.
+func (s *Store) GetTrainerAvailability(trainerID int, startsAt, endsAt time.Time, duration time.Duration, userID int, resourceIDs []int) (*[]models.OpenTimeslot, error) {
+	return trainerAvailability(s, trainerID, startsAt, endsAt, duration, userID, resourceIDs)
+}
+
+func trainerAvailability(s availabilitySource, trainerID int, startsAt, endsAt time.Time, duration time.Duration, userID int, resourceIDs []int) (*[]models.OpenTimeslot, error) {
+	settings, err := s.GetTrainerSettings(trainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.GetTrainerWorkingHours(trainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// INFO: Slots are generated for whole days, so bookings are fetched for whole days as well
+	firstDay := startsAt.In(settings.Location())
+	firstDay = time.Date(firstDay.Year(), firstDay.Month(), firstDay.Day(), 0, 0, 0, 0, firstDay.Location())
+	lastDay := endsAt.AddDate(0, 0, 1)
+
+	// INFO: Bookings just outside the window still count when their buffers reach into it
+	appointments, _, err := s.GetAppointmentsByTrainerID(trainerID, firstDay.Add(-settings.BufferGap()), lastDay.Add(settings.BufferGap()), models.Page{})
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := s.getHeldTimeslots([]int{trainerID}, userID, firstDay.Add(-settings.BufferGap()), lastDay.Add(settings.BufferGap()))
+	if err != nil {
+		return nil, err
+	}
+
+	timeOff, err := s.GetTimeOffByTrainerID(trainerID, firstDay, lastDay)
+	if err != nil {
+		return nil, err
+	}
+
+	userBusy, err := s.getUserBusyTimeslots(userID, firstDay, lastDay)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceBusy, err := s.getResourceBusyTimeslots(resourceIDs, firstDay, lastDay)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.GetHolidays(
+		firstDay.Format(models.HolidayDateFormat),
+		lastDay.In(settings.Location()).Format(models.HolidayDateFormat),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// INFO: Seats held by an open waitlist offer are taken like booked ones
+	booked := make([]models.Timeslot, 0, len(appointments)+len(held[trainerID]))
+	for _, appointment := range appointments {
+		booked = append(booked, appointment.Timeslot())
+	}
+	booked = append(booked, held[trainerID]...)
+
+	busy := make([]models.Timeslot, 0, len(timeOff)+len(holidays)+len(userBusy))
+	busy = append(busy, userBusy...)
+	busy = append(busy, resourceBusyFor(resourceBusy, trainerID)...)
+	for _, entry := range timeOff {
+		busy = append(busy, entry.Timeslot())
+	}
+	for _, holiday := range holidays {
+		busy = append(busy, holiday.On(settings.Location()))
+	}
+
+	timeslots := openTimeslots(schedule, busy, booked, settings, startsAt, endsAt, duration)
+
+	return &timeslots, nil
+}
 
-		for currentDate.Before(time.Date(date.Year(), date.Month(), date.Day(), 17, 0, 0, 0, date.Location())) {
-			if currAppIdx < len(appointments) && appointments[currAppIdx].StartsAt.Equal(currentDate) && appointments[currAppIdx].EndsAt.Equal(currentDate.Add(30*time.Minute)) {
-				currentDate = appointments[currAppIdx].EndsAt
-				currAppIdx += 1
-				continue
+// openTimeslots returns the timeslots within the working hours on each day of
+// the window that do not overlap anything busy and still have a seat open
+// alongside the booked appointments and their buffers.
+func openTimeslots(schedule models.WeeklySchedule, busy, booked []models.Timeslot, settings *models.TrainerSettings, startsAt, endsAt time.Time, duration time.Duration) []models.OpenTimeslot {
+	timeslots := make([]models.OpenTimeslot, 0)
+	loc := settings.Location()
+
+	// INFO: Days are stepped in the trainer's time zone so DST transitions keep wall-clock hours
+	for date := startsAt.In(loc); date.Before(endsAt); date = date.AddDate(0, 0, 1) {
+		for _, interval := range schedule.On(date) {
+			for currentDate := interval.StartsAt; !currentDate.Add(duration).After(interval.EndsAt); currentDate = currentDate.Add(models.SlotInterval) {
+				if overlapsAny(busy, currentDate, currentDate.Add(duration)) {
+					continue
+				}
+
+				timeslot := models.NewTimeslot(currentDate, currentDate.Add(duration))
+				seats := openSeats(booked, settings.Seats(), timeslot, bufferedTimeslot(timeslot, settings))
+				if seats == 0 {
+					continue
+				}
+
+				timeslots = append(timeslots, models.OpenTimeslot{
+					Timeslot: timeslot,
+					Seats:    seats,
+				})
 			}
+		}
+	}
 
-			timeslot := models.NewTimeslot(currentDate, currentDate.Add(30*time.Minute))
+	return timeslots
+}
 
-			timeslots = append(timeslots, timeslot)
-			currentDate = currentDate.Add(30 * time.Minute)
+func overlapsAny(busy []models.Timeslot, startsAt, endsAt time.Time) bool {
+	for _, timeslot := range busy {
+		if timeslot.Overlaps(startsAt, endsAt) {
+			return true
 		}
 	}
+	return false
+}
 
-	return &timeslots, nil
+// openSeats returns the seats left in the timeslot. Bookings only share a
+// timeslot that matches exactly, so any other booking overlapping the buffered
+// timeslot fills it.
+func openSeats(booked []models.Timeslot, capacity int, timeslot, buffered models.Timeslot) int {
+	seats := capacity
+	for _, booking := range booked {
+		if !booking.Overlaps(buffered.StartsAt, buffered.EndsAt) {
+			continue
+		}
+
+		if !booking.StartsAt.Equal(timeslot.StartsAt) || !booking.EndsAt.Equal(timeslot.EndsAt) {
+			return 0
+		}
+
+		seats--
+	}
+	return max(seats, 0)
 }
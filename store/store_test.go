@@ -1,7 +1,10 @@
 package store
 
 import (
+	"bytes"
+	"fmt"
 	"future-app/models"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,7 +12,7 @@ import (
 )
 
 func getTestAppointment() *models.Appointment {
-	tz := time.FixedZone(models.GLOBAL_TZ, models.GLOBAL_TZ_OFFSET)
+	tz := models.DefaultLocation()
 	startsAt := time.Date(2030, 7, 5, 8, 0, 0, 0, tz)
 	endsAt := startsAt.Add(time.Minute * 30)
 
@@ -31,9 +34,30 @@ func setupStore() (*Store, error) {
 		return nil, err
 	}
 
+	if err := seedParticipants(db); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
+// INFO: Tests book for users 1-10 and trainers 1-5
+func seedParticipants(db Repository) error {
+	for i := 1; i <= 10; i++ {
+		if _, err := db.CreateUser(&models.User{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i), Active: true}); err != nil {
+			return err
+		}
+	}
+
+	for i := 1; i <= 5; i++ {
+		if _, err := db.CreateTrainer(&models.Trainer{Name: fmt.Sprintf("Trainer %d", i), Email: fmt.Sprintf("trainer%d@example.com", i), Active: true}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func TestCreateAppointment(t *testing.T) {
 	store, err := setupStore()
 	if err != nil {
@@ -53,6 +77,215 @@ func TestCreateAppointment(t *testing.T) {
 	assert.Equal(t, appointment.EndsAt, createdAppointment.EndsAt)
 }
 
+func TestCreateAppointmentConflicts(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	t.Run("Concurrent bookings for the same timeslot", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(userID int) {
+				defer wg.Done()
+				appointment := getTestAppointment()
+				appointment.UserID = userID
+				_, err := store.CreateAppointment(appointment)
+				errs <- err
+			}(i + 1)
+		}
+
+		wg.Wait()
+		close(errs)
+
+		succeeded := 0
+		for err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+		}
+		assert.Equal(t, 1, succeeded)
+
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 1)
+	})
+
+	t.Run("Overlapping booking for the same user", func(t *testing.T) {
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{})
+		assert.NoError(t, err)
+
+		appointment := getTestAppointment()
+		appointment.UserID = appointments[0].UserID
+		appointment.TrainerID = 2
+		appointment.StartsAt = appointment.StartsAt.Add(time.Minute * 15)
+		appointment.EndsAt = appointment.EndsAt.Add(time.Minute * 15)
+
+		_, err = store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+	})
+
+	t.Run("Cancelled appointments do not conflict", func(t *testing.T) {
+		appointment := getTestAppointment()
+		appointment.UserID = 9
+		appointment.Status = models.AppointmentStatusCancelled
+
+		_, err := store.CreateAppointment(appointment)
+		assert.NoError(t, err)
+	})
+}
+
+func TestGroupSessions(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	_, err = store.SetTrainerSettings(&models.TrainerSettings{TrainerID: 1, TimeZone: models.DEFAULT_TZ, Durations: models.DefaultDurations, Capacity: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+	startsAt := monday.Add(time.Hour * 9)
+	endsAt := startsAt.Add(time.Hour)
+
+	seatsAt := func(startsAt time.Time) int {
+		timeslots, err := store.GetTrainerAvailability(1, monday, monday.Add(time.Hour*24), time.Hour, 0, nil)
+		assert.NoError(t, err)
+		for _, timeslot := range *timeslots {
+			if timeslot.StartsAt.Equal(startsAt) {
+				return timeslot.Seats
+			}
+		}
+		return 0
+	}
+
+	var first *models.Appointment
+
+	t.Run("Users share the session up to capacity", func(t *testing.T) {
+		assert.Equal(t, 3, seatsAt(startsAt))
+
+		for userID := 1; userID <= 3; userID++ {
+			appointment, err := store.CreateAppointment(&models.Appointment{UserID: userID, TrainerID: 1, StartsAt: startsAt, EndsAt: endsAt})
+			assert.NoError(t, err)
+			if first == nil {
+				first = appointment
+			}
+			assert.Equal(t, 3-userID, seatsAt(startsAt))
+		}
+
+		_, err := store.CreateAppointment(&models.Appointment{UserID: 4, TrainerID: 1, StartsAt: startsAt, EndsAt: endsAt})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+
+		err = store.ValidateAvailableTimeslot(&models.Appointment{UserID: 4, TrainerID: 1, StartsAt: startsAt, EndsAt: endsAt})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+	})
+
+	t.Run("Overlapping timeslots are not shared", func(t *testing.T) {
+		_, err := store.CreateAppointment(&models.Appointment{UserID: 5, TrainerID: 1, StartsAt: startsAt.Add(time.Minute * 30), EndsAt: endsAt.Add(time.Minute * 30)})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+		assert.Equal(t, 0, seatsAt(startsAt.Add(time.Minute*30)))
+	})
+
+	t.Run("Cancelling frees a seat", func(t *testing.T) {
+		_, err := store.CancelAppointment(first.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, seatsAt(startsAt))
+	})
+
+	t.Run("Rescheduling into a session with a seat open", func(t *testing.T) {
+		appointment, err := store.CreateAppointment(&models.Appointment{UserID: 6, TrainerID: 1, StartsAt: startsAt.Add(time.Hour * 3), EndsAt: endsAt.Add(time.Hour * 3)})
+		assert.NoError(t, err)
+
+		_, err = store.RescheduleAppointment(appointment.ID, startsAt, endsAt)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, seatsAt(startsAt))
+	})
+}
+
+func TestBuffers(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	_, err = store.SetTrainerSettings(&models.TrainerSettings{TrainerID: 1, TimeZone: models.DEFAULT_TZ, Durations: models.DefaultDurations, Capacity: 2, BufferBefore: 10, BufferAfter: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+	startsAt := monday.Add(time.Hour * 10)
+	endsAt := startsAt.Add(time.Minute * 30)
+
+	starts := func() []string {
+		timeslots, err := store.GetTrainerAvailability(1, monday, monday.Add(time.Hour*24), time.Minute*30, 0, nil)
+		assert.NoError(t, err)
+
+		starts := make([]string, 0)
+		for _, timeslot := range *timeslots {
+			starts = append(starts, timeslot.StartsAt.Format("15:04"))
+		}
+		return starts
+	}
+
+	appointment, err := store.CreateAppointment(&models.Appointment{UserID: 1, TrainerID: 1, StartsAt: startsAt, EndsAt: endsAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Availability leaves room for buffers", func(t *testing.T) {
+		// INFO: The 25 minute gap rules out the neighbouring timeslots
+		available := starts()
+		assert.Contains(t, available, "09:00")
+		assert.NotContains(t, available, "09:30")
+		assert.Contains(t, available, "10:00")
+		assert.NotContains(t, available, "10:30")
+		assert.Contains(t, available, "11:00")
+	})
+
+	t.Run("Back-to-back bookings are rejected", func(t *testing.T) {
+		_, err := store.CreateAppointment(&models.Appointment{UserID: 2, TrainerID: 1, StartsAt: endsAt, EndsAt: endsAt.Add(time.Minute * 30)})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+
+		err = store.ValidateAvailableTimeslot(&models.Appointment{UserID: 2, TrainerID: 1, StartsAt: startsAt.Add(-time.Minute * 30), EndsAt: startsAt})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+
+		_, err = store.CreateAppointment(&models.Appointment{UserID: 2, TrainerID: 1, StartsAt: endsAt.Add(time.Minute * 30), EndsAt: endsAt.Add(time.Hour)})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Group sessions are not affected", func(t *testing.T) {
+		_, err := store.CreateAppointment(&models.Appointment{UserID: 3, TrainerID: 1, StartsAt: startsAt, EndsAt: endsAt})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rescheduling respects buffers", func(t *testing.T) {
+		_, err := store.RescheduleAppointment(appointment.ID, startsAt.Add(time.Hour*-1), startsAt.Add(time.Minute*-30))
+		assert.NoError(t, err)
+
+		// INFO: User 3 still holds the 10:00 session, so 9:30 leaves no room for the buffers
+		_, err = store.RescheduleAppointment(appointment.ID, startsAt.Add(time.Minute*-30), startsAt)
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+	})
+
+	t.Run("Other trainers are not affected", func(t *testing.T) {
+		_, err := store.CreateAppointment(&models.Appointment{UserID: 4, TrainerID: 2, StartsAt: endsAt, EndsAt: endsAt.Add(time.Minute * 30)})
+		assert.NoError(t, err)
+	})
+}
+
 func TestValidateAvailableTimeslot(t *testing.T) {
 	store, err := setupStore()
 	if err != nil {
@@ -82,6 +315,36 @@ func TestValidateAvailableTimeslot(t *testing.T) {
 		assert.Equal(t, "Timeslot is not available", err.Error())
 	})
 
+	t.Run("Trainer busy during overlapping timeslot", func(t *testing.T) {
+		err = store.ValidateAvailableTimeslot(&models.Appointment{
+			UserID:    2,
+			TrainerID: 1,
+			StartsAt:  appointment.StartsAt.Add(-time.Minute * 15),
+			EndsAt:    appointment.EndsAt.Add(time.Minute * 30),
+		})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+	})
+
+	t.Run("Overlap detected across offsets", func(t *testing.T) {
+		err = store.ValidateAvailableTimeslot(&models.Appointment{
+			UserID:    1,
+			TrainerID: 2,
+			StartsAt:  appointment.StartsAt.Add(time.Minute * 15).UTC(),
+			EndsAt:    appointment.EndsAt.Add(time.Minute * 15).UTC(),
+		})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+	})
+
+	t.Run("Adjacent timeslot is available", func(t *testing.T) {
+		err = store.ValidateAvailableTimeslot(&models.Appointment{
+			UserID:    1,
+			TrainerID: 1,
+			StartsAt:  appointment.EndsAt,
+			EndsAt:    appointment.EndsAt.Add(time.Minute * 45),
+		})
+		assert.NoError(t, err)
+	})
+
 	t.Run("User busy during timeslot", func(t *testing.T) {
 		err = store.ValidateAvailableTimeslot(&models.Appointment{
 			UserID:    1,
@@ -94,6 +357,149 @@ func TestValidateAvailableTimeslot(t *testing.T) {
 	})
 }
 
+func TestCancelAppointment(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	appointment := getTestAppointment()
+
+	// INFO: Create test appointment
+	createdAppointment, err := store.CreateAppointment(appointment)
+	assert.NoError(t, err)
+	assert.NotNil(t, createdAppointment)
+
+	t.Run("Appointment not found", func(t *testing.T) {
+		_, err := store.CancelAppointment(createdAppointment.ID + 1)
+		assert.ErrorIs(t, err, ErrAppointmentNotFound)
+	})
+
+	t.Run("Cancel appointment", func(t *testing.T) {
+		cancelledAppointment, err := store.CancelAppointment(createdAppointment.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.AppointmentStatusCancelled, cancelledAppointment.Status)
+
+		fetchedAppointment, err := store.GetAppointmentByID(createdAppointment.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.AppointmentStatusCancelled, fetchedAppointment.Status)
+	})
+
+	t.Run("Appointment already cancelled", func(t *testing.T) {
+		_, err := store.CancelAppointment(createdAppointment.ID)
+		assert.ErrorIs(t, err, ErrAppointmentCancelled)
+	})
+
+	t.Run("Cancelled appointment is excluded from listing", func(t *testing.T) {
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 0)
+	})
+
+	t.Run("Cancelled appointment frees timeslot", func(t *testing.T) {
+		err := store.ValidateAvailableTimeslot(getTestAppointment())
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetAppointmentDetails(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	createdAppointment, err := store.CreateAppointment(getTestAppointment())
+	assert.NoError(t, err)
+
+	t.Run("Appointment not found", func(t *testing.T) {
+		_, err := store.GetAppointmentDetails(createdAppointment.ID + 1)
+		assert.ErrorIs(t, err, ErrAppointmentNotFound)
+	})
+
+	t.Run("Includes user and trainer", func(t *testing.T) {
+		details, err := store.GetAppointmentDetails(createdAppointment.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, createdAppointment.ID, details.ID)
+		assert.True(t, createdAppointment.StartsAt.Equal(details.StartsAt))
+		assert.Equal(t, models.ProfileSummary{ID: createdAppointment.UserID, Name: fmt.Sprintf("User %d", createdAppointment.UserID), Active: true}, details.User)
+		assert.Equal(t, models.ProfileSummary{ID: createdAppointment.TrainerID, Name: fmt.Sprintf("Trainer %d", createdAppointment.TrainerID), Active: true}, details.Trainer)
+	})
+
+	t.Run("Cancelled appointments are returned", func(t *testing.T) {
+		_, err := store.CancelAppointment(createdAppointment.ID)
+		assert.NoError(t, err)
+
+		details, err := store.GetAppointmentDetails(createdAppointment.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.AppointmentStatusCancelled, details.Status)
+	})
+}
+
+func TestRescheduleAppointment(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	appointment := getTestAppointment()
+
+	// INFO: Create test appointment and a conflicting appointment for the same trainer
+	createdAppointment, err := store.CreateAppointment(appointment)
+	assert.NoError(t, err)
+
+	otherAppointment := getTestAppointment()
+	otherAppointment.UserID = 2
+	otherAppointment.StartsAt = otherAppointment.StartsAt.Add(time.Hour)
+	otherAppointment.EndsAt = otherAppointment.EndsAt.Add(time.Hour)
+	_, err = store.CreateAppointment(otherAppointment)
+	assert.NoError(t, err)
+
+	t.Run("Appointment not found", func(t *testing.T) {
+		_, err := store.RescheduleAppointment(999, appointment.StartsAt, appointment.EndsAt)
+		assert.ErrorIs(t, err, ErrAppointmentNotFound)
+	})
+
+	t.Run("Invalid timeslot", func(t *testing.T) {
+		_, err := store.RescheduleAppointment(createdAppointment.ID, appointment.StartsAt.Add(-time.Hour), appointment.EndsAt.Add(-time.Hour))
+		assert.Error(t, err)
+		assert.Equal(t, "Appointment must be scheduled within the trainer's working hours", err.Error())
+	})
+
+	t.Run("Conflicting timeslot keeps original booking", func(t *testing.T) {
+		_, err := store.RescheduleAppointment(createdAppointment.ID, otherAppointment.StartsAt, otherAppointment.EndsAt)
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+
+		fetchedAppointment, err := store.GetAppointmentByID(createdAppointment.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, appointment.StartsAt, fetchedAppointment.StartsAt)
+		assert.Equal(t, appointment.EndsAt, fetchedAppointment.EndsAt)
+	})
+
+	t.Run("Same timeslot ignores appointment being moved", func(t *testing.T) {
+		rescheduledAppointment, err := store.RescheduleAppointment(createdAppointment.ID, appointment.StartsAt, appointment.EndsAt)
+		assert.NoError(t, err)
+		assert.Equal(t, createdAppointment.ID, rescheduledAppointment.ID)
+	})
+
+	t.Run("Valid reschedule", func(t *testing.T) {
+		newStartsAt := appointment.StartsAt.Add(time.Hour * 2)
+		newEndsAt := appointment.EndsAt.Add(time.Hour * 2)
+
+		rescheduledAppointment, err := store.RescheduleAppointment(createdAppointment.ID, newStartsAt, newEndsAt)
+		assert.NoError(t, err)
+		assert.Equal(t, newStartsAt, rescheduledAppointment.StartsAt)
+		assert.Equal(t, newEndsAt, rescheduledAppointment.EndsAt)
+
+		fetchedAppointment, err := store.GetAppointmentByID(createdAppointment.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, newStartsAt, fetchedAppointment.StartsAt)
+		assert.Equal(t, newEndsAt, fetchedAppointment.EndsAt)
+	})
+}
+
 func TestGetAppointmentsByTrainerID(t *testing.T) {
 	store, err := setupStore()
 	if err != nil {
@@ -109,7 +515,7 @@ func TestGetAppointmentsByTrainerID(t *testing.T) {
 	assert.NotNil(t, createdAppointment)
 
 	t.Run("All appointments", func(t *testing.T) {
-		appointments, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{})
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{})
 		assert.NoError(t, err)
 		assert.NotNil(t, appointments)
 		assert.Len(t, appointments, 1)
@@ -121,7 +527,7 @@ func TestGetAppointmentsByTrainerID(t *testing.T) {
 	})
 
 	t.Run("Appointment within timeframe", func(t *testing.T) {
-		appointments, err := store.GetAppointmentsByTrainerID(1, appointment.StartsAt.Add(-time.Hour), appointment.EndsAt.Add(time.Hour))
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, appointment.StartsAt.Add(-time.Hour), appointment.EndsAt.Add(time.Hour), models.Page{})
 		assert.NoError(t, err)
 		assert.NotNil(t, appointments)
 		assert.Len(t, appointments, 1)
@@ -133,7 +539,7 @@ func TestGetAppointmentsByTrainerID(t *testing.T) {
 	})
 
 	t.Run("Appointment overlaps timeframe start", func(t *testing.T) {
-		appointments, err := store.GetAppointmentsByTrainerID(1, appointment.StartsAt.Add(time.Minute*15), appointment.EndsAt.Add(time.Hour))
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, appointment.StartsAt.Add(time.Minute*15), appointment.EndsAt.Add(time.Hour), models.Page{})
 		assert.NoError(t, err)
 		assert.NotNil(t, appointments)
 		assert.Len(t, appointments, 1)
@@ -141,7 +547,7 @@ func TestGetAppointmentsByTrainerID(t *testing.T) {
 	})
 
 	t.Run("Appointment overlaps timeframe end", func(t *testing.T) {
-		appointments, err := store.GetAppointmentsByTrainerID(1, appointment.StartsAt.Add(-time.Hour), appointment.EndsAt.Add(-time.Minute*15))
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, appointment.StartsAt.Add(-time.Hour), appointment.EndsAt.Add(-time.Minute*15), models.Page{})
 		assert.NoError(t, err)
 		assert.NotNil(t, appointments)
 		assert.Len(t, appointments, 1)
@@ -149,19 +555,155 @@ func TestGetAppointmentsByTrainerID(t *testing.T) {
 	})
 
 	t.Run("Appointment not in timeframe", func(t *testing.T) {
-		appointments, err := store.GetAppointmentsByTrainerID(1, appointment.EndsAt.Add(time.Hour), appointment.EndsAt.Add(time.Hour*2))
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, appointment.EndsAt.Add(time.Hour), appointment.EndsAt.Add(time.Hour*2), models.Page{})
 		assert.NoError(t, err)
 		assert.NotNil(t, appointments)
 		assert.Len(t, appointments, 0)
 	})
 
 	t.Run("Get trainer with no appointments", func(t *testing.T) {
-		appointments, err := store.GetAppointmentsByTrainerID(2, appointment.StartsAt.Add(-time.Hour), appointment.EndsAt.Add(time.Hour))
+		appointments, _, err := store.GetAppointmentsByTrainerID(2, appointment.StartsAt.Add(-time.Hour), appointment.EndsAt.Add(time.Hour), models.Page{})
 		assert.NoError(t, err)
 		assert.NotNil(t, appointments)
 		assert.Len(t, appointments, 0)
 	})
 
+	t.Run("Timeframe in other offsets", func(t *testing.T) {
+		tokyo := time.FixedZone("UTC+9", 9*60*60)
+		honolulu := time.FixedZone("UTC-10", -10*60*60)
+
+		appointments, _, err := store.GetAppointmentsByTrainerID(1, appointment.StartsAt.In(tokyo), appointment.EndsAt.In(honolulu), models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 1)
+
+		// INFO: Compared as text in their own offsets these bounds would overlap the appointment
+		appointments, _, err = store.GetAppointmentsByTrainerID(1, appointment.EndsAt.Add(time.Minute).In(honolulu), appointment.EndsAt.Add(time.Hour).In(tokyo), models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 0)
+	})
+}
+
+func TestAppointmentIndexes(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	plan := func(query string, args ...any) string {
+		rows, err := store.DB.Query("EXPLAIN QUERY PLAN "+query, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		details := ""
+		for rows.Next() {
+			var id, parent, unused int
+			var detail string
+			if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+				t.Fatal(err)
+			}
+			details += detail + "\n"
+		}
+		return details
+	}
+
+	startsAt, endsAt := formatTime(time.Now()), formatTime(time.Now().Add(time.Hour))
+
+	t.Run("Trainer range", func(t *testing.T) {
+		detail := plan(`SELECT id FROM appointments WHERE trainer_id = $1 AND ends_at >= $2 AND starts_at <= $3 ORDER BY starts_at, id`, 1, startsAt, endsAt)
+		assert.Contains(t, detail, "idx_appointments_trainer_id_starts_at (trainer_id=? AND starts_at<?)")
+	})
+
+	t.Run("User range", func(t *testing.T) {
+		detail := plan(`SELECT id FROM appointments WHERE user_id = $1 AND ends_at >= $2 AND starts_at <= $3 ORDER BY starts_at, id`, 1, startsAt, endsAt)
+		assert.Contains(t, detail, "idx_appointments_user_id_starts_at (user_id=? AND starts_at<?)")
+	})
+}
+
+func TestAppointmentPagination(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	tz := models.DefaultLocation()
+	past := time.Date(2020, 7, 6, 9, 0, 0, 0, tz)
+	upcoming := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+
+	cancelled := upcoming.AddDate(0, 0, 2)
+
+	// INFO: The cancelled appointments share a start time so the id breaks the tie
+	for _, appointment := range []*models.Appointment{
+		{UserID: 1, TrainerID: 1, StartsAt: upcoming, EndsAt: upcoming.Add(time.Minute * 30)},
+		{UserID: 2, TrainerID: 2, StartsAt: upcoming, EndsAt: upcoming.Add(time.Minute * 30)},
+		{UserID: 1, TrainerID: 1, StartsAt: upcoming.AddDate(0, 0, 1), EndsAt: upcoming.AddDate(0, 0, 1).Add(time.Minute * 30)},
+		{UserID: 1, TrainerID: 1, StartsAt: past, EndsAt: past.Add(time.Minute * 30)},
+		{UserID: 1, TrainerID: 2, StartsAt: past.Add(time.Hour), EndsAt: past.Add(time.Minute * 90)},
+		{UserID: 1, TrainerID: 1, StartsAt: cancelled, EndsAt: cancelled.Add(time.Minute * 30), Status: models.AppointmentStatusCancelled},
+		{UserID: 1, TrainerID: 2, StartsAt: cancelled, EndsAt: cancelled.Add(time.Minute * 30), Status: models.AppointmentStatusCancelled},
+		{UserID: 1, TrainerID: 3, StartsAt: cancelled, EndsAt: cancelled.Add(time.Minute * 30), Status: models.AppointmentStatusCancelled},
+	} {
+		if _, err := store.CreateAppointment(appointment); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collect := func(list func(page models.Page) ([]*models.Appointment, *models.Cursor, error)) []int {
+		ids := make([]int, 0)
+		page := models.Page{Limit: 2}
+		for {
+			appointments, next, err := list(page)
+			if !assert.NoError(t, err) {
+				return ids
+			}
+			assert.LessOrEqual(t, len(appointments), 2)
+			for _, appointment := range appointments {
+				ids = append(ids, appointment.ID)
+			}
+			if next == nil {
+				return ids
+			}
+			page.Cursor = next
+		}
+	}
+
+	t.Run("Trainer appointments", func(t *testing.T) {
+		ids := collect(func(page models.Page) ([]*models.Appointment, *models.Cursor, error) {
+			return store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, page)
+		})
+		assert.Equal(t, []int{4, 1, 3}, ids)
+	})
+
+	t.Run("User appointments", func(t *testing.T) {
+		ids := collect(func(page models.Page) ([]*models.Appointment, *models.Cursor, error) {
+			return store.GetAppointmentsByUserID(1, time.Time{}, time.Time{}, "", page)
+		})
+		assert.Equal(t, []int{4, 5, 1, 3}, ids)
+	})
+
+	t.Run("Ties are ordered by id", func(t *testing.T) {
+		ids := collect(func(page models.Page) ([]*models.Appointment, *models.Cursor, error) {
+			return store.GetAppointmentsByUserID(1, time.Time{}, time.Time{}, AppointmentFilterCancelled, page)
+		})
+		assert.Equal(t, []int{6, 7, 8}, ids)
+	})
+
+	t.Run("Past appointments page backwards", func(t *testing.T) {
+		ids := collect(func(page models.Page) ([]*models.Appointment, *models.Cursor, error) {
+			return store.GetAppointmentsByUserID(1, time.Time{}, time.Time{}, AppointmentFilterPast, page)
+		})
+		assert.Equal(t, []int{5, 4}, ids)
+	})
+
+	t.Run("Last page has no cursor", func(t *testing.T) {
+		appointments, next, err := store.GetAppointmentsByTrainerID(1, time.Time{}, time.Time{}, models.Page{Limit: 3})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 3)
+		assert.Nil(t, next)
+	})
 }
 
 func TestGetTrainerAvailability(t *testing.T) {
@@ -171,12 +713,12 @@ func TestGetTrainerAvailability(t *testing.T) {
 	}
 	defer store.Close()
 
-	tz := time.FixedZone(models.GLOBAL_TZ, models.GLOBAL_TZ_OFFSET)
+	tz := models.DefaultLocation()
 	startsAt := time.Date(2030, 7, 5, 0, 0, 0, 0, tz) // Friday midnight
 	endsAt := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)   // Monday midnight
 
 	t.Run("Trainer with no appointments", func(t *testing.T) {
-		timeslots, err := store.GetTrainerAvailability(1, startsAt, endsAt)
+		timeslots, err := store.GetTrainerAvailability(1, startsAt, endsAt, time.Minute*30, 0, nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, timeslots)
 		assert.NotZero(t, len(*timeslots))
@@ -212,7 +754,7 @@ func TestGetTrainerAvailability(t *testing.T) {
 
 	t.Run("Trainer with appointments", func(t *testing.T) {
 		// INFO: Get initial availability
-		timeslots, err := store.GetTrainerAvailability(1, startsAt, endsAt)
+		timeslots, err := store.GetTrainerAvailability(1, startsAt, endsAt, time.Minute*30, 0, nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, timeslots)
 		assert.NotZero(t, len(*timeslots))
@@ -228,11 +770,380 @@ func TestGetTrainerAvailability(t *testing.T) {
 		assert.NotNil(t, createdAppointment)
 
 		// INFO: Get updated availability
-		updatedTimeslots, err := store.GetTrainerAvailability(1, startsAt, endsAt)
+		updatedTimeslots, err := store.GetTrainerAvailability(1, startsAt, endsAt, time.Minute*30, 0, nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, updatedTimeslots)
 		assert.Len(t, *updatedTimeslots, len(*timeslots)-1)
 		assert.NotEqual(t, (*timeslots)[0].StartsAt, (*updatedTimeslots)[0].StartsAt)
 		assert.NotEqual(t, (*timeslots)[0].EndsAt, (*updatedTimeslots)[0].EndsAt)
 	})
+
+	t.Run("Longer bookings block every overlapping timeslot", func(t *testing.T) {
+		monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+
+		// INFO: 90-minute booking from 9:00 to 10:30
+		_, err := store.CreateAppointment(&models.Appointment{
+			UserID:    1,
+			TrainerID: 4,
+			StartsAt:  monday.Add(time.Hour * 9),
+			EndsAt:    monday.Add(time.Hour * 10).Add(time.Minute * 30),
+		})
+		assert.NoError(t, err)
+
+		timeslots, err := store.GetTrainerAvailability(4, monday, monday.Add(time.Hour*24), time.Minute*60, 0, nil)
+		assert.NoError(t, err)
+
+		starts := make([]string, 0)
+		for _, timeslot := range *timeslots {
+			assert.Equal(t, time.Minute*60, timeslot.EndsAt.Sub(timeslot.StartsAt))
+			starts = append(starts, timeslot.StartsAt.Format("15:04"))
+		}
+		assert.Equal(t, []string{
+			"08:00", "10:30", "11:00", "11:30", "12:00", "12:30", "13:00",
+			"13:30", "14:00", "14:30", "15:00", "15:30", "16:00",
+		}, starts)
+	})
+
+	t.Run("Availability across DST transition", func(t *testing.T) {
+		startsAt := time.Date(2030, 11, 1, 0, 0, 0, 0, tz) // Friday midnight PDT
+		endsAt := time.Date(2030, 11, 5, 0, 0, 0, 0, tz)   // Tuesday midnight PST
+
+		timeslots, err := store.GetTrainerAvailability(2, startsAt, endsAt, time.Minute*30, 0, nil)
+		assert.NoError(t, err)
+		assert.Len(t, *timeslots, 36)
+
+		timeFormat := "2006-01-02T15:04:05-07:00"
+		assert.Equal(t, "2030-11-01T08:00:00-07:00", (*timeslots)[0].StartsAt.Format(timeFormat))
+		assert.Equal(t, "2030-11-04T08:00:00-08:00", (*timeslots)[18].StartsAt.Format(timeFormat))
+		assert.Equal(t, "2030-11-04T17:00:00-08:00", (*timeslots)[35].EndsAt.Format(timeFormat))
+	})
+
+	t.Run("Availability in trainer's time zone", func(t *testing.T) {
+		_, err := store.SetTrainerSettings(&models.TrainerSettings{TrainerID: 3, TimeZone: "Europe/London"})
+		assert.NoError(t, err)
+
+		timeslots, err := store.GetTrainerAvailability(3, startsAt, endsAt, time.Minute*30, 0, nil)
+		assert.NoError(t, err)
+		assert.NotZero(t, len(*timeslots))
+
+		timeFormat := "2006-01-02T15:04:05-07:00"
+		assert.Equal(t, "2030-07-05T08:00:00+01:00", (*timeslots)[0].StartsAt.Format(timeFormat))
+	})
+
+	t.Run("Excludes the user's own appointments", func(t *testing.T) {
+		monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+
+		// INFO: User 2 is booked with trainer 5 from 10:00 to 11:00
+		_, err := store.CreateAppointment(&models.Appointment{
+			UserID:    2,
+			TrainerID: 5,
+			StartsAt:  monday.Add(time.Hour * 10),
+			EndsAt:    monday.Add(time.Hour * 11),
+		})
+		assert.NoError(t, err)
+
+		timeslots, err := store.GetTrainerAvailability(2, monday, monday.Add(time.Hour*24), time.Minute*60, 0, nil)
+		assert.NoError(t, err)
+		assert.Len(t, *timeslots, 17)
+
+		timeslots, err = store.GetTrainerAvailability(2, monday, monday.Add(time.Hour*24), time.Minute*60, 2, nil)
+		assert.NoError(t, err)
+
+		starts := make([]string, 0)
+		for _, timeslot := range *timeslots {
+			starts = append(starts, timeslot.StartsAt.Format("15:04"))
+		}
+		assert.Equal(t, []string{
+			"08:00", "08:30", "09:00", "11:00", "11:30", "12:00", "12:30",
+			"13:00", "13:30", "14:00", "14:30", "15:00", "15:30", "16:00",
+		}, starts)
+	})
+}
+
+func TestGetAppointmentsByUserID(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	tz := models.DefaultLocation()
+	london, _ := models.LoadLocation("Europe/London")
+
+	settings, err := models.NewTrainerSettings(2, "Europe/London", nil, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SetTrainerSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	past := time.Date(2020, 7, 6, 9, 0, 0, 0, tz)
+	upcoming := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+
+	for _, appointment := range []*models.Appointment{
+		{UserID: 1, TrainerID: 1, StartsAt: past, EndsAt: past.Add(time.Minute * 30)},
+		{UserID: 1, TrainerID: 1, StartsAt: upcoming, EndsAt: upcoming.Add(time.Minute * 30)},
+		{UserID: 1, TrainerID: 2, StartsAt: upcoming.AddDate(0, 0, 1), EndsAt: upcoming.AddDate(0, 0, 1).Add(time.Minute * 30)},
+		{UserID: 1, TrainerID: 1, StartsAt: upcoming.AddDate(0, 0, 2), EndsAt: upcoming.AddDate(0, 0, 2).Add(time.Minute * 30), Status: models.AppointmentStatusCancelled},
+		{UserID: 2, TrainerID: 1, StartsAt: upcoming.AddDate(0, 0, 3), EndsAt: upcoming.AddDate(0, 0, 3).Add(time.Minute * 30)},
+	} {
+		if _, err := store.CreateAppointment(appointment); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("All scheduled appointments", func(t *testing.T) {
+		appointments, _, err := store.GetAppointmentsByUserID(1, time.Time{}, time.Time{}, "", models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 3)
+
+		// INFO: Appointments are returned in their trainer's time zone
+		assert.Equal(t, tz, appointments[1].StartsAt.Location())
+		assert.Equal(t, london, appointments[2].StartsAt.Location())
+	})
+
+	t.Run("Upcoming", func(t *testing.T) {
+		appointments, _, err := store.GetAppointmentsByUserID(1, time.Time{}, time.Time{}, AppointmentFilterUpcoming, models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 2)
+		assert.True(t, upcoming.Equal(appointments[0].StartsAt))
+	})
+
+	t.Run("Past", func(t *testing.T) {
+		appointments, _, err := store.GetAppointmentsByUserID(1, time.Time{}, time.Time{}, AppointmentFilterPast, models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 1)
+		assert.True(t, past.Equal(appointments[0].StartsAt))
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		appointments, _, err := store.GetAppointmentsByUserID(1, time.Time{}, time.Time{}, AppointmentFilterCancelled, models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 1)
+		assert.Equal(t, models.AppointmentStatusCancelled, appointments[0].Status)
+	})
+
+	t.Run("Upcoming within a window", func(t *testing.T) {
+		appointments, _, err := store.GetAppointmentsByUserID(1, upcoming.AddDate(0, 0, 1), upcoming.AddDate(0, 0, 7), AppointmentFilterUpcoming, models.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, appointments, 1)
+		assert.Equal(t, 2, appointments[0].TrainerID)
+	})
+}
+
+func TestExportAppointments(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+
+	book := func(userID, trainerID int, startsAt time.Time) *models.Appointment {
+		appointment, err := store.CreateAppointment(&models.Appointment{
+			UserID:    userID,
+			TrainerID: trainerID,
+			StartsAt:  startsAt,
+			EndsAt:    startsAt.Add(time.Minute * 30),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return appointment
+	}
+
+	late := book(1, 1, monday.Add(time.Hour*2))
+	early := book(2, 1, monday)
+	other := book(1, 2, monday.AddDate(0, 0, 1))
+	cancelled := book(3, 1, monday.Add(time.Hour))
+	if _, err := store.CancelAppointment(cancelled.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(appointments []*models.Appointment) []int {
+		result := make([]int, 0, len(appointments))
+		for _, appointment := range appointments {
+			result = append(result, appointment.ID)
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name      string
+		trainerID int
+		userID    int
+		startsAt  time.Time
+		endsAt    time.Time
+		expected  []int
+	}{
+		{name: "everything", expected: []int{late.ID, early.ID, other.ID, cancelled.ID}},
+		{name: "trainer", trainerID: 1, expected: []int{late.ID, early.ID, cancelled.ID}},
+		{name: "user", userID: 1, expected: []int{late.ID, other.ID}},
+		{name: "trainer and user", trainerID: 2, userID: 1, expected: []int{other.ID}},
+		{name: "from", startsAt: monday.Add(time.Hour), expected: []int{late.ID, other.ID, cancelled.ID}},
+		{name: "to", endsAt: monday.Add(time.Hour), expected: []int{early.ID, cancelled.ID}},
+		{name: "range", startsAt: monday.Add(time.Hour), endsAt: monday.Add(time.Hour * 2), expected: []int{late.ID, cancelled.ID}},
+		{name: "no match", userID: 9, expected: []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			appointments, err := store.ExportAppointments(tc.trainerID, tc.userID, tc.startsAt, tc.endsAt)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ids(appointments))
+		})
+	}
+
+	t.Run("Cancelled appointments keep their status", func(t *testing.T) {
+		appointments, err := store.ExportAppointments(0, 3, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		if assert.Len(t, appointments, 1) {
+			assert.Equal(t, models.AppointmentStatusCancelled, appointments[0].Status)
+			assert.Equal(t, monday.Add(time.Hour), appointments[0].StartsAt)
+		}
+	})
+}
+
+func TestImportAppointments(t *testing.T) {
+	source, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+
+	settings, err := models.NewTrainerSettings(1, tz.String(), nil, 3, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.SetTrainerSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	book := func(userID, trainerID int, startsAt time.Time) *models.Appointment {
+		appointment, err := source.CreateAppointment(&models.Appointment{
+			UserID:    userID,
+			TrainerID: trainerID,
+			StartsAt:  startsAt,
+			EndsAt:    startsAt.Add(time.Minute * 30),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return appointment
+	}
+
+	// INFO: A group session only fits the source trainer's settings
+	book(1, 1, monday)
+	book(2, 1, monday)
+	book(3, 2, monday.AddDate(0, 0, 1))
+	cancelled := book(4, 1, monday.Add(time.Hour))
+	if _, err := source.CancelAppointment(cancelled.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := source.ExportAppointments(0, 0, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Export seeds back into a fresh database", func(t *testing.T) {
+		target, err := setupStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer target.Close()
+
+		// INFO: The target has default settings and a holiday on the trainer 2 booking
+		if _, err := target.CreateHoliday(&models.Holiday{Date: "2030-07-09", Name: "Closed"}); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := models.WriteAppointmentsCSV(&buf, exported); err != nil {
+			t.Fatal(err)
+		}
+		entries, err := models.ReadAppointmentsCSV(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		appointments := make([]*models.Appointment, 0, len(entries))
+		for i := range entries {
+			appointments = append(appointments, &entries[i])
+		}
+
+		_, err = target.ImportAppointments(appointments)
+		assert.NoError(t, err)
+
+		imported, err := target.ExportAppointments(0, 0, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, exported, imported)
+
+		history, err := target.GetAppointmentHistory(cancelled.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, history, 1) {
+			assert.Equal(t, models.AppointmentActionCreated, history[0].Action)
+		}
+	})
+
+	t.Run("Imports keep their IDs and refuse taken ones", func(t *testing.T) {
+		target, err := setupStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer target.Close()
+
+		// INFO: Leaving out trainer 2 puts a gap in the exported IDs
+		partial, err := source.ExportAppointments(1, 0, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = target.ImportAppointments(partial)
+		assert.NoError(t, err)
+
+		imported, err := target.ExportAppointments(0, 0, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, partial, imported)
+
+		_, err = target.ImportAppointments(partial)
+		assert.ErrorIs(t, err, ErrAppointmentExists)
+
+		imported, err = target.ExportAppointments(0, 0, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Len(t, imported, len(partial))
+
+		next, err := target.CreateAppointment(&models.Appointment{
+			UserID:    5,
+			TrainerID: 3,
+			StartsAt:  monday,
+			EndsAt:    monday.Add(time.Minute * 30),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, cancelled.ID+1, next.ID)
+	})
+
+	t.Run("A failed import leaves nothing behind", func(t *testing.T) {
+		target, err := setupStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer target.Close()
+
+		_, err = target.ImportAppointments([]*models.Appointment{
+			{UserID: 1, TrainerID: 1, StartsAt: monday, EndsAt: monday.Add(time.Minute * 30)},
+			{UserID: 99, TrainerID: 1, StartsAt: monday, EndsAt: monday.Add(time.Minute * 30)},
+		})
+		assert.ErrorIs(t, err, ErrUserNotFound)
+
+		imported, err := target.ExportAppointments(0, 0, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Empty(t, imported)
+	})
 }
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"future-app/models"
+	"time"
+)
+
+var ErrTimeOffNotFound = errors.New("Time off not found")
+var ErrTrainerTimeOff = errors.New("Trainer is unavailable during timeslot")
+
+func (s *Store) CreateTimeOff(data *models.TimeOff) (*models.TimeOff, error) {
+	query := `
+	INSERT INTO trainer_time_off (trainer_id, starts_at, ends_at, all_day, reason)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+
+	res, err := s.DB.Exec(
+		query,
+		data.TrainerID,
+		formatTime(data.StartsAt),
+		formatTime(data.EndsAt),
+		data.AllDay,
+		data.Reason,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data.ID = int(id)
+	return data, nil
+}
+
+func (s *Store) UpdateTimeOff(data *models.TimeOff) (*models.TimeOff, error) {
+	query := `
+	UPDATE trainer_time_off
+	SET starts_at = $1, ends_at = $2, all_day = $3, reason = $4
+	WHERE id = $5 AND trainer_id = $6
+	`
+
+	res, err := s.DB.Exec(
+		query,
+		formatTime(data.StartsAt),
+		formatTime(data.EndsAt),
+		data.AllDay,
+		data.Reason,
+		data.ID,
+		data.TrainerID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrTimeOffNotFound
+	}
+
+	return data, nil
+}
+
+func (s *Store) DeleteTimeOff(trainerID, id int) error {
+	query := `
+	DELETE FROM trainer_time_off
+	WHERE id = $1 AND trainer_id = $2
+	`
+
+	res, err := s.DB.Exec(query, id, trainerID)
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrTimeOffNotFound
+	}
+
+	return nil
+}
+
+func (s *Store) GetTimeOffByTrainerID(trainerID int, startsAt, endsAt time.Time) ([]*models.TimeOff, error) {
+	return getTimeOffByTrainerID(s.DB, trainerID, startsAt, endsAt)
+}
+
+func getTimeOffByTrainerID(q querier, trainerID int, startsAt, endsAt time.Time) ([]*models.TimeOff, error) {
+	timeOff := make([]*models.TimeOff, 0)
+
+	settings, err := getTrainerSettings(q, trainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows *sql.Rows
+
+	if startsAt.IsZero() || endsAt.IsZero() {
+		query := `
+		SELECT id, trainer_id, starts_at, ends_at, all_day, reason
+		FROM trainer_time_off
+		WHERE trainer_id = $1
+		ORDER BY starts_at ASC
+		`
+		rows, err = q.Query(query, trainerID)
+	} else {
+		query := `
+		SELECT id, trainer_id, starts_at, ends_at, all_day, reason
+		FROM trainer_time_off
+		WHERE trainer_id = $1
+		AND ends_at > $2 AND starts_at < $3
+		ORDER BY starts_at ASC
+		`
+		rows, err = q.Query(
+			query,
+			trainerID,
+			formatTime(startsAt),
+			formatTime(endsAt),
+		)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.TimeOff
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.TrainerID,
+			&entry.StartsAt,
+			&entry.EndsAt,
+			&entry.AllDay,
+			&entry.Reason,
+		); err != nil {
+			return nil, err
+		}
+
+		timeOff = append(timeOff, entry.In(settings.Location()))
+	}
+
+	return timeOff, rows.Err()
+}
+
+func validateTrainerTimeOff(q querier, data *models.Appointment) error {
+	timeOff, err := getTimeOffByTrainerID(q, data.TrainerID, data.StartsAt, data.EndsAt)
+	if err != nil {
+		return err
+	}
+
+	if len(timeOff) != 0 {
+		return ErrTrainerTimeOff
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeOff(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 0, 0, 0, 0, tz)
+
+	var created *models.TimeOff
+
+	t.Run("Create time off", func(t *testing.T) {
+		timeOff, err := models.NewTimeOff(1, monday.Add(time.Hour*9), monday.Add(time.Hour*12), false, "Dentist", tz)
+		assert.NoError(t, err)
+
+		created, err = store.CreateTimeOff(timeOff)
+		assert.NoError(t, err)
+		assert.NotZero(t, created.ID)
+	})
+
+	t.Run("List time off", func(t *testing.T) {
+		timeOff, err := store.GetTimeOffByTrainerID(1, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*models.TimeOff{created}, timeOff)
+
+		timeOff, err = store.GetTimeOffByTrainerID(1, monday.Add(time.Hour*12), monday.Add(time.Hour*13))
+		assert.NoError(t, err)
+		assert.Len(t, timeOff, 0)
+	})
+
+	t.Run("Booking during time off", func(t *testing.T) {
+		appointment := &models.Appointment{
+			UserID:    1,
+			TrainerID: 1,
+			StartsAt:  monday.Add(time.Hour * 11).Add(time.Minute * 30),
+			EndsAt:    monday.Add(time.Hour * 12),
+		}
+
+		_, err := store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrTrainerTimeOff)
+	})
+
+	t.Run("Availability excludes time off", func(t *testing.T) {
+		timeslots, err := store.GetTrainerAvailability(1, monday, monday.Add(time.Hour*24), time.Minute*30, 0, nil)
+		assert.NoError(t, err)
+		assert.Len(t, *timeslots, 12)
+
+		for _, timeslot := range *timeslots {
+			assert.False(t, created.Timeslot().Overlaps(timeslot.StartsAt, timeslot.EndsAt))
+		}
+	})
+
+	t.Run("Update time off", func(t *testing.T) {
+		timeOff, err := models.NewTimeOff(1, monday, monday, true, "Vacation", tz)
+		assert.NoError(t, err)
+		timeOff.ID = created.ID
+
+		_, err = store.UpdateTimeOff(timeOff)
+		assert.NoError(t, err)
+
+		timeslots, err := store.GetTrainerAvailability(1, monday, monday.Add(time.Hour*24), time.Minute*30, 0, nil)
+		assert.NoError(t, err)
+		assert.Len(t, *timeslots, 0)
+	})
+
+	t.Run("Update time off for another trainer", func(t *testing.T) {
+		timeOff, err := models.NewTimeOff(2, monday, monday, true, "Vacation", tz)
+		assert.NoError(t, err)
+		timeOff.ID = created.ID
+
+		_, err = store.UpdateTimeOff(timeOff)
+		assert.ErrorIs(t, err, ErrTimeOffNotFound)
+	})
+
+	t.Run("Delete time off", func(t *testing.T) {
+		err := store.DeleteTimeOff(1, created.ID)
+		assert.NoError(t, err)
+
+		err = store.DeleteTimeOff(1, created.ID)
+		assert.ErrorIs(t, err, ErrTimeOffNotFound)
+
+		timeslots, err := store.GetTrainerAvailability(1, monday, monday.Add(time.Hour*24), time.Minute*30, 0, nil)
+		assert.NoError(t, err)
+		assert.Len(t, *timeslots, 18)
+	})
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"future-app/models"
+	"strconv"
+	"strings"
+)
+
+func (s *Store) GetTrainerSettings(trainerID int) (*models.TrainerSettings, error) {
+	return getTrainerSettings(s.DB, trainerID)
+}
+
+func getTrainerSettings(q querier, trainerID int) (*models.TrainerSettings, error) {
+	query := `
+	SELECT trainer_id, time_zone, durations, capacity, buffer_before, buffer_after
+	FROM trainer_settings
+	WHERE trainer_id = $1
+	`
+
+	settings, err := scanTrainerSettings(q.QueryRow(query, trainerID))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DefaultTrainerSettings(trainerID), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// scanTrainerSettings reads a trainer_settings row. Settings saved without
+// durations offer the default ones.
+func scanTrainerSettings(row interface{ Scan(...any) error }) (*models.TrainerSettings, error) {
+	var settings models.TrainerSettings
+	var durations string
+
+	if err := row.Scan(
+		&settings.TrainerID,
+		&settings.TimeZone,
+		&durations,
+		&settings.Capacity,
+		&settings.BufferBefore,
+		&settings.BufferAfter,
+	); err != nil {
+		return nil, err
+	}
+
+	if durations == "" {
+		settings.Durations = models.DefaultDurations
+		return &settings, nil
+	}
+
+	for _, duration := range strings.Split(durations, ",") {
+		minutes, err := strconv.Atoi(duration)
+		if err != nil {
+			return nil, err
+		}
+		settings.Durations = append(settings.Durations, minutes)
+	}
+
+	return &settings, nil
+}
+
+func (s *Store) SetTrainerSettings(settings *models.TrainerSettings) (*models.TrainerSettings, error) {
+	query := `
+	INSERT INTO trainer_settings (trainer_id, time_zone, durations, capacity, buffer_before, buffer_after)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (trainer_id) DO UPDATE SET
+		time_zone = excluded.time_zone,
+		durations = excluded.durations,
+		capacity = excluded.capacity,
+		buffer_before = excluded.buffer_before,
+		buffer_after = excluded.buffer_after
+	`
+
+	settings.Capacity = settings.Seats()
+
+	durations := make([]string, len(settings.Durations))
+	for i, duration := range settings.Durations {
+		durations[i] = strconv.Itoa(duration)
+	}
+
+	if _, err := s.DB.Exec(
+		query,
+		settings.TrainerID,
+		settings.TimeZone,
+		strings.Join(durations, ","),
+		settings.Capacity,
+		settings.BufferBefore,
+		settings.BufferAfter,
+	); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"future-app/models"
+)
+
+var ErrTrainerNotFound = errors.New("Trainer not found")
+var ErrTrainerInactive = errors.New("Trainer is not active")
+
+func (s *Store) CreateTrainer(data *models.Trainer) (*models.Trainer, error) {
+	query := `
+	INSERT INTO trainers (name, email, active)
+	VALUES ($1, $2, $3)
+	`
+
+	res, err := s.DB.Exec(query, data.Name, data.Email, data.Active)
+	if isUniqueViolation(err) {
+		return nil, ErrEmailTaken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data.ID = int(id)
+	return data, nil
+}
+
+func (s *Store) UpdateTrainer(data *models.Trainer) (*models.Trainer, error) {
+	query := `
+	UPDATE trainers
+	SET name = $1, email = $2, active = $3
+	WHERE id = $4
+	`
+
+	res, err := s.DB.Exec(query, data.Name, data.Email, data.Active, data.ID)
+	if isUniqueViolation(err) {
+		return nil, ErrEmailTaken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrTrainerNotFound
+	}
+
+	return data, nil
+}
+
+// DeactivateTrainer keeps the trainer so existing appointments still reference it,
+// but blocks new bookings.
+func (s *Store) DeactivateTrainer(id int) (*models.Trainer, error) {
+	trainer, err := s.GetTrainer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.DB.Exec(`UPDATE trainers SET active = FALSE WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	trainer.Active = false
+	return trainer, nil
+}
+
+func (s *Store) GetTrainer(id int) (*models.Trainer, error) {
+	return getTrainer(s.DB, id)
+}
+
+func getTrainer(q querier, id int) (*models.Trainer, error) {
+	var trainer models.Trainer
+
+	query := `
+	SELECT id, name, email, active
+	FROM trainers
+	WHERE id = $1
+	`
+
+	err := q.QueryRow(query, id).Scan(
+		&trainer.ID,
+		&trainer.Name,
+		&trainer.Email,
+		&trainer.Active,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTrainerNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &trainer, nil
+}
+
+func (s *Store) GetTrainers() ([]*models.Trainer, error) {
+	trainers := make([]*models.Trainer, 0)
+
+	query := `
+	SELECT id, name, email, active
+	FROM trainers
+	ORDER BY id ASC
+	`
+
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var trainer models.Trainer
+		if err := rows.Scan(
+			&trainer.ID,
+			&trainer.Name,
+			&trainer.Email,
+			&trainer.Active,
+		); err != nil {
+			return nil, err
+		}
+
+		trainers = append(trainers, &trainer)
+	}
+
+	return trainers, rows.Err()
+}
+
+func validateActiveTrainer(q querier, id int) error {
+	trainer, err := getTrainer(q, id)
+	if err != nil {
+		return err
+	}
+
+	if !trainer.Active {
+		return ErrTrainerInactive
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrainers(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	t.Run("Create trainer", func(t *testing.T) {
+		trainer, err := store.CreateTrainer(&models.Trainer{Name: "John Smith", Email: "john@example.com", Active: true})
+		assert.NoError(t, err)
+		assert.Equal(t, 6, trainer.ID)
+
+		trainers, err := store.GetTrainers()
+		assert.NoError(t, err)
+		assert.Len(t, trainers, 6)
+	})
+
+	t.Run("Deactivated trainers cannot be booked or rescheduled into", func(t *testing.T) {
+		appointment, err := store.CreateAppointment(getTestAppointment())
+		assert.NoError(t, err)
+
+		_, err = store.DeactivateTrainer(1)
+		assert.NoError(t, err)
+
+		next := getTestAppointment()
+		next.UserID = 2
+		next.StartsAt = next.StartsAt.AddDate(0, 0, 3)
+		next.EndsAt = next.EndsAt.AddDate(0, 0, 3)
+
+		_, err = store.CreateAppointment(next)
+		assert.ErrorIs(t, err, ErrTrainerInactive)
+
+		_, err = store.RescheduleAppointment(appointment.ID, next.StartsAt, next.EndsAt)
+		assert.ErrorIs(t, err, ErrTrainerInactive)
+	})
+
+	t.Run("Unknown trainers", func(t *testing.T) {
+		appointment := getTestAppointment()
+		appointment.TrainerID = 999999
+
+		_, err := store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrTrainerNotFound)
+
+		_, err = store.DeactivateTrainer(999999)
+		assert.ErrorIs(t, err, ErrTrainerNotFound)
+	})
+
+	t.Run("Foreign keys are enforced", func(t *testing.T) {
+		_, err := store.DB.Exec(`
+		INSERT INTO appointments (user_id, trainer_id, starts_at, ends_at)
+		VALUES (1, 999999, '2030-07-05T08:00:00-07:00', '2030-07-05T08:30:00-07:00')
+		`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"future-app/models"
+)
+
+var ErrUserNotFound = errors.New("User not found")
+var ErrUserInactive = errors.New("User is not active")
+
+func (s *Store) CreateUser(data *models.User) (*models.User, error) {
+	query := `
+	INSERT INTO users (name, email, active)
+	VALUES ($1, $2, $3)
+	`
+
+	res, err := s.DB.Exec(query, data.Name, data.Email, data.Active)
+	if isUniqueViolation(err) {
+		return nil, ErrEmailTaken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data.ID = int(id)
+	return data, nil
+}
+
+func (s *Store) UpdateUser(data *models.User) (*models.User, error) {
+	query := `
+	UPDATE users
+	SET name = $1, email = $2, active = $3
+	WHERE id = $4
+	`
+
+	res, err := s.DB.Exec(query, data.Name, data.Email, data.Active, data.ID)
+	if isUniqueViolation(err) {
+		return nil, ErrEmailTaken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	return data, nil
+}
+
+// DeactivateUser keeps the user so existing appointments still reference it,
+// but blocks new bookings.
+func (s *Store) DeactivateUser(id int) (*models.User, error) {
+	user, err := s.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.DB.Exec(`UPDATE users SET active = FALSE WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	user.Active = false
+	return user, nil
+}
+
+func (s *Store) GetUser(id int) (*models.User, error) {
+	return getUser(s.DB, id)
+}
+
+func getUser(q querier, id int) (*models.User, error) {
+	var user models.User
+
+	query := `
+	SELECT id, name, email, active
+	FROM users
+	WHERE id = $1
+	`
+
+	err := q.QueryRow(query, id).Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.Active,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (s *Store) GetUsers() ([]*models.User, error) {
+	users := make([]*models.User, 0)
+
+	query := `
+	SELECT id, name, email, active
+	FROM users
+	ORDER BY id ASC
+	`
+
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(
+			&user.ID,
+			&user.Name,
+			&user.Email,
+			&user.Active,
+		); err != nil {
+			return nil, err
+		}
+
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+func validateActiveUser(q querier, id int) error {
+	user, err := getUser(q, id)
+	if err != nil {
+		return err
+	}
+
+	if !user.Active {
+		return ErrUserInactive
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsers(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	var created *models.User
+
+	t.Run("Create user", func(t *testing.T) {
+		user, err := models.NewUser("Jane Doe", "jane@example.com", true)
+		assert.NoError(t, err)
+
+		created, err = store.CreateUser(user)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, created.ID)
+
+		_, err = store.CreateUser(&models.User{Name: "Jane", Email: "jane@example.com", Active: true})
+		assert.ErrorIs(t, err, ErrEmailTaken)
+	})
+
+	t.Run("Update user", func(t *testing.T) {
+		created.Name = "Jane Smith"
+		_, err := store.UpdateUser(created)
+		assert.NoError(t, err)
+
+		user, err := store.GetUser(created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane Smith", user.Name)
+
+		_, err = store.UpdateUser(&models.User{ID: 999, Name: "Nobody", Email: "nobody@example.com"})
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("Deactivated users cannot book", func(t *testing.T) {
+		user, err := store.DeactivateUser(created.ID)
+		assert.NoError(t, err)
+		assert.False(t, user.Active)
+
+		appointment := getTestAppointment()
+		appointment.UserID = created.ID
+
+		_, err = store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrUserInactive)
+	})
+
+	t.Run("Unknown users cannot book", func(t *testing.T) {
+		appointment := getTestAppointment()
+		appointment.UserID = 999
+
+		_, err := store.CreateAppointment(appointment)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("List users", func(t *testing.T) {
+		users, err := store.GetUsers()
+		assert.NoError(t, err)
+		assert.Len(t, users, 11)
+	})
+}
@@ -0,0 +1,590 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"future-app/models"
+	"strings"
+	"time"
+)
+
+var ErrWaitlistEntryNotFound = errors.New("Waitlist entry not found")
+var ErrAlreadyWaitlisted = errors.New("User is already on the waitlist for this timeslot")
+var ErrWaitlistOfferUnavailable = errors.New("Waitlist entry does not have an open offer")
+var ErrWaitlistOfferExpired = errors.New("Waitlist offer has expired")
+
+const waitlistColumns = `id, user_id, trainer_id, starts_at, ends_at, auto_book, status, offer_expires_at, appointment_id, created_at`
+
+func scanWaitlistEntry(row interface{ Scan(...any) error }) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	if err := row.Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.TrainerID,
+		&entry.StartsAt,
+		&entry.EndsAt,
+		&entry.AutoBook,
+		&entry.Status,
+		&entry.OfferExpiresAt,
+		&entry.AppointmentID,
+		&entry.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CreateWaitlistEntry adds the user to the back of the queue for the slot. If
+// the slot is already free the entry is offered or booked straight away.
+func (s *Store) CreateWaitlistEntry(data *models.WaitlistEntry) (*models.WaitlistEntry, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var count int
+
+	query := `
+	SELECT COUNT(*)
+	FROM waitlist
+	WHERE user_id = $1 AND trainer_id = $2
+	AND starts_at = $3 AND ends_at = $4
+	AND status IN ('waiting', 'offered')
+	`
+
+	if err := tx.QueryRow(
+		query,
+		data.UserID,
+		data.TrainerID,
+		formatTime(data.StartsAt),
+		formatTime(data.EndsAt),
+	).Scan(&count); err != nil {
+		return nil, err
+	}
+
+	if count != 0 {
+		return nil, ErrAlreadyWaitlisted
+	}
+
+	now := time.Now()
+
+	if err := expireWaitlistOffers(tx, s.audit, now); err != nil {
+		return nil, err
+	}
+
+	query = `
+	INSERT INTO waitlist (user_id, trainer_id, starts_at, ends_at, auto_book, status, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	res, err := tx.Exec(
+		query,
+		data.UserID,
+		data.TrainerID,
+		formatTime(data.StartsAt),
+		formatTime(data.EndsAt),
+		data.AutoBook,
+		models.WaitlistStatusWaiting,
+		formatTime(now),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := offerTimeslot(tx, s.audit, data.TrainerID, data.StartsAt, data.EndsAt, now); err != nil {
+		return nil, err
+	}
+
+	entry, err := getWaitlistEntry(tx, int(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (s *Store) GetWaitlistEntry(id int) (*models.WaitlistEntry, error) {
+	return getWaitlistEntry(s.DB, id)
+}
+
+func getWaitlistEntry(q querier, id int) (*models.WaitlistEntry, error) {
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist WHERE id = $1`
+
+	entry, err := scanWaitlistEntry(q.QueryRow(query, id))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWaitlistEntryNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := getTrainerSettings(q, entry.TrainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.In(settings.Location()), nil
+}
+
+// GetWaitlistByTrainerID returns the trainer's waiting and offered entries in
+// queue order.
+func (s *Store) GetWaitlistByTrainerID(trainerID int) ([]*models.WaitlistEntry, error) {
+	settings, err := getTrainerSettings(s.DB, trainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT ` + waitlistColumns + `
+	FROM waitlist
+	WHERE trainer_id = $1 AND status IN ('waiting', 'offered')
+	ORDER BY starts_at ASC, id ASC
+	`
+
+	rows, err := s.DB.Query(query, trainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*models.WaitlistEntry, 0)
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		entries = append(entries, entry.In(settings.Location()))
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// AcceptWaitlistOffer books the offered slot for the waitlisted user.
+func (s *Store) AcceptWaitlistOffer(id int) (*models.Appointment, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	entry, err := getWaitlistEntry(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status == models.WaitlistStatusOffered && !entry.OfferExpiresAt.After(now) {
+		// INFO: The lapsed offer moves on to the next user even though the accept fails
+		if err := expireWaitlistOffers(tx, s.audit, now); err != nil {
+			return nil, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrWaitlistOfferExpired
+	}
+
+	if entry.Status != models.WaitlistStatusOffered {
+		return nil, ErrWaitlistOfferUnavailable
+	}
+
+	appointment, err := bookAppointment(tx, s.audit, entry.Appointment())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setWaitlistStatus(tx, entry.ID, models.WaitlistStatusBooked, nil, &appointment.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+// CancelWaitlistEntry removes the user from the queue. An open offer is passed
+// on to the next user.
+func (s *Store) CancelWaitlistEntry(id int) (*models.WaitlistEntry, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	if err := expireWaitlistOffers(tx, s.audit, now); err != nil {
+		return nil, err
+	}
+
+	entry, err := getWaitlistEntry(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != models.WaitlistStatusWaiting && entry.Status != models.WaitlistStatusOffered {
+		return nil, ErrWaitlistEntryNotFound
+	}
+
+	if err := setWaitlistStatus(tx, entry.ID, models.WaitlistStatusCancelled, nil, nil); err != nil {
+		return nil, err
+	}
+
+	if entry.Status == models.WaitlistStatusOffered {
+		if err := offerTimeslot(tx, s.audit, entry.TrainerID, entry.StartsAt, entry.EndsAt, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	entry.Status = models.WaitlistStatusCancelled
+	entry.OfferExpiresAt = nil
+	return entry, nil
+}
+
+func setWaitlistStatus(q querier, id int, status string, offerExpiresAt *time.Time, appointmentID *int) error {
+	var expiresAt *string
+	if offerExpiresAt != nil {
+		formatted := formatTime(*offerExpiresAt)
+		expiresAt = &formatted
+	}
+
+	query := `
+	UPDATE waitlist
+	SET status = $1, offer_expires_at = $2, appointment_id = $3
+	WHERE id = $4
+	`
+
+	_, err := q.Exec(query, status, expiresAt, appointmentID, id)
+	return err
+}
+
+// ExpireWaitlistOffers moves lapsed offers on to the next users in the queue.
+// Waitlist writes do the same, but reads leave lapsed offers in place, so the
+// server also runs it in the background.
+func (s *Store) ExpireWaitlistOffers() error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := expireWaitlistOffers(tx, s.audit, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// releaseTimeslot hands a timeslot freed by a cancellation or reschedule to
+// the trainer's waitlist.
+func releaseTimeslot(q querier, audit models.Audit, trainerID int, startsAt, endsAt time.Time) error {
+	now := time.Now()
+
+	if err := expireWaitlistOffers(q, audit, now); err != nil {
+		return err
+	}
+
+	return offerTimeslot(q, audit, trainerID, startsAt, endsAt, now)
+}
+
+// expireWaitlistOffers marks lapsed offers as expired and passes each freed
+// slot on to the next user in the queue.
+func expireWaitlistOffers(q querier, audit models.Audit, now time.Time) error {
+	query := `
+	SELECT ` + waitlistColumns + `
+	FROM waitlist
+	WHERE status = 'offered' AND offer_expires_at <= $1
+	ORDER BY id ASC
+	`
+
+	rows, err := q.Query(query, formatTime(now))
+	if err != nil {
+		return err
+	}
+
+	expired := make([]*models.WaitlistEntry, 0)
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		expired = append(expired, entry)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, entry := range expired {
+		if err := setWaitlistStatus(q, entry.ID, models.WaitlistStatusExpired, entry.OfferExpiresAt, nil); err != nil {
+			return err
+		}
+
+		if err := offerTimeslot(q, audit, entry.TrainerID, entry.StartsAt, entry.EndsAt, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// offerTimeslot walks the trainer's queue for entries overlapping a freed
+// timeslot. The first entry whose slot is now bookable is booked outright if it
+// opted into auto booking, otherwise it is offered the slot until the offer
+// expires. Slots held by an open offer are skipped.
+func offerTimeslot(q querier, audit models.Audit, trainerID int, startsAt, endsAt time.Time, now time.Time) error {
+	query := `
+	SELECT ` + waitlistColumns + `
+	FROM waitlist
+	WHERE trainer_id = $1 AND status = 'waiting'
+	AND ends_at > $2 AND starts_at < $3
+	ORDER BY id ASC
+	`
+
+	rows, err := q.Query(query, trainerID, formatTime(startsAt), formatTime(endsAt))
+	if err != nil {
+		return err
+	}
+
+	waiting := make([]*models.WaitlistEntry, 0)
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		waiting = append(waiting, entry)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, entry := range waiting {
+		if entry.StartsAt.Before(now.Add(time.Hour)) {
+			continue
+		}
+
+		held, err := hasOpenOffer(q, entry, now)
+		if err != nil {
+			return err
+		}
+
+		if held {
+			continue
+		}
+
+		if err := validateParticipants(q, entry.UserID, entry.TrainerID); err != nil {
+			if isParticipantError(err) {
+				continue
+			}
+			return err
+		}
+
+		appointment := entry.Appointment()
+
+		if err := validateAvailableTimeslot(q, appointment); err != nil {
+			if errors.Is(err, ErrTimeslotUnavailable) {
+				continue
+			}
+			return err
+		}
+
+		if entry.AutoBook {
+			_, err := bookAppointment(q, audit, appointment)
+			if isTimeslotConflict(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := setWaitlistStatus(q, entry.ID, models.WaitlistStatusBooked, nil, &appointment.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := validateTrainerTimeOff(q, appointment); err != nil {
+			if errors.Is(err, ErrTrainerTimeOff) {
+				continue
+			}
+			return err
+		}
+
+		if err := validateHolidays(q, appointment); err != nil {
+			if errors.Is(err, ErrHoliday) {
+				continue
+			}
+			return err
+		}
+
+		offerExpiresAt := entry.OfferExpiry(now)
+		if err := setWaitlistStatus(q, entry.ID, models.WaitlistStatusOffered, &offerExpiresAt, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasOpenOffer reports whether another entry currently holds an offer that
+// overlaps the entry's slot with the same trainer.
+func hasOpenOffer(q querier, entry *models.WaitlistEntry, now time.Time) (bool, error) {
+	var count int
+
+	query := `
+	SELECT COUNT(*)
+	FROM waitlist
+	WHERE trainer_id = $1 AND status = 'offered'
+	AND ends_at > $2 AND starts_at < $3
+	AND offer_expires_at > $4
+	`
+
+	if err := q.QueryRow(
+		query,
+		entry.TrainerID,
+		formatTime(entry.StartsAt),
+		formatTime(entry.EndsAt),
+		formatTime(now),
+	).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count != 0, nil
+}
+
+// validateWaitlistHold treats seats held by another user's open waitlist offer
+// as taken, so the offered user can still accept while the offer lasts. The
+// rules are the same as for scheduled appointments: an offer only shares its
+// timeslot with a group session that matches it exactly.
+func validateWaitlistHold(q querier, data *models.Appointment) error {
+	var count int
+
+	settings, err := getTrainerSettings(q, data.TrainerID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	SELECT COUNT(*)
+	FROM waitlist
+	WHERE trainer_id = $1 AND user_id != $2
+	AND status = 'offered' AND offer_expires_at > $3
+	AND ends_at > $4 AND starts_at < $5
+	AND (
+		starts_at != $6 OR ends_at != $7
+		OR (
+			SELECT COUNT(*)
+			FROM appointments
+			WHERE trainer_id = $1 AND status = 'scheduled' AND id != $8
+			AND starts_at = $6 AND ends_at = $7
+		) + (
+			SELECT COUNT(*)
+			FROM waitlist
+			WHERE trainer_id = $1 AND user_id != $2
+			AND status = 'offered' AND offer_expires_at > $3
+			AND starts_at = $6 AND ends_at = $7
+		) >= $9
+	)
+	`
+
+	buffered := bufferedTimeslot(data.Timeslot(), settings)
+
+	if err := q.QueryRow(
+		query,
+		data.TrainerID,
+		data.UserID,
+		formatTime(time.Now()),
+		formatTime(buffered.StartsAt),
+		formatTime(buffered.EndsAt),
+		formatTime(data.StartsAt),
+		formatTime(data.EndsAt),
+		data.ID,
+		settings.Seats(),
+	).Scan(&count); err != nil {
+		return err
+	}
+
+	if count != 0 {
+		return ErrTimeslotUnavailable
+	}
+
+	return nil
+}
+
+// getHeldTimeslots returns the slots of the open offers overlapping the window
+// that were made to anyone but the user, keyed by trainer ID. Like
+// validateWaitlistHold, availability counts each of them as a booking.
+func (s *Store) getHeldTimeslots(trainerIDs []int, userID int, startsAt, endsAt time.Time) (map[int][]models.Timeslot, error) {
+	held := make(map[int][]models.Timeslot, len(trainerIDs))
+	if len(trainerIDs) == 0 {
+		return held, nil
+	}
+
+	args := make([]any, 0, len(trainerIDs)+4)
+	args = append(args, formatTime(startsAt), formatTime(endsAt), formatTime(time.Now()), userID)
+
+	placeholders := make([]string, len(trainerIDs))
+	for i, trainerID := range trainerIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+5)
+		args = append(args, trainerID)
+	}
+
+	query := `
+	SELECT trainer_id, starts_at, ends_at
+	FROM waitlist
+	WHERE ends_at > $1 AND starts_at < $2
+	AND status = 'offered' AND offer_expires_at > $3
+	AND user_id != $4 AND trainer_id IN (` + strings.Join(placeholders, ", ") + `)
+	`
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var trainerID int
+		var timeslot models.Timeslot
+		if err := rows.Scan(&trainerID, &timeslot.StartsAt, &timeslot.EndsAt); err != nil {
+			return nil, err
+		}
+
+		held[trainerID] = append(held[trainerID], timeslot)
+	}
+
+	return held, rows.Err()
+}
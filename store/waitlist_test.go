@@ -0,0 +1,278 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestWaitlistEntry(userID int, startsAt time.Time, autoBook bool) *models.WaitlistEntry {
+	return &models.WaitlistEntry{
+		UserID:    userID,
+		TrainerID: 1,
+		StartsAt:  startsAt,
+		EndsAt:    startsAt.Add(time.Minute * 30),
+		AutoBook:  autoBook,
+		Status:    models.WaitlistStatusWaiting,
+	}
+}
+
+func TestWaitlist(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	tz := models.DefaultLocation()
+	monday := time.Date(2030, 7, 8, 9, 0, 0, 0, tz)
+
+	booked, err := store.CreateAppointment(&models.Appointment{
+		UserID:    1,
+		TrainerID: 1,
+		StartsAt:  monday,
+		EndsAt:    monday.Add(time.Minute * 30),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var first, second *models.WaitlistEntry
+
+	t.Run("Join waitlist for a taken slot", func(t *testing.T) {
+		first, err = store.CreateWaitlistEntry(getTestWaitlistEntry(2, monday, false))
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusWaiting, first.Status)
+
+		second, err = store.CreateWaitlistEntry(getTestWaitlistEntry(3, monday, false))
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusWaiting, second.Status)
+
+		_, err = store.CreateWaitlistEntry(getTestWaitlistEntry(2, monday, false))
+		assert.ErrorIs(t, err, ErrAlreadyWaitlisted)
+
+		entries, err := store.GetWaitlistByTrainerID(1)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("Accept without an offer", func(t *testing.T) {
+		_, err := store.AcceptWaitlistOffer(first.ID)
+		assert.ErrorIs(t, err, ErrWaitlistOfferUnavailable)
+	})
+
+	t.Run("Cancellation offers the slot to the first user", func(t *testing.T) {
+		_, err := store.CancelAppointment(booked.ID)
+		assert.NoError(t, err)
+
+		entry, err := store.GetWaitlistEntry(first.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusOffered, entry.Status)
+		assert.NotNil(t, entry.OfferExpiresAt)
+
+		entry, err = store.GetWaitlistEntry(second.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusWaiting, entry.Status)
+	})
+
+	t.Run("An open offer holds the slot", func(t *testing.T) {
+		_, err := store.CreateAppointment(&models.Appointment{
+			UserID:    9,
+			TrainerID: 1,
+			StartsAt:  monday,
+			EndsAt:    monday.Add(time.Minute * 30),
+		})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+
+		// INFO: Overlapping the held slot is just as unavailable
+		_, err = store.CreateAppointment(&models.Appointment{
+			UserID:    9,
+			TrainerID: 1,
+			StartsAt:  monday.Add(-time.Minute * 30),
+			EndsAt:    monday.Add(time.Minute * 30),
+		})
+		assert.ErrorIs(t, err, ErrTimeslotUnavailable)
+
+		entry, err := store.GetWaitlistEntry(first.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusOffered, entry.Status)
+	})
+
+	t.Run("Availability leaves out a held slot", func(t *testing.T) {
+		offersMonday := func(userID int) bool {
+			timeslots, err := store.GetTrainerAvailability(1, monday, monday.Add(time.Hour), time.Minute*30, userID, nil)
+			assert.NoError(t, err)
+			for _, timeslot := range *timeslots {
+				if timeslot.StartsAt.Equal(monday) {
+					return true
+				}
+			}
+			return false
+		}
+
+		assert.False(t, offersMonday(0))
+		assert.False(t, offersMonday(9))
+
+		// INFO: The offered user still sees the slot they can accept
+		assert.True(t, offersMonday(2))
+
+		available, err := store.GetAvailability([]int{1}, monday, monday.Add(time.Hour), time.Minute*30, 0, nil, 0)
+		assert.NoError(t, err)
+		for _, timeslot := range available {
+			assert.False(t, timeslot.StartsAt.Equal(monday))
+		}
+	})
+
+	t.Run("Expired offer moves to the next user", func(t *testing.T) {
+		expiredAt := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+		if _, err := store.DB.Exec(`UPDATE waitlist SET offer_expires_at = $1 WHERE id = $2`, expiredAt, first.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := store.AcceptWaitlistOffer(first.ID)
+		assert.ErrorIs(t, err, ErrWaitlistOfferExpired)
+
+		entry, err := store.GetWaitlistEntry(first.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusExpired, entry.Status)
+
+		entry, err = store.GetWaitlistEntry(second.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusOffered, entry.Status)
+	})
+
+	t.Run("Accept offer", func(t *testing.T) {
+		appointment, err := store.AcceptWaitlistOffer(second.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, appointment.UserID)
+		assert.Equal(t, monday, appointment.StartsAt)
+
+		entry, err := store.GetWaitlistEntry(second.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusBooked, entry.Status)
+		assert.Equal(t, appointment.ID, *entry.AppointmentID)
+	})
+
+	t.Run("A lapsed offer no longer holds the slot", func(t *testing.T) {
+		startsAt := monday.Add(time.Hour * 6)
+		appointment, err := store.CreateAppointment(&models.Appointment{
+			UserID:    1,
+			TrainerID: 1,
+			StartsAt:  startsAt,
+			EndsAt:    startsAt.Add(time.Minute * 30),
+		})
+		assert.NoError(t, err)
+
+		entry, err := store.CreateWaitlistEntry(getTestWaitlistEntry(7, startsAt, false))
+		assert.NoError(t, err)
+
+		_, err = store.CancelAppointment(appointment.ID)
+		assert.NoError(t, err)
+
+		expiredAt := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+		if _, err := store.DB.Exec(`UPDATE waitlist SET offer_expires_at = $1 WHERE id = $2`, expiredAt, entry.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		booked, err := store.CreateAppointment(&models.Appointment{
+			UserID:    8,
+			TrainerID: 1,
+			StartsAt:  startsAt,
+			EndsAt:    startsAt.Add(time.Minute * 30),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 8, booked.UserID)
+	})
+
+	t.Run("Reschedule automatically books the next user", func(t *testing.T) {
+		startsAt := monday.Add(time.Hour)
+		appointment, err := store.CreateAppointment(&models.Appointment{
+			UserID:    1,
+			TrainerID: 1,
+			StartsAt:  startsAt,
+			EndsAt:    startsAt.Add(time.Minute * 30),
+		})
+		assert.NoError(t, err)
+
+		entry, err := store.CreateWaitlistEntry(getTestWaitlistEntry(4, startsAt, true))
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusWaiting, entry.Status)
+
+		_, err = store.RescheduleAppointment(appointment.ID, startsAt.Add(time.Hour), startsAt.Add(time.Hour).Add(time.Minute*30))
+		assert.NoError(t, err)
+
+		entry, err = store.GetWaitlistEntry(entry.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusBooked, entry.Status)
+
+		autoBooked, err := store.GetAppointmentByID(*entry.AppointmentID)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, autoBooked.UserID)
+		assert.Equal(t, startsAt, autoBooked.StartsAt)
+	})
+
+	t.Run("Joining a free slot offers it straight away", func(t *testing.T) {
+		startsAt := monday.Add(time.Hour * 4)
+
+		entry, err := store.CreateWaitlistEntry(getTestWaitlistEntry(5, startsAt, false))
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusOffered, entry.Status)
+
+		next, err := store.CreateWaitlistEntry(getTestWaitlistEntry(6, startsAt, false))
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusWaiting, next.Status)
+
+		t.Run("Leaving with an open offer passes it on", func(t *testing.T) {
+			cancelled, err := store.CancelWaitlistEntry(entry.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, models.WaitlistStatusCancelled, cancelled.Status)
+
+			next, err = store.GetWaitlistEntry(next.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, models.WaitlistStatusOffered, next.Status)
+
+			_, err = store.CancelWaitlistEntry(entry.ID)
+			assert.ErrorIs(t, err, ErrWaitlistEntryNotFound)
+		})
+	})
+
+	t.Run("Reads leave a lapsed offer for the background expiry", func(t *testing.T) {
+		startsAt := monday.Add(time.Hour * 8)
+
+		entry, err := store.CreateWaitlistEntry(getTestWaitlistEntry(9, startsAt, false))
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusOffered, entry.Status)
+
+		next, err := store.CreateWaitlistEntry(getTestWaitlistEntry(10, startsAt, true))
+		assert.NoError(t, err)
+
+		expiredAt := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+		if _, err := store.DB.Exec(`UPDATE waitlist SET offer_expires_at = $1 WHERE id = $2`, expiredAt, entry.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		// INFO: Reads never book the next user in the queue
+		entry, err = store.GetWaitlistEntry(entry.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusOffered, entry.Status)
+
+		_, err = store.GetWaitlistByTrainerID(1)
+		assert.NoError(t, err)
+
+		next, err = store.GetWaitlistEntry(next.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusWaiting, next.Status)
+
+		assert.NoError(t, store.ExpireWaitlistOffers())
+
+		entry, err = store.GetWaitlistEntry(entry.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusExpired, entry.Status)
+
+		next, err = store.GetWaitlistEntry(next.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WaitlistStatusBooked, next.Status)
+	})
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"future-app/models"
+	"time"
+)
+
+func (s *Store) GetTrainerWorkingHours(trainerID int) (models.WeeklySchedule, error) {
+	return getTrainerWorkingHours(s.DB, trainerID)
+}
+
+func getTrainerWorkingHours(q querier, trainerID int) (models.WeeklySchedule, error) {
+	query := `
+	SELECT weekday, start_time, end_time
+	FROM trainer_working_hours
+	WHERE trainer_id = $1
+	ORDER BY weekday ASC, start_time ASC
+	`
+
+	rows, err := q.Query(query, trainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	schedule := make(models.WeeklySchedule, 0)
+
+	for rows.Next() {
+		var workingHours models.WorkingHours
+		var weekday int
+		if err := rows.Scan(
+			&weekday,
+			&workingHours.StartTime,
+			&workingHours.EndTime,
+		); err != nil {
+			return nil, err
+		}
+
+		workingHours.Weekday = time.Weekday(weekday)
+		schedule = append(schedule, workingHours)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(schedule) == 0 {
+		return models.DefaultWeeklySchedule(), nil
+	}
+
+	return schedule, nil
+}
+
+func (s *Store) SetTrainerWorkingHours(trainerID int, schedule models.WeeklySchedule) (models.WeeklySchedule, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM trainer_working_hours WHERE trainer_id = $1`, trainerID); err != nil {
+		return nil, err
+	}
+
+	query := `
+	INSERT INTO trainer_working_hours (trainer_id, weekday, start_time, end_time)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	for _, workingHours := range schedule {
+		if _, err := tx.Exec(
+			query,
+			trainerID,
+			int(workingHours.Weekday),
+			workingHours.StartTime,
+			workingHours.EndTime,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"future-app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrainerWorkingHours(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	schedule := models.WeeklySchedule{
+		{Weekday: time.Monday, StartTime: "06:00", EndTime: "10:00"},
+		{Weekday: time.Monday, StartTime: "16:00", EndTime: "20:00"},
+		{Weekday: time.Saturday, StartTime: "07:00", EndTime: "09:00"},
+	}
+
+	t.Run("Default working hours", func(t *testing.T) {
+		workingHours, err := store.GetTrainerWorkingHours(1)
+		assert.NoError(t, err)
+		assert.Equal(t, models.DefaultWeeklySchedule(), workingHours)
+	})
+
+	t.Run("Set working hours", func(t *testing.T) {
+		_, err := store.SetTrainerWorkingHours(1, schedule)
+		assert.NoError(t, err)
+
+		workingHours, err := store.GetTrainerWorkingHours(1)
+		assert.NoError(t, err)
+		assert.Equal(t, schedule, workingHours)
+	})
+
+	t.Run("Replace working hours", func(t *testing.T) {
+		_, err := store.SetTrainerWorkingHours(1, schedule[2:])
+		assert.NoError(t, err)
+
+		workingHours, err := store.GetTrainerWorkingHours(1)
+		assert.NoError(t, err)
+		assert.Equal(t, schedule[2:], workingHours)
+	})
+
+	t.Run("Other trainers keep default working hours", func(t *testing.T) {
+		workingHours, err := store.GetTrainerWorkingHours(2)
+		assert.NoError(t, err)
+		assert.Equal(t, models.DefaultWeeklySchedule(), workingHours)
+	})
+}
+
+func TestGetTrainerAvailabilityWithWorkingHours(t *testing.T) {
+	store, err := setupStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	_, err = store.SetTrainerWorkingHours(1, models.WeeklySchedule{
+		{Weekday: time.Monday, StartTime: "06:00", EndTime: "07:00"},
+		{Weekday: time.Monday, StartTime: "18:00", EndTime: "19:00"},
+		{Weekday: time.Saturday, StartTime: "07:00", EndTime: "08:00"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tz := models.DefaultLocation()
+	startsAt := time.Date(2030, 7, 8, 0, 0, 0, 0, tz) // Monday midnight
+	endsAt := time.Date(2030, 7, 15, 0, 0, 0, 0, tz)  // Next Monday midnight
+
+	timeslots, err := store.GetTrainerAvailability(1, startsAt, endsAt, time.Minute*30, 0, nil)
+	assert.NoError(t, err)
+
+	expected := []models.Timeslot{
+		models.NewTimeslot(startsAt.Add(time.Hour*6), startsAt.Add(time.Hour*6).Add(time.Minute*30)),
+		models.NewTimeslot(startsAt.Add(time.Hour*6).Add(time.Minute*30), startsAt.Add(time.Hour*7)),
+		models.NewTimeslot(startsAt.Add(time.Hour*18), startsAt.Add(time.Hour*18).Add(time.Minute*30)),
+		models.NewTimeslot(startsAt.Add(time.Hour*18).Add(time.Minute*30), startsAt.Add(time.Hour*19)),
+		models.NewTimeslot(startsAt.AddDate(0, 0, 5).Add(time.Hour*7), startsAt.AddDate(0, 0, 5).Add(time.Hour*7).Add(time.Minute*30)),
+		models.NewTimeslot(startsAt.AddDate(0, 0, 5).Add(time.Hour*7).Add(time.Minute*30), startsAt.AddDate(0, 0, 5).Add(time.Hour*8)),
+	}
+
+	actual := make([]models.Timeslot, 0, len(*timeslots))
+	for _, timeslot := range *timeslots {
+		assert.Equal(t, 1, timeslot.Seats)
+		actual = append(actual, timeslot.Timeslot)
+	}
+	assert.Equal(t, expected, actual)
+}